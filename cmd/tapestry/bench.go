@@ -0,0 +1,96 @@
+/*
+ *  Brown University, CS138, Spring 2022
+ *
+ *  Purpose: implements the bench subcommand, which measures the throughput
+ *  and latency of a tapestry under a configurable workload, either through
+ *  a running node or through a cluster started in the process.
+ */
+
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	tapestry "tapestry/pkg"
+)
+
+func runBench(args []string) int {
+	flags := flag.NewFlagSet("bench", flag.ContinueOnError)
+	var node string
+	var nodes int
+	var distribution string
+	var logLevel string
+	var asJSON bool
+	config := tapestry.BenchConfig{Hops: true}
+
+	flags.StringVar(&node, "node", "", "The address of a running node to benchmark through. If left blank, a cluster is started in the process.")
+	flags.IntVar(&nodes, "n", 5, "How many nodes the cluster started in the process has.")
+	flags.IntVar(&config.Keys, "keys", 1000, "How many distinct keys the workload uses.")
+	flags.StringVar(&distribution, "dist", "uniform", "How keys are drawn: uniform or zipf.")
+	flags.Float64Var(&config.Zipf, "zipf", 1.1, "The exponent of the Zipf distribution, over 1. Higher makes popular keys more popular.")
+	flags.IntVar(&config.ValueSize, "value-size", 1024, "The size in bytes of stored values.")
+	flags.Float64Var(&config.ReadRatio, "read-ratio", 0.9, "The fraction of operations that read a key rather than store it.")
+	flags.Float64Var(&config.LookupRatio, "lookup-ratio", 0, "The fraction of reads that are lookups rather than gets.")
+	flags.IntVar(&config.Concurrency, "concurrency", 8, "How many operations are in flight at once.")
+	flags.DurationVar(&config.Duration, "duration", tapestry.REPUBLISH, "How long to issue operations for.")
+	flags.BoolVar(&config.Hops, "hops", config.Hops, "Measure how many hops keys take to route to their roots, in a cluster started in the process.")
+	flags.Int64Var(&config.Seed, "seed", 1, "Seeds the choice of operations and keys.")
+	flags.StringVar(&logLevel, "log-level", "error", "The log level of the nodes: error, info, debug or trace.")
+	flags.BoolVar(&asJSON, "json", false, "Print the report as JSON.")
+	if err := flags.Parse(args); err != nil || flags.NArg() != 0 || nodes < 1 {
+		printUsage()
+		return exitUsage
+	}
+	switch distribution {
+	case "uniform":
+		config.Zipf = 0
+	case "zipf":
+	default:
+		fmt.Fprintf(os.Stderr, "Error: unknown key distribution %v, expected uniform or zipf\n", distribution)
+		return exitUsage
+	}
+	if _, err := tapestry.SetLogLevel(logLevel); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return exitUsage
+	}
+
+	var targets []tapestry.BenchTarget
+	if node != "" {
+		client, err := tapestry.Connect(node)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return exitError
+		}
+		targets = append(targets, client)
+	} else {
+		cluster, err := tapestry.StartCluster(nodes)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error starting the cluster: %v\n", err)
+			return exitError
+		}
+		defer cluster.Down()
+		for _, node := range cluster.Nodes() {
+			targets = append(targets, node)
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "Benchmarking %v keys for %v with %v workers\n", config.Keys, config.Duration, config.Concurrency)
+	report, err := tapestry.Bench(targets, config)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return exitError
+	}
+	if asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(report); err != nil {
+			fmt.Fprintf(os.Stderr, "Error encoding result: %v\n", err)
+			return exitError
+		}
+		return exitOK
+	}
+	fmt.Print(report)
+	return exitOK
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"encoding/json"
+	tapestry "tapestry/pkg"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// test the bench subcommand, through a running node and through a cluster started in the process,
+// and its exit codes
+func TestBenchCommand(t *testing.T) {
+	node, err := tapestry.Start(tapestry.MakeID("1"), 0, "")
+	assert.Equal(t, err, nil)
+	defer tapestry.KillTapestries(node)
+	level := tapestry.LogLevel()
+	defer tapestry.SetLogLevel(level)
+
+	tests := []struct {
+		args   []string
+		code   int
+		output string
+	}{
+		{[]string{"extra"}, exitUsage, ""},
+		{[]string{"--unknown"}, exitUsage, ""},
+		{[]string{"-n", "0"}, exitUsage, ""},
+		{[]string{"--dist", "pareto"}, exitUsage, ""},
+		{[]string{"--log-level", "loud"}, exitUsage, ""},
+		{[]string{"--node", "127.0.0.1:1"}, exitError, ""},
+		{[]string{"--node", node.Addr(), "--keys", "0"}, exitError, ""},
+		{[]string{"--node", node.Addr(), "--dist", "zipf", "--zipf", "1"}, exitError, ""},
+		{[]string{"--node", node.Addr(), "--keys", "5", "--duration", "100ms"}, exitOK, "get"},
+		{[]string{"-n", "2", "--keys", "5", "--duration", "100ms"}, exitOK, "hops"},
+	}
+	for _, test := range tests {
+		code, output := run(t, "bench", test.args...)
+		assert.Equal(t, code, test.code, "%v", test.args)
+		assert.Contains(t, output, test.output, "%v", test.args)
+	}
+
+	code, output := run(t, "bench", "--node", node.Addr(), "--keys", "5", "--read-ratio", "1", "--duration", "100ms", "--json")
+	assert.Equal(t, code, exitOK)
+	var report tapestry.BenchReport
+	assert.Equal(t, json.Unmarshal([]byte(output), &report), nil)
+	assert.Equal(t, report.Operations[tapestry.BenchGet].Count > 0, true)
+	assert.Equal(t, report.Operations[tapestry.BenchGet].Errors, 0)
+	assert.Equal(t, len(report.Hops), 0)
+}
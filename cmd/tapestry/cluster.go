@@ -0,0 +1,236 @@
+/*
+ *  Brown University, CS138, Spring 2022
+ *
+ *  Purpose: implements the cluster subcommand, which runs a cluster of nodes
+ *  on localhost in one process. The process started by `cluster up` serves
+ *  a control socket in the cluster directory, through which later `cluster`
+ *  commands add, kill and list its nodes, or take it down.
+ */
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	tapestry "tapestry/pkg"
+	"text/tabwriter"
+)
+
+// clusterSocket is the name of the control socket in the cluster directory
+const clusterSocket = "control.sock"
+
+// Flags shared by the cluster commands
+type clusterFlags struct {
+	flags *flag.FlagSet
+	dir   string
+	json  bool
+}
+
+func newClusterFlags(name string) *clusterFlags {
+	c := &clusterFlags{flags: flag.NewFlagSet("cluster "+name, flag.ContinueOnError)}
+	c.flags.StringVar(&c.dir, "dir", ".tapestry-cluster", "The directory holding the control socket of the cluster.")
+	c.flags.BoolVar(&c.json, "json", false, "Print the result as JSON.")
+	return c
+}
+
+func runCluster(args []string) int {
+	if len(args) == 0 {
+		printUsage()
+		return exitUsage
+	}
+	switch args[0] {
+	case "up":
+		return runClusterUp(args[1:])
+	case "status", "add", "down":
+		c := newClusterFlags(args[0])
+		if err := c.flags.Parse(args[1:]); err != nil || c.flags.NArg() != 0 {
+			printUsage()
+			return exitUsage
+		}
+		return c.control(args[0], "")
+	case "kill":
+		c := newClusterFlags("kill")
+		if err := c.flags.Parse(args[1:]); err != nil || c.flags.NArg() != 1 {
+			printUsage()
+			return exitUsage
+		}
+		return c.control("kill", c.flags.Arg(0))
+	default:
+		printUsage()
+		return exitUsage
+	}
+}
+
+// runClusterUp starts the cluster and serves its control socket until it is taken down, or the
+// process is interrupted
+func runClusterUp(args []string) int {
+	c := newClusterFlags("up")
+	var n int
+	var logLevel string
+	c.flags.IntVar(&n, "n", 5, "How many nodes to start.")
+	c.flags.StringVar(&logLevel, "log-level", "error", "The log level of the nodes: error, info, debug or trace.")
+	if err := c.flags.Parse(args); err != nil || c.flags.NArg() != 0 || n < 1 {
+		printUsage()
+		return exitUsage
+	}
+	if _, err := tapestry.SetLogLevel(logLevel); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return exitUsage
+	}
+
+	if err := os.MkdirAll(c.dir, 0755); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return exitError
+	}
+	socket := filepath.Join(c.dir, clusterSocket)
+	if conn, err := net.Dial("unix", socket); err == nil {
+		conn.Close()
+		fmt.Fprintf(os.Stderr, "Error: a cluster is already running in %v\n", c.dir)
+		return exitError
+	}
+	os.Remove(socket)
+	lis, err := net.Listen("unix", socket)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return exitError
+	}
+	defer os.Remove(socket)
+
+	cluster, err := tapestry.StartCluster(n)
+	if err != nil {
+		lis.Close()
+		fmt.Fprintf(os.Stderr, "Error starting the cluster: %v\n", err)
+		return exitError
+	}
+	if code := c.print(clusterNodes(cluster.Nodes()...)); code != exitOK {
+		cluster.Down()
+		return code
+	}
+
+	down := make(chan bool, 1)
+	server := &http.Server{Handler: clusterHandler(cluster, down)}
+	go server.Serve(lis)
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGTERM, syscall.SIGINT)
+	select {
+	case <-down:
+	case <-signals:
+	}
+	server.Close()
+	cluster.Down()
+	return exitOK
+}
+
+// Serves the control requests of the other cluster commands. Sends on down once the cluster is to
+// be taken down.
+func clusterHandler(cluster *tapestry.Cluster, down chan bool) http.Handler {
+	reply := func(w http.ResponseWriter, nodes []tapestry.RemoteNode) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(nodes)
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/status", func(w http.ResponseWriter, r *http.Request) {
+		reply(w, clusterNodes(cluster.Nodes()...))
+	})
+	mux.HandleFunc("/add", func(w http.ResponseWriter, r *http.Request) {
+		node, err := cluster.Add()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		reply(w, clusterNodes(node))
+	})
+	mux.HandleFunc("/kill", func(w http.ResponseWriter, r *http.Request) {
+		node, err := cluster.Kill(r.URL.Query().Get("id"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		reply(w, clusterNodes(node))
+	})
+	mux.HandleFunc("/down", func(w http.ResponseWriter, r *http.Request) {
+		reply(w, clusterNodes(cluster.Nodes()...))
+		select {
+		case down <- true:
+		default:
+		}
+	})
+	return mux
+}
+
+// The IDs and addresses of nodes
+func clusterNodes(nodes ...*tapestry.Node) []tapestry.RemoteNode {
+	remotes := make([]tapestry.RemoteNode, len(nodes))
+	for i, node := range nodes {
+		remotes[i] = node.Node
+	}
+	return remotes
+}
+
+// Sends a command to the process running the cluster, and prints the nodes it affected
+func (c *clusterFlags) control(command string, id string) int {
+	socket := filepath.Join(c.dir, clusterSocket)
+	client := http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, "unix", socket)
+		},
+	}}
+	url := "http://cluster/" + command
+	if id != "" {
+		url += "?id=" + id
+	}
+	rsp, err := client.Post(url, "", nil)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: no cluster is running in %v: %v\n", c.dir, err)
+		return exitError
+	}
+	defer rsp.Body.Close()
+	body, err := ioutil.ReadAll(rsp.Body)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return exitError
+	}
+	if rsp.StatusCode == http.StatusNotFound {
+		fmt.Fprintf(os.Stderr, "Error: %s", body)
+		return exitNotFound
+	} else if rsp.StatusCode != http.StatusOK {
+		fmt.Fprintf(os.Stderr, "Error: %s", body)
+		return exitError
+	}
+
+	var nodes []tapestry.RemoteNode
+	if err := json.Unmarshal(body, &nodes); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return exitError
+	}
+	return c.print(nodes)
+}
+
+// Prints the IDs and addresses of nodes, as JSON if requested
+func (c *clusterFlags) print(nodes []tapestry.RemoteNode) int {
+	if c.json {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(nodes); err != nil {
+			fmt.Fprintf(os.Stderr, "Error encoding result: %v\n", err)
+			return exitError
+		}
+		return exitOK
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for _, node := range nodes {
+		fmt.Fprintf(w, "%v\t%v\n", node.ID, node.Address)
+	}
+	w.Flush()
+	return exitOK
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	tapestry "tapestry/pkg"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Wait up to ten seconds for the cluster in dir to answer on its control socket. Does not go through
+// the cluster commands, as they print to stdout, which the cluster may still be printing to.
+func waitForCluster(dir string) bool {
+	client := http.Client{Transport: &http.Transport{
+		Dial: func(network, addr string) (net.Conn, error) {
+			return net.Dial("unix", filepath.Join(dir, clusterSocket))
+		},
+	}}
+	for deadline := time.Now().Add(10 * time.Second); time.Now().Before(deadline); time.Sleep(50 * time.Millisecond) {
+		rsp, err := client.Get("http://cluster/status")
+		if err == nil {
+			rsp.Body.Close()
+			return rsp.StatusCode == http.StatusOK
+		}
+	}
+	return false
+}
+
+// test the cluster subcommand rejects missing and extra arguments with the usage exit code
+func TestClusterUsage(t *testing.T) {
+	tests := [][]string{
+		{},
+		{"start"},
+		{"up", "extra"},
+		{"up", "-n", "0"},
+		{"up", "--log-level", "loud"},
+		{"status", "extra"},
+		{"add", "--unknown"},
+		{"kill"},
+		{"kill", "1", "2"},
+		{"down", "extra"},
+	}
+	for _, args := range tests {
+		code, _ := run(t, "cluster", args...)
+		assert.Equal(t, code, exitUsage, "%v", args)
+	}
+}
+
+// test the cluster commands control a running cluster, and their exit codes
+func TestClusterCommands(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "cluster")
+	defer os.RemoveAll(dir)
+	level := tapestry.LogLevel()
+	defer tapestry.SetLogLevel(level)
+
+	code, _ := run(t, "cluster", "status", "--dir", dir)
+	assert.Equal(t, code, exitError)
+
+	exited := make(chan int)
+	go func() {
+		exited <- runCluster([]string{"up", "--dir", dir, "-n", "2"})
+	}()
+	assert.Equal(t, waitForCluster(dir), true)
+
+	code, _ = run(t, "cluster", "up", "--dir", dir, "-n", "1")
+	assert.Equal(t, code, exitError)
+
+	code, output := run(t, "cluster", "status", "--dir", dir, "--json")
+	assert.Equal(t, code, exitOK)
+	var nodes []tapestry.RemoteNode
+	assert.Equal(t, json.Unmarshal([]byte(output), &nodes), nil)
+	assert.Equal(t, len(nodes), 2)
+
+	// The nodes run in this process, so the output of add also holds what the new node logs
+	code, _ = run(t, "cluster", "add", "--dir", dir)
+	assert.Equal(t, code, exitOK)
+	code, output = run(t, "cluster", "status", "--dir", dir)
+	assert.Equal(t, code, exitOK)
+	assert.Equal(t, strings.Count(output, "\n"), 3)
+	assert.Contains(t, output, nodes[0].ID.String()+"  "+nodes[0].Address+"\n")
+
+	code, output = run(t, "cluster", "kill", "--dir", dir, nodes[0].ID.String())
+	assert.Equal(t, code, exitOK)
+	assert.Contains(t, output, nodes[0].Address)
+	code, _ = run(t, "cluster", "kill", "--dir", dir, nodes[0].ID.String())
+	assert.Equal(t, code, exitNotFound)
+
+	code, output = run(t, "cluster", "down", "--dir", dir)
+	assert.Equal(t, code, exitOK)
+	assert.Contains(t, output, nodes[1].Address)
+	assert.NotContains(t, output, nodes[0].Address)
+	select {
+	case code := <-exited:
+		assert.Equal(t, code, exitOK)
+	case <-time.After(10 * time.Second):
+		t.Fatal("the cluster did not go down")
+	}
+	_, err := os.Stat(filepath.Join(dir, clusterSocket))
+	assert.Equal(t, os.IsNotExist(err), true)
+}
@@ -0,0 +1,471 @@
+/*
+ *  Brown University, CS138, Spring 2022
+ *
+ *  Purpose: implements non-interactive subcommands that run a single
+ *  operation against a running Tapestry node and exit, for scripting.
+ */
+
+package main
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	tapestry "tapestry/pkg"
+	"time"
+)
+
+// Exit codes of the tapestry binary
+const (
+	exitOK       = 0 // The command succeeded
+	exitError    = 1 // The command failed
+	exitUsage    = 2 // The command was invoked incorrectly
+	exitNotFound = 3 // The requested key has no replicas
+	exitConflict = 4 // A conditional put found another version of the key
+)
+
+// adminTokenEnv is the environment variable holding the admin token, when no flag gives it
+const adminTokenEnv = "TAPESTRY_ADMIN_TOKEN"
+
+func printUsage() {
+	fmt.Fprintln(os.Stderr, "Usage:")
+	fmt.Fprintln(os.Stderr, "  tapestry [shell] [flags]                          Start a node and the interactive shell")
+	fmt.Fprintln(os.Stderr, "      [--config file]                               Reading the configuration from a YAML file, which flags override")
+	fmt.Fprintln(os.Stderr, "      [--daemon] [--pid-file file]                  Without the shell, leaving on SIGTERM (SIGHUP reloads the log level)")
+	fmt.Fprintln(os.Stderr, "  tapestry config validate [--config file] [flags]  Check a configuration without starting a node")
+	fmt.Fprintln(os.Stderr, "  tapestry cluster up [-n 5] [--dir d]              Run a cluster of nodes on localhost in this process, until taken down")
+	fmt.Fprintln(os.Stderr, "  tapestry cluster status|add|down [--dir d]        List, add a node to, or take down the cluster running in d")
+	fmt.Fprintln(os.Stderr, "  tapestry cluster kill [--dir d] <id>              Crash the node of the cluster whose ID starts with id")
+	fmt.Fprintln(os.Stderr, "  tapestry bench [--node host:port | -n 5]          Measure latency and throughput under a workload, through a node or an in-process cluster")
+	fmt.Fprintln(os.Stderr, "      [--dist uniform|zipf] [--read-ratio 0.9]      See tapestry bench -h for the workload flags")
+	fmt.Fprintln(os.Stderr, "  tapestry put --node host:port <key> [value|-]     Store a value (read from stdin if omitted or -)")
+	fmt.Fprintln(os.Stderr, "      [--if-version v]                              Only if v is the newest version (0: key must not exist)")
+	fmt.Fprintln(os.Stderr, "      [--ttl duration]                              Expire the value after the duration")
+	fmt.Fprintln(os.Stderr, "  tapestry get --node host:port <key>               Fetch a value")
+	fmt.Fprintln(os.Stderr, "  tapestry stat --node host:port <key>              Fetch the metadata of a value")
+	fmt.Fprintln(os.Stderr, "  tapestry lookup --node host:port <key>            List the replicas advertising a key")
+	fmt.Fprintln(os.Stderr, "  tapestry table --node host:port                   Print the routing table of a node")
+	fmt.Fprintln(os.Stderr, "  tapestry keys --node host:port [prefix]           List the keys advertised in the tapestry")
+	fmt.Fprintln(os.Stderr, "  tapestry published --node host:port [--failing]   Show how each key stored on a node is being published")
+	fmt.Fprintln(os.Stderr, "  tapestry state --node host:port [--locations]     Print the routing table and backpointers of a node")
+	fmt.Fprintln(os.Stderr, "  tapestry topology --node host:port                Crawl the mesh and print its nodes and routing links")
+	fmt.Fprintln(os.Stderr, "      [--format dot|json] [--locations]             As a Graphviz graph (the default) or JSON")
+	fmt.Fprintln(os.Stderr, "  tapestry admin --node host:port <command>         Run an admin command against the admin service of a node:")
+	fmt.Fprintln(os.Stderr, "      [--token t] [--ca file]                       table, backpointers, locations, blobs, log-level <level>, leave, drain")
+	fmt.Fprintln(os.Stderr, "      [--drain-timeout d]                           How long to wait for drain to hand off the node's blobs and keys")
+	fmt.Fprintln(os.Stderr, "")
+	fmt.Fprintln(os.Stderr, "Subcommands accept --json to print machine-readable output, in which get encodes the value in base64. Exit codes:")
+	fmt.Fprintln(os.Stderr, "  0 success, 1 error, 2 usage error, 3 key not found, 4 version conflict")
+}
+
+// Collects repeated key=value flags into a map
+type tagFlag map[string]string
+
+func (t tagFlag) String() string {
+	return fmt.Sprint(map[string]string(t))
+}
+
+func (t tagFlag) Set(value string) error {
+	parts := strings.SplitN(value, "=", 2)
+	if len(parts) != 2 {
+		return fmt.Errorf("expected key=value, got %v", value)
+	}
+	t[parts[0]] = parts[1]
+	return nil
+}
+
+// Flags shared by the subcommands that talk to a running node
+type remoteFlags struct {
+	flags *flag.FlagSet
+	node  string
+	json  bool
+}
+
+func newRemoteFlags(name string) *remoteFlags {
+	r := &remoteFlags{flags: flag.NewFlagSet(name, flag.ContinueOnError)}
+	r.flags.StringVar(&r.node, "node", "", "The address of the Tapestry node to run the command against.")
+	r.flags.StringVar(&r.node, "n", "", "The address of the Tapestry node to run the command against. (shorthand)")
+	r.flags.BoolVar(&r.json, "json", false, "Print the result as JSON.")
+	return r
+}
+
+// Parses the arguments and connects to the node. Returns a non-zero exit code on failure.
+func (r *remoteFlags) connect(args []string, minArgs int, maxArgs int) (*tapestry.Client, int) {
+	if err := r.flags.Parse(args); err != nil {
+		return nil, exitUsage
+	}
+	if r.node == "" || r.flags.NArg() < minArgs || r.flags.NArg() > maxArgs {
+		printUsage()
+		return nil, exitUsage
+	}
+	client, err := tapestry.Connect(r.node)
+	if err != nil {
+		return nil, r.fail(err)
+	}
+	return client, exitOK
+}
+
+// Prints the result, as JSON if requested, otherwise as the provided text
+func (r *remoteFlags) print(result interface{}, text string) int {
+	if !r.json {
+		fmt.Print(text)
+		return exitOK
+	}
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(result); err != nil {
+		fmt.Fprintf(os.Stderr, "Error encoding result: %v\n", err)
+		return exitError
+	}
+	return exitOK
+}
+
+// Reports an error and returns the matching exit code
+func (r *remoteFlags) fail(err error) int {
+	if r.json {
+		json.NewEncoder(os.Stdout).Encode(map[string]string{"error": err.Error()})
+	}
+	fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+
+	var notFound *tapestry.NotFoundError
+	if errors.As(err, &notFound) {
+		return exitNotFound
+	}
+	var conflict *tapestry.VersionConflictError
+	if errors.As(err, &conflict) {
+		return exitConflict
+	}
+	return exitError
+}
+
+func runPut(args []string) int {
+	r := newRemoteFlags("put")
+	contentType := r.flags.String("content-type", "", "The content type to record in the metadata of the value.")
+	tags := make(tagFlag)
+	r.flags.Var(tags, "tag", "A key=value tag to record in the metadata of the value. May be repeated.")
+	ifVersion := r.flags.String("if-version", "", "Only store if this is the newest version of the key; 0 if the key must not exist.")
+	ttl := r.flags.Duration("ttl", 0, "Expire the value after this duration, such as 30m. Never expires if 0.")
+	client, code := r.connect(args, 1, 2)
+	if client == nil {
+		return code
+	}
+
+	key := r.flags.Arg(0)
+	var value []byte
+	if r.flags.NArg() == 1 || r.flags.Arg(1) == "-" {
+		input, err := ioutil.ReadAll(os.Stdin)
+		if err != nil {
+			return r.fail(err)
+		}
+		value = input
+	} else {
+		value = []byte(r.flags.Arg(1))
+	}
+
+	opts := []tapestry.StoreOption{tapestry.WithContentType(*contentType), tapestry.WithTags(tags), tapestry.WithTTL(*ttl)}
+	if *ifVersion == "" {
+		if err := client.Store(key, value, opts...); err != nil {
+			return r.fail(err)
+		}
+		result := map[string]interface{}{"key": key, "size": len(value)}
+		return r.print(result, fmt.Sprintf("Successfully stored %v bytes at key (%v)\n", len(value), key))
+	}
+
+	expected, err := tapestry.ParseVersion(*ifVersion)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return exitUsage
+	}
+	version, err := client.StoreIf(key, value, expected, opts...)
+	if err != nil {
+		return r.fail(err)
+	}
+	result := map[string]interface{}{"key": key, "size": len(value), "version": version}
+	return r.print(result, fmt.Sprintf("Successfully stored %v bytes at key (%v) with version %v\n", len(value), key, version))
+}
+
+func runGet(args []string) int {
+	r := newRemoteFlags("get")
+	client, code := r.connect(args, 1, 1)
+	if client == nil {
+		return code
+	}
+
+	key := r.flags.Arg(0)
+	value, err := client.Get(key)
+	if err != nil {
+		return r.fail(err)
+	}
+	// Values may be binary, which JSON strings cannot hold
+	result := map[string]interface{}{"key": key, "value": base64.StdEncoding.EncodeToString(value)}
+	return r.print(result, string(value))
+}
+
+func runStat(args []string) int {
+	r := newRemoteFlags("stat")
+	client, code := r.connect(args, 1, 1)
+	if client == nil {
+		return code
+	}
+
+	key := r.flags.Arg(0)
+	metadata, err := client.Stat(key)
+	if err != nil {
+		return r.fail(err)
+	}
+	result := map[string]interface{}{"key": key, "metadata": metadata}
+	return r.print(result, fmt.Sprintf("%v: %v\n", key, metadata))
+}
+
+func runLookup(args []string) int {
+	r := newRemoteFlags("lookup")
+	client, code := r.connect(args, 1, 1)
+	if client == nil {
+		return code
+	}
+
+	key := r.flags.Arg(0)
+	replicas, err := client.LookupVersions(key)
+	if err != nil {
+		return r.fail(err)
+	}
+	if len(replicas) == 0 {
+		return r.fail(&tapestry.NotFoundError{Key: key})
+	}
+
+	nodes := make([]map[string]interface{}, len(replicas))
+	text := ""
+	for i, replica := range replicas {
+		nodes[i] = map[string]interface{}{"id": replica.Node.ID, "address": replica.Node.Address, "version": replica.Version}
+		text += fmt.Sprintf("%v %v %v\n", replica.Node.ID, replica.Node.Address, replica.Version)
+	}
+	result := map[string]interface{}{"key": key, "replicas": nodes}
+	return r.print(result, text)
+}
+
+func runTable(args []string) int {
+	r := newRemoteFlags("table")
+	client, code := r.connect(args, 0, 0)
+	if client == nil {
+		return code
+	}
+
+	entries, err := client.RoutingTable()
+	if err != nil {
+		return r.fail(err)
+	}
+
+	text := ""
+	for _, entry := range entries {
+		text += fmt.Sprintf("%v %v: %v %v\n", entry.Level, entry.Digit, entry.Node.Address, entry.Node.ID)
+	}
+	result := map[string]interface{}{"id": client.ID, "entries": entries}
+	return r.print(result, text)
+}
+
+func runKeys(args []string) int {
+	r := newRemoteFlags("keys")
+	client, code := r.connect(args, 0, 1)
+	if client == nil {
+		return code
+	}
+
+	prefix := r.flags.Arg(0)
+	keys := make([]string, 0)
+	token := ""
+	for {
+		page, next, err := client.ListKeys(prefix, token)
+		if err != nil {
+			return r.fail(err)
+		}
+		keys = append(keys, page...)
+		if next == "" {
+			break
+		}
+		token = next
+	}
+
+	text := ""
+	for _, key := range keys {
+		text += key + "\n"
+	}
+	result := map[string]interface{}{"prefix": prefix, "keys": keys}
+	return r.print(result, text)
+}
+
+func runPublished(args []string) int {
+	r := newRemoteFlags("published")
+	failing := r.flags.Bool("failing", false, "Only show keys whose last registration failed.")
+	client, code := r.connect(args, 0, 0)
+	if client == nil {
+		return code
+	}
+
+	statuses, err := client.PublishedKeys()
+	if err != nil {
+		return r.fail(err)
+	}
+	shown := make([]tapestry.PublishStatus, 0, len(statuses))
+	text := ""
+	for _, status := range statuses {
+		if *failing && status.Failures == 0 {
+			continue
+		}
+		shown = append(shown, status)
+		text += status.String() + "\n"
+	}
+	result := map[string]interface{}{"id": client.ID, "published": shown}
+	return r.print(result, text)
+}
+
+func runState(args []string) int {
+	r := newRemoteFlags("state")
+	locations := r.flags.Bool("locations", false, "Include the location maps of the node.")
+	client, code := r.connect(args, 0, 0)
+	if client == nil {
+		return code
+	}
+
+	state, err := client.State(*locations)
+	if err != nil {
+		return r.fail(err)
+	}
+	text := fmt.Sprintf("Node %v %v\nRouting table:\n", state.Node.ID, state.Node.Address)
+	for _, entry := range state.RoutingTable {
+		text += fmt.Sprintf(" %v %v: %v %v\n", entry.Level, entry.Digit, entry.Node.Address, entry.Node.ID)
+	}
+	text += "Backpointers:\n"
+	for _, entry := range state.Backpointers {
+		text += fmt.Sprintf(" %v: %v %v\n", entry.Level, entry.Node.Address, entry.Node.ID)
+	}
+	if *locations {
+		text += "Locations:\n"
+		for _, entry := range state.Locations {
+			text += fmt.Sprintf(" %v: %v\n", entry.Key, entry.Replicas)
+		}
+		text += "Backup locations:\n"
+		for _, entry := range state.BackupLocations {
+			text += fmt.Sprintf(" %v: %v\n", entry.Key, entry.Replicas)
+		}
+	}
+	return r.print(state, text)
+}
+
+func runTopology(args []string) int {
+	r := newRemoteFlags("topology")
+	format := r.flags.String("format", "dot", "The output format: dot for a Graphviz graph, or json.")
+	locations := r.flags.Bool("locations", false, "Include the location maps of each node in JSON output.")
+	client, code := r.connect(args, 0, 0)
+	if client == nil {
+		return code
+	}
+	if *format == "json" {
+		r.json = true
+	} else if *format != "dot" {
+		fmt.Fprintf(os.Stderr, "Error: unknown format %v\n", *format)
+		return exitUsage
+	}
+
+	topology, err := tapestry.CrawlTopology(client.Address(), *locations)
+	if err != nil {
+		return r.fail(err)
+	}
+	return r.print(topology, topology.DOT())
+}
+
+func runAdmin(args []string) int {
+	r := newRemoteFlags("admin")
+	token := r.flags.String("token", os.Getenv(adminTokenEnv), "The admin token of the node. Defaults to $"+adminTokenEnv+".")
+	caFile := r.flags.String("ca", "", "The certificate authority that signed the admin service's TLS certificate. Connects without TLS if left blank, which is only allowed to a loopback address.")
+	drainTimeout := r.flags.Duration("drain-timeout", time.Minute, "How long to wait for the node to drain.")
+	if err := r.flags.Parse(args); err != nil {
+		return exitUsage
+	}
+	if r.node == "" || r.flags.NArg() < 1 {
+		printUsage()
+		return exitUsage
+	}
+	command, level := r.flags.Arg(0), r.flags.Arg(1)
+	if (command == "log-level") != (r.flags.NArg() == 2) || r.flags.NArg() > 2 {
+		printUsage()
+		return exitUsage
+	}
+
+	admin, err := tapestry.DialAdmin(r.node, *token, *caFile)
+	if err != nil {
+		return r.fail(err)
+	}
+	defer admin.Close()
+
+	var result interface{}
+	text := ""
+	switch command {
+	case "table":
+		entries, err := admin.RoutingTable()
+		if err != nil {
+			return r.fail(err)
+		}
+		for _, entry := range entries {
+			text += fmt.Sprintf("%v %v: %v %v\n", entry.Level, entry.Digit, entry.Node.Address, entry.Node.ID)
+		}
+		result = map[string]interface{}{"entries": entries}
+	case "backpointers":
+		entries, err := admin.Backpointers()
+		if err != nil {
+			return r.fail(err)
+		}
+		for _, entry := range entries {
+			text += fmt.Sprintf("%v: %v %v\n", entry.Level, entry.Node.Address, entry.Node.ID)
+		}
+		result = map[string]interface{}{"backpointers": entries}
+	case "locations":
+		locations, backups, err := admin.LocationMap()
+		if err != nil {
+			return r.fail(err)
+		}
+		for _, entry := range locations {
+			text += fmt.Sprintf("%v: %v\n", entry.Key, entry.Replicas)
+		}
+		for _, entry := range backups {
+			text += fmt.Sprintf("%v (backup): %v\n", entry.Key, entry.Replicas)
+		}
+		result = map[string]interface{}{"locations": locations, "backupLocations": backups}
+	case "blobs":
+		blobs, err := admin.ListBlobs()
+		if err != nil {
+			return r.fail(err)
+		}
+		for _, blob := range blobs {
+			text += fmt.Sprintf("%v  %v\n", blob.Key, blob.Metadata)
+		}
+		result = map[string]interface{}{"blobs": blobs}
+	case "log-level":
+		previous, err := admin.SetLogLevel(level)
+		if err != nil {
+			return r.fail(err)
+		}
+		text = fmt.Sprintf("Log level changed from %v to %v\n", previous, level)
+		result = map[string]interface{}{"previous": previous, "level": level}
+	case "leave":
+		if err := admin.Leave(); err != nil {
+			return r.fail(err)
+		}
+		text = "Node left the tapestry\n"
+		result = map[string]interface{}{"left": true}
+	case "drain":
+		if err := admin.Drain(*drainTimeout); err != nil {
+			return r.fail(err)
+		}
+		text = "Node drained and stopped\n"
+		result = map[string]interface{}{"drained": true}
+	default:
+		printUsage()
+		return exitUsage
+	}
+	return r.print(result, text)
+}
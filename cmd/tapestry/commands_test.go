@@ -0,0 +1,245 @@
+package main
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	tapestry "tapestry/pkg"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Run the subcommand with args, returning its exit code and what it printed to stdout
+func run(t *testing.T, name string, args ...string) (int, string) {
+	stdout, stderr := os.Stdout, os.Stderr
+	r, w, err := os.Pipe()
+	assert.Equal(t, err, nil)
+	devnull, err := os.Open(os.DevNull)
+	assert.Equal(t, err, nil)
+	defer devnull.Close()
+	os.Stdout, os.Stderr = w, devnull
+
+	output := make(chan string)
+	go func() {
+		contents, _ := ioutil.ReadAll(r)
+		output <- string(contents)
+	}()
+	code := commands[name](args)
+	w.Close()
+	os.Stdout, os.Stderr = stdout, stderr
+	return code, <-output
+}
+
+// test the subcommands reject missing and extra arguments with the usage exit code
+func TestCommandsUsage(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"put", []string{"key", "value"}},
+		{"put", []string{"--node", "localhost:1"}},
+		{"put", []string{"--node", "localhost:1", "key", "value", "extra"}},
+		{"put", []string{"--node", "localhost:1", "--unknown", "key"}},
+		{"get", []string{"key"}},
+		{"get", []string{"--node", "localhost:1"}},
+		{"get", []string{"--node", "localhost:1", "key", "extra"}},
+		{"stat", []string{"--node", "localhost:1"}},
+		{"lookup", []string{"--node", "localhost:1"}},
+		{"lookup", []string{"--node", "localhost:1", "key", "extra"}},
+		{"table", []string{}},
+		{"table", []string{"--node", "localhost:1", "extra"}},
+		{"keys", []string{"--node", "localhost:1", "prefix", "extra"}},
+	}
+	for _, test := range tests {
+		code, _ := run(t, test.name, test.args...)
+		assert.Equal(t, code, exitUsage, "%v %v", test.name, test.args)
+	}
+}
+
+// test the subcommands against a running node, and their exit codes
+func TestCommandsAgainstNode(t *testing.T) {
+	node, err := tapestry.Start(tapestry.MakeID("1"), 0, "")
+	assert.Equal(t, err, nil)
+	defer tapestry.KillTapestries(node)
+	addr := node.Addr()
+
+	tests := []struct {
+		name   string
+		args   []string
+		code   int
+		output string
+	}{
+		{"put", []string{"--node", addr, "greeting", "hello"}, exitOK, "Successfully stored 5 bytes at key (greeting)\n"},
+		{"get", []string{"-n", addr, "greeting"}, exitOK, "hello"},
+		{"get", []string{"-n", addr, "missing"}, exitNotFound, ""},
+		{"put", []string{"-n", addr, "--if-version", "0", "greeting", "again"}, exitConflict, ""},
+		{"put", []string{"-n", addr, "--if-version", "bad", "greeting", "again"}, exitUsage, ""},
+		{"lookup", []string{"-n", addr, "missing"}, exitNotFound, ""},
+		{"keys", []string{"-n", addr, "greet"}, exitOK, "greeting\n"},
+		{"get", []string{"-n", "127.0.0.1:1", "greeting"}, exitError, ""},
+	}
+	for _, test := range tests {
+		code, output := run(t, test.name, test.args...)
+		assert.Equal(t, code, test.code, "%v %v", test.name, test.args)
+		if test.output != "" {
+			assert.Equal(t, output, test.output, "%v %v", test.name, test.args)
+		}
+	}
+
+	code, output := run(t, "lookup", "-n", addr, "greeting")
+	assert.Equal(t, code, exitOK)
+	assert.Contains(t, output, node.Node.ID.String())
+	code, output = run(t, "table", "-n", addr)
+	assert.Equal(t, code, exitOK)
+	assert.Contains(t, output, addr)
+}
+
+// test --json output, with binary values encoded in base64
+func TestCommandsJSON(t *testing.T) {
+	node, err := tapestry.Start(tapestry.MakeID("1"), 0, "")
+	assert.Equal(t, err, nil)
+	defer tapestry.KillTapestries(node)
+	binary := []byte{0xff, 0x00, 0xfe}
+	assert.Equal(t, node.Store("binary", binary), nil)
+
+	code, output := run(t, "get", "-n", node.Addr(), "--json", "binary")
+	assert.Equal(t, code, exitOK)
+	var result map[string]string
+	assert.Equal(t, json.Unmarshal([]byte(output), &result), nil)
+	value, err := base64.StdEncoding.DecodeString(result["value"])
+	assert.Equal(t, err, nil)
+	assert.Equal(t, value, binary)
+
+	code, output = run(t, "get", "-n", node.Addr(), "--json", "missing")
+	assert.Equal(t, code, exitNotFound)
+	assert.Equal(t, json.Unmarshal([]byte(output), &result), nil)
+	assert.NotEqual(t, result["error"], "")
+
+	code, output = run(t, "stat", "-n", node.Addr(), "--json", "binary")
+	assert.Equal(t, code, exitOK)
+	var stat struct {
+		Key      string
+		Metadata tapestry.Metadata
+	}
+	assert.Equal(t, json.Unmarshal([]byte(output), &stat), nil)
+	assert.Equal(t, stat.Key, "binary")
+	assert.Equal(t, stat.Metadata.Size, 3)
+}
+
+// test the published command lists the keys the node publishes, and filters failing ones
+func TestPublishedCommand(t *testing.T) {
+	node, err := tapestry.Start(tapestry.MakeID("1"), 0, "")
+	assert.Equal(t, err, nil)
+	defer tapestry.KillTapestries(node)
+	assert.Equal(t, node.Store("greeting", []byte("hello")), nil)
+
+	tests := []struct {
+		args   []string
+		code   int
+		output string
+	}{
+		{[]string{}, exitUsage, ""},
+		{[]string{"-n", node.Addr(), "extra"}, exitUsage, ""},
+		{[]string{"-n", node.Addr(), "--unknown"}, exitUsage, ""},
+		{[]string{"-n", node.Addr(), "--failing"}, exitOK, ""},
+		{[]string{"-n", "127.0.0.1:1"}, exitError, ""},
+		{[]string{"-n", node.Addr()}, exitOK, "greeting"},
+	}
+	for _, test := range tests {
+		code, output := run(t, "published", test.args...)
+		assert.Equal(t, code, test.code, "%v", test.args)
+		assert.Contains(t, output, test.output, "%v", test.args)
+	}
+
+	_, output := run(t, "published", "-n", node.Addr(), "--failing")
+	assert.NotContains(t, output, "greeting")
+
+	code, output := run(t, "published", "-n", node.Addr(), "--json")
+	assert.Equal(t, code, exitOK)
+	var result struct {
+		Published []tapestry.PublishStatus
+	}
+	assert.Equal(t, json.Unmarshal([]byte(output), &result), nil)
+	assert.Equal(t, len(result.Published), 1)
+	if len(result.Published) == 1 {
+		assert.Equal(t, result.Published[0].Key, "greeting")
+	}
+}
+
+// test the state and topology commands against a mesh, and their exit codes
+func TestStateAndTopologyCommands(t *testing.T) {
+	tap, err := tapestry.MakeTapestries(true, "1", "5")
+	assert.Equal(t, err, nil)
+	defer tapestry.KillTapestries(tap...)
+	addr := tap[0].Addr()
+
+	tests := []struct {
+		name   string
+		args   []string
+		code   int
+		output string
+	}{
+		{"state", []string{}, exitUsage, ""},
+		{"state", []string{"-n", addr, "extra"}, exitUsage, ""},
+		{"state", []string{"-n", "127.0.0.1:1"}, exitError, ""},
+		{"state", []string{"-n", addr}, exitOK, "Routing table:"},
+		{"state", []string{"-n", addr, "--locations"}, exitOK, "Backup locations:"},
+		{"topology", []string{}, exitUsage, ""},
+		{"topology", []string{"-n", addr, "extra"}, exitUsage, ""},
+		{"topology", []string{"-n", addr, "--format", "svg"}, exitUsage, ""},
+		{"topology", []string{"-n", "127.0.0.1:1"}, exitError, ""},
+		{"topology", []string{"-n", addr}, exitOK, "digraph"},
+	}
+	for _, test := range tests {
+		code, output := run(t, test.name, test.args...)
+		assert.Equal(t, code, test.code, "%v %v", test.name, test.args)
+		assert.Contains(t, output, test.output, "%v %v", test.name, test.args)
+	}
+
+	code, output := run(t, "state", "-n", addr, "--json")
+	assert.Equal(t, code, exitOK)
+	var state tapestry.NodeState
+	assert.Equal(t, json.Unmarshal([]byte(output), &state), nil)
+	assert.Equal(t, state.Node, tap[0].Node)
+
+	code, output = run(t, "topology", "-n", addr, "--format", "json")
+	assert.Equal(t, code, exitOK)
+	var topology tapestry.Topology
+	assert.Equal(t, json.Unmarshal([]byte(output), &topology), nil)
+	assert.Equal(t, len(topology.Nodes), 2)
+}
+
+// test the admin subcommand reaches a loopback admin service without TLS, and its exit codes
+func TestAdminCommand(t *testing.T) {
+	node, err := tapestry.Start(tapestry.MakeID("1"), 0, "", tapestry.WithAdmin("localhost:0", "secret"))
+	assert.Equal(t, err, nil)
+	defer tapestry.KillTapestries(node)
+	assert.Equal(t, node.Store("admin", []byte("value")), nil)
+	addr := node.AdminAddr()
+	level := tapestry.LogLevel()
+
+	tests := []struct {
+		args   []string
+		code   int
+		output string
+	}{
+		{[]string{"table"}, exitUsage, ""},
+		{[]string{"-n", addr}, exitUsage, ""},
+		{[]string{"-n", addr, "table", "extra"}, exitUsage, ""},
+		{[]string{"-n", addr, "log-level"}, exitUsage, ""},
+		{[]string{"-n", addr, "--token", "secret", "unknown"}, exitUsage, ""},
+		{[]string{"-n", "192.0.2.1:7000", "--token", "secret", "table"}, exitError, ""},
+		{[]string{"-n", addr, "--token", "guess", "table"}, exitError, ""},
+		{[]string{"-n", addr, "--token", "secret", "table"}, exitOK, node.Addr()},
+		{[]string{"-n", addr, "--token", "secret", "blobs"}, exitOK, "admin"},
+		{[]string{"-n", addr, "--token", "secret", "locations"}, exitOK, "admin"},
+		{[]string{"-n", addr, "--token", "secret", "log-level", level}, exitOK, "Log level changed"},
+	}
+	for _, test := range tests {
+		code, output := run(t, "admin", test.args...)
+		assert.Equal(t, code, test.code, "%v", test.args)
+		assert.Contains(t, output, test.output, "%v", test.args)
+	}
+}
@@ -0,0 +1,451 @@
+/*
+ *  Brown University, CS138, Spring 2022
+ *
+ *  Purpose: reads the configuration of a node started by the tapestry
+ *  command from a YAML file, which flags given on the command line
+ *  override, and checks it before the node starts.
+ */
+
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	tapestry "tapestry/pkg"
+	"time"
+
+	"gopkg.in/yaml.v2"
+)
+
+// idFileName is the file holding the node ID in the data directory, when no ID file is configured
+const idFileName = "node-id"
+
+// nodeConfig is the configuration of a node started by the shell or in daemon mode. Every field
+// can be set in the YAML file given with -config, and most by a flag, which takes precedence.
+type nodeConfig struct {
+	Listen    string   `yaml:"listen"`    // The address to bind to, overriding Port
+	Port      int      `yaml:"port"`      // The port to bind to, or zero for a random port
+	Advertise string   `yaml:"advertise"` // The address other nodes should use to reach this node
+	Seeds     []string `yaml:"seeds"`     // Existing nodes to join through, tried in order
+	SeedFile  string   `yaml:"seed-file"` // A file listing seeds, one address per line
+	SRV       string   `yaml:"srv"`       // A DNS SRV record listing seeds
+
+	ID      string `yaml:"id"`       // The node ID, random if blank
+	IDFile  string `yaml:"id-file"`  // A file holding the node ID, created with a random ID if missing
+	DataDir string `yaml:"data-dir"` // The directory holding the node's files, such as its ID
+
+	HTTP        string `yaml:"http"`          // The address to serve the HTTP gateway on, if any
+	HTTPMaxBody int64  `yaml:"http-max-body"` // The largest request body the gateways accept, or zero for the default
+	S3          string `yaml:"s3"`            // The address to serve the S3 gateway on, if any
+	S3Bucket    string `yaml:"s3-bucket"`     // The bucket served by the S3 gateway
+
+	Storage  storageConfig  `yaml:"storage"`
+	Timeouts timeoutsConfig `yaml:"timeouts"`
+	Log      logConfig      `yaml:"log"`
+	Admin    adminConfig    `yaml:"admin"`
+
+	Daemon  bool   `yaml:"daemon"`   // Run without the interactive shell
+	PIDFile string `yaml:"pid-file"` // Written once the node has joined, in daemon mode
+
+	File string `yaml:"-"` // The file the configuration was read from, if any
+}
+
+type storageConfig struct {
+	MaxBytes   int64         `yaml:"max-bytes"`   // The byte quota of the blob store, or zero for none
+	MaxObjects int           `yaml:"max-objects"` // The blob quota of the blob store, or zero for none
+	Eviction   string        `yaml:"eviction"`    // lru, lfu or reject
+	Cache      time.Duration `yaml:"cache"`       // How long fetched blobs are cached, or zero to not cache them
+	Hedge      float64       `yaml:"hedge"`       // The percentile of fetch latency after which fetches are hedged, or zero
+}
+
+type timeoutsConfig struct {
+	RPC          time.Duration `yaml:"rpc"`           // How long RPCs to other nodes may take
+	Registration time.Duration `yaml:"registration"`  // How long registrations last without being republished
+	Republish    time.Duration `yaml:"republish"`     // How often blobs are republished
+	Retries      int           `yaml:"retries"`       // How many times publishing and fetching are attempted
+	JoinAttempts int           `yaml:"join-attempts"` // How many passes over the seeds are made before the join fails
+	JoinBackoff  time.Duration `yaml:"join-backoff"`  // The delay before the first retry of a failed join
+}
+
+type logConfig struct {
+	Level     string `yaml:"level"`      // error, info, debug or trace
+	LevelFile string `yaml:"level-file"` // A file holding the log level to set on SIGHUP, in daemon mode
+}
+
+// The TLS settings apply to the admin service only. The peer protocol and the HTTP and S3 gateways
+// are always served without TLS, so they belong on a trusted network or behind a TLS proxy.
+type adminConfig struct {
+	Listen    string `yaml:"listen"`     // The address to serve the admin service on, if any
+	Token     string `yaml:"token"`      // The token admin clients must present
+	TokenFile string `yaml:"token-file"` // A file holding the token, read if Token is blank
+	Cert      string `yaml:"cert"`       // The TLS certificate file of the admin service
+	Key       string `yaml:"key"`        // The TLS key file of the admin service
+}
+
+// The configuration used when neither the file nor a flag sets a value
+func defaultConfig() nodeConfig {
+	return nodeConfig{
+		S3Bucket: "tapestry",
+		Storage:  storageConfig{Eviction: "lru"},
+		Timeouts: timeoutsConfig{
+			RPC:          tapestry.GRPCTimeout,
+			Registration: tapestry.TIMEOUT,
+			Republish:    tapestry.REPUBLISH,
+			Retries:      tapestry.RETRIES,
+			JoinAttempts: tapestry.RETRIES,
+			JoinBackoff:  tapestry.JOINBACKOFF,
+		},
+		Log:   logConfig{Level: "info"},
+		Admin: adminConfig{Token: os.Getenv(adminTokenEnv)},
+	}
+}
+
+// Registers the flags of the shell on flags, bound to the fields of config
+func (config *nodeConfig) bind(flags *flag.FlagSet, configFile *string) {
+	flags.StringVar(configFile, "config", "", "A YAML file to read the configuration from. Flags override it.")
+
+	flags.IntVar(&config.Port, "port", config.Port, "The server port to bind to. Defaults to a random port.")
+	flags.IntVar(&config.Port, "p", config.Port, "The server port to bind to. Defaults to a random port. (shorthand)")
+
+	flags.StringVar(&config.Listen, "listen", config.Listen, "The address to bind to, such as 0.0.0.0:4000, [::]:4000 or unix:/var/run/tapestry.sock. Overrides -port.")
+	flags.StringVar(&config.Advertise, "advertise", config.Advertise, "The address other nodes should use to reach this node. Defaults to the bound IP, or the hostname.")
+
+	flags.StringVar(&config.HTTP, "http", config.HTTP, "The address to serve the HTTP/JSON gateway on, such as :8080. Disabled if left blank.")
+	flags.Int64Var(&config.HTTPMaxBody, "http-max-body", config.HTTPMaxBody, "The largest request body, in bytes, that the HTTP and S3 gateways accept. Defaults to 64 MiB if 0.")
+
+	flags.StringVar(&config.S3, "s3", config.S3, "The address to serve the S3-compatible gateway on, such as :9000. Disabled if left blank.")
+	flags.StringVar(&config.S3Bucket, "s3-bucket", config.S3Bucket, "The name of the single bucket served by the S3 gateway.")
+
+	flags.Var((*listFlag)(&config.Seeds), "connect", "A comma-separated list of existing nodes to connect to, tried in order. If left blank, does not attempt to connect to another node.")
+	flags.Var((*listFlag)(&config.Seeds), "c", "A comma-separated list of existing nodes to connect to, tried in order. If left blank, does not attempt to connect to another node.  (shorthand)")
+
+	flags.StringVar(&config.SeedFile, "seeds", config.SeedFile, "A file listing existing nodes to connect to, one address per line.")
+	flags.StringVar(&config.SRV, "srv", config.SRV, "A DNS SRV record, such as _tapestry._tcp.example.com, listing existing nodes to connect to.")
+
+	flags.StringVar(&config.ID, "id", config.ID, "The ID of the node, as 40 hex digits. Random if left blank.")
+	flags.StringVar(&config.IDFile, "id-file", config.IDFile, "A file holding the ID of the node, written with a random ID if it does not exist.")
+	flags.StringVar(&config.DataDir, "data-dir", config.DataDir, "The directory holding the files of the node. Its ID is kept in "+idFileName+" there unless -id or -id-file is given.")
+
+	flags.Int64Var(&config.Storage.MaxBytes, "max-bytes", config.Storage.MaxBytes, "The most bytes of blobs this node stores. Unlimited if 0.")
+	flags.IntVar(&config.Storage.MaxObjects, "max-objects", config.Storage.MaxObjects, "The most blobs this node stores. Unlimited if 0.")
+	flags.StringVar(&config.Storage.Eviction, "eviction", config.Storage.Eviction, "How to make room once the blob store is full: lru, lfu or reject.")
+	flags.DurationVar(&config.Storage.Cache, "cache", config.Storage.Cache, "How long to cache blobs fetched from other nodes, such as 30s. Disabled if 0.")
+	flags.Float64Var(&config.Storage.Hedge, "hedge", config.Storage.Hedge, "Also fetch from the next replica when the first has not answered within this percentile of fetch latency, such as 0.95. Disabled if 0.")
+
+	flags.DurationVar(&config.Timeouts.RPC, "rpc-timeout", config.Timeouts.RPC, "How long RPCs to other nodes may take.")
+	flags.DurationVar(&config.Timeouts.Registration, "registration-timeout", config.Timeouts.Registration, "How long this node keeps registrations it is root of without them being republished.")
+	flags.DurationVar(&config.Timeouts.Republish, "republish", config.Timeouts.Republish, "How often the blobs stored on this node are registered again with their roots.")
+	flags.IntVar(&config.Timeouts.Retries, "retries", config.Timeouts.Retries, "How many times publishing and fetching a key are attempted.")
+	flags.IntVar(&config.Timeouts.JoinAttempts, "join-attempts", config.Timeouts.JoinAttempts, "How many passes over the seeds are made before the join fails.")
+	flags.DurationVar(&config.Timeouts.JoinBackoff, "join-backoff", config.Timeouts.JoinBackoff, "The delay before the first retry of a failed join. It doubles after every failed pass.")
+
+	flags.StringVar(&config.Admin.Listen, "admin", config.Admin.Listen, "The address to serve the admin service on, such as localhost:7000. Disabled if left blank.")
+	flags.StringVar(&config.Admin.Token, "admin-token", config.Admin.Token, "The token admin clients must present. Defaults to $"+adminTokenEnv+".")
+	flags.StringVar(&config.Admin.TokenFile, "admin-token-file", config.Admin.TokenFile, "A file holding the token admin clients must present, if -admin-token is blank.")
+	flags.StringVar(&config.Admin.Cert, "admin-cert", config.Admin.Cert, "The TLS certificate file of the admin service. Serves without TLS if left blank, which is only allowed on a loopback address.")
+	flags.StringVar(&config.Admin.Key, "admin-key", config.Admin.Key, "The TLS key file of the admin service.")
+
+	flags.BoolVar(&config.Daemon, "daemon", config.Daemon, "Run without the interactive shell, until SIGTERM or SIGINT makes the node leave.")
+	flags.StringVar(&config.PIDFile, "pid-file", config.PIDFile, "The file to write the process ID to once the node has joined, in daemon mode.")
+	flags.StringVar(&config.Log.Level, "log-level", config.Log.Level, "The log level: "+strings.Join(tapestry.LogLevels, ", ")+".")
+	flags.StringVar(&config.Log.LevelFile, "log-level-file", config.Log.LevelFile, "A file holding the log level to set on SIGHUP, in daemon mode. SIGHUP reloads log.level from -config, or restores -log-level, if left blank.")
+
+	flags.Var((*debugFlag)(&config.Log.Level), "debug", "Turn on debug message printing. Same as -log-level debug.")
+	flags.Var((*debugFlag)(&config.Log.Level), "d", "Turn on debug message printing. Same as -log-level debug. (shorthand)")
+}
+
+// parseConfig reads the configuration from the file given with -config, if any, and then from the
+// flags in args, which take precedence. Returns the flags, from which positional arguments can be read.
+func parseConfig(name string, args []string) (config nodeConfig, flags *flag.FlagSet, err error) {
+	// The file is found on a first pass over the flags, and read before they are parsed again
+	// on top of it
+	var configFile string
+	config = defaultConfig()
+	flags = flag.NewFlagSet(name, flag.ContinueOnError)
+	config.bind(flags, &configFile)
+	if err = flags.Parse(args); err != nil {
+		return config, flags, err
+	}
+	if configFile == "" {
+		return config, flags, nil
+	}
+
+	config = defaultConfig()
+	if err = config.load(configFile); err != nil {
+		return config, flags, err
+	}
+	flags = flag.NewFlagSet(name, flag.ContinueOnError)
+	config.bind(flags, &configFile)
+	err = flags.Parse(args)
+	config.File = configFile
+	return config, flags, err
+}
+
+// Read the configuration from a YAML file, on top of the current values. Unknown keys are errors.
+func (config *nodeConfig) load(path string) error {
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if err := yaml.UnmarshalStrict(contents, config); err != nil {
+		return fmt.Errorf("%v: %v", path, err)
+	}
+	return nil
+}
+
+// validate checks the configuration for values the node would fail to start with, or that do not
+// make sense together, and returns all of the problems found
+func (config *nodeConfig) validate() error {
+	var problems []string
+	check := func(ok bool, format string, args ...interface{}) {
+		if !ok {
+			problems = append(problems, fmt.Sprintf(format, args...))
+		}
+	}
+	readable := func(field string, path string) {
+		if path == "" {
+			return
+		}
+		file, err := os.Open(path)
+		check(err == nil, "%v: %v", field, err)
+		if err == nil {
+			file.Close()
+		}
+	}
+
+	check(config.Port >= 0 && config.Port <= 65535, "port: %v is not a valid port", config.Port)
+	if config.ID != "" {
+		_, err := tapestry.ParseID(config.ID)
+		check(err == nil, "id: %v", err)
+	}
+	check(config.ID == "" || config.IDFile == "", "id and id-file cannot both be set")
+	readable("seed-file", config.SeedFile)
+
+	check(config.HTTPMaxBody >= 0, "http-max-body: must not be negative")
+
+	check(config.Storage.MaxBytes >= 0, "storage.max-bytes: must not be negative")
+	check(config.Storage.MaxObjects >= 0, "storage.max-objects: must not be negative")
+	_, err := tapestry.ParseEvictionPolicy(config.Storage.Eviction)
+	check(err == nil, "storage.eviction: %v", err)
+	check(config.Storage.Cache >= 0, "storage.cache: must not be negative")
+	check(config.Storage.Hedge >= 0 && config.Storage.Hedge < 1, "storage.hedge: must be at least 0 and under 1")
+
+	check(config.Timeouts.RPC > 0, "timeouts.rpc: must be positive")
+	check(config.Timeouts.Registration > 0, "timeouts.registration: must be positive")
+	check(config.Timeouts.Republish > 0, "timeouts.republish: must be positive")
+	check(config.Timeouts.Republish < config.Timeouts.Registration, "timeouts.republish: %v must be under timeouts.registration (%v), or registrations expire before they are republished", config.Timeouts.Republish, config.Timeouts.Registration)
+	check(config.Timeouts.Retries > 0, "timeouts.retries: must be positive")
+	check(config.Timeouts.JoinAttempts > 0, "timeouts.join-attempts: must be positive")
+	check(config.Timeouts.JoinBackoff >= 0, "timeouts.join-backoff: must not be negative")
+
+	err = checkLogLevel(config.Log.Level)
+	check(err == nil, "log.level: %v", err)
+
+	if config.Admin.Listen != "" {
+		check(config.Admin.Token != "" || config.Admin.TokenFile != "", "admin: the admin service requires a token or token-file")
+		readable("admin.token-file", config.Admin.TokenFile)
+		check(config.Admin.Cert != "" || tapestry.IsLoopback(config.Admin.Listen), "admin.listen: %v is not a loopback address, so the admin service requires a cert and key", config.Admin.Listen)
+	}
+	check((config.Admin.Cert == "") == (config.Admin.Key == ""), "admin: cert and key must be set together")
+	readable("admin.cert", config.Admin.Cert)
+	readable("admin.key", config.Admin.Key)
+
+	check(config.Daemon || config.PIDFile == "", "pid-file: only written in daemon mode")
+
+	if len(problems) > 0 {
+		return errors.New(strings.Join(problems, "\n"))
+	}
+	return nil
+}
+
+// Checks that level is one of tapestry.LogLevels
+func checkLogLevel(level string) error {
+	for _, name := range tapestry.LogLevels {
+		if name == level {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown log level %v, expected one of %v", level, strings.Join(tapestry.LogLevels, ", "))
+}
+
+// options returns the options to start the node with. The configuration must be valid.
+func (config *nodeConfig) options() ([]tapestry.StartOption, error) {
+	opts := []tapestry.StartOption{
+		tapestry.WithRetries(config.Timeouts.Retries),
+		tapestry.WithRegistrationTimeout(config.Timeouts.Registration),
+		tapestry.WithRepublishInterval(config.Timeouts.Republish),
+		tapestry.WithJoinRetry(config.Timeouts.JoinAttempts, config.Timeouts.JoinBackoff),
+	}
+	if config.Listen != "" {
+		opts = append(opts, tapestry.WithListen(config.Listen))
+	}
+	if config.Advertise != "" {
+		opts = append(opts, tapestry.WithAdvertise(config.Advertise))
+	}
+	if len(config.Seeds) > 0 {
+		opts = append(opts, tapestry.WithSeeds(config.Seeds...))
+	}
+	if config.SeedFile != "" {
+		opts = append(opts, tapestry.WithSeedFile(config.SeedFile))
+	}
+	if config.SRV != "" {
+		opts = append(opts, tapestry.WithSeedResolver(tapestry.SRVSeeds{Name: config.SRV}))
+	}
+	if config.HTTP != "" {
+		opts = append(opts, tapestry.WithHTTP(config.HTTP))
+	}
+	if config.S3 != "" {
+		opts = append(opts, tapestry.WithS3(config.S3, config.S3Bucket))
+	}
+	if config.HTTPMaxBody > 0 {
+		opts = append(opts, tapestry.WithMaxBodySize(config.HTTPMaxBody))
+	}
+
+	policy, err := tapestry.ParseEvictionPolicy(config.Storage.Eviction)
+	if err != nil {
+		return nil, err
+	}
+	opts = append(opts, tapestry.WithBlobQuota(config.Storage.MaxBytes, config.Storage.MaxObjects), tapestry.WithEvictionPolicy(policy))
+	if config.Storage.Cache > 0 {
+		opts = append(opts, tapestry.WithCache(config.Storage.Cache))
+	}
+	if config.Storage.Hedge > 0 {
+		opts = append(opts, tapestry.WithHedging(config.Storage.Hedge))
+	}
+
+	if config.Admin.Listen != "" {
+		token := config.Admin.Token
+		if token == "" && config.Admin.TokenFile != "" {
+			contents, err := ioutil.ReadFile(config.Admin.TokenFile)
+			if err != nil {
+				return nil, err
+			}
+			token = strings.TrimSpace(string(contents))
+		}
+		opts = append(opts, tapestry.WithAdmin(config.Admin.Listen, token))
+	}
+	if config.Admin.Cert != "" {
+		opts = append(opts, tapestry.WithAdminTLS(config.Admin.Cert, config.Admin.Key))
+	}
+	return opts, nil
+}
+
+// The file the node ID is kept in, or an empty string if the ID is not kept
+func (config *nodeConfig) idFile() string {
+	if config.IDFile != "" {
+		return config.IDFile
+	}
+	if config.ID == "" && config.DataDir != "" {
+		return filepath.Join(config.DataDir, idFileName)
+	}
+	return ""
+}
+
+// nodeID returns the configured ID of the node, or the ID kept in its ID file. If neither exists,
+// fixed is false and the node should start with a random ID, which saveID then keeps.
+func (config *nodeConfig) nodeID() (id tapestry.ID, fixed bool, err error) {
+	if config.ID != "" {
+		id, err = tapestry.ParseID(config.ID)
+		return id, true, err
+	}
+	path := config.idFile()
+	if path == "" {
+		return id, false, nil
+	}
+	contents, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return id, false, nil
+	} else if err != nil {
+		return id, false, err
+	}
+	id, err = tapestry.ParseID(strings.TrimSpace(string(contents)))
+	if err != nil {
+		return id, false, fmt.Errorf("%v: %v", path, err)
+	}
+	return id, true, nil
+}
+
+// saveID keeps the ID the node started with in its ID file, if it has one, so that it restarts with it
+func (config *nodeConfig) saveID(id tapestry.ID) error {
+	path := config.idFile()
+	if path == "" {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, []byte(id.String()+"\n"), 0644)
+}
+
+// Sets a list from a comma-separated flag, replacing the list given in the configuration file.
+// Spaces around each element are trimmed, and empty elements dropped. An empty flag empties the list.
+type listFlag []string
+
+func (l *listFlag) String() string {
+	if l == nil {
+		return ""
+	}
+	return strings.Join(*l, ",")
+}
+
+func (l *listFlag) Set(value string) error {
+	if value == "" {
+		*l = nil
+		return nil
+	}
+	*l = nil
+	for _, element := range strings.Split(value, ",") {
+		if element = strings.TrimSpace(element); element != "" {
+			*l = append(*l, element)
+		}
+	}
+	return nil
+}
+
+// Sets the log level to debug when the flag is given
+type debugFlag string
+
+func (d *debugFlag) String() string {
+	return ""
+}
+
+func (d *debugFlag) Set(value string) error {
+	if value == "true" {
+		*d = "debug"
+	}
+	return nil
+}
+
+func (d *debugFlag) IsBoolFlag() bool {
+	return true
+}
+
+// runConfig runs the config subcommand, which checks a configuration without starting a node
+func runConfig(args []string) int {
+	if len(args) == 0 || args[0] != "validate" {
+		printUsage()
+		return exitUsage
+	}
+	config, flags, err := parseConfig("config validate", args[1:])
+	if err != nil {
+		if err != flag.ErrHelp {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		}
+		return exitUsage
+	}
+	if flags.NArg() > 0 {
+		printUsage()
+		return exitUsage
+	}
+	if err := config.validate(); err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid configuration:\n%v\n", err)
+		return exitError
+	}
+	fmt.Println("Configuration is valid")
+	return exitOK
+}
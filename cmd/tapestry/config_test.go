@@ -0,0 +1,168 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	tapestry "tapestry/pkg"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Write a configuration file to dir, returning its path
+func writeConfig(t *testing.T, dir string, contents string) string {
+	path := filepath.Join(dir, "tapestry.yaml")
+	assert.Equal(t, ioutil.WriteFile(path, []byte(contents), 0644), nil)
+	return path
+}
+
+// test parseConfig reads the flags on top of the defaults
+func TestParseConfigFlags(t *testing.T) {
+	config, flags, err := parseConfig("shell", []string{})
+	assert.Equal(t, err, nil)
+	assert.Equal(t, config, defaultConfig())
+	assert.Equal(t, flags.NArg(), 0)
+
+	config, flags, err = parseConfig("shell", []string{"-p", "4000", "-c", " a:1, b:2 ,,c:3 ", "-d", "--max-objects", "10", "--admin", "localhost:7000", "extra"})
+	assert.Equal(t, err, nil)
+	assert.Equal(t, config.Port, 4000)
+	assert.Equal(t, config.Seeds, []string{"a:1", "b:2", "c:3"})
+	assert.Equal(t, config.Log.Level, "debug")
+	assert.Equal(t, config.Storage.MaxObjects, 10)
+	assert.Equal(t, config.Admin.Listen, "localhost:7000")
+	assert.Equal(t, config.File, "")
+	assert.Equal(t, flags.Args(), []string{"extra"})
+
+	config, _, err = parseConfig("shell", []string{"-c", ""})
+	assert.Equal(t, err, nil)
+	assert.Equal(t, len(config.Seeds), 0)
+
+	_, _, err = parseConfig("shell", []string{"--unknown"})
+	assert.NotEqual(t, err, nil)
+	_, _, err = parseConfig("shell", []string{"--port", "many"})
+	assert.NotEqual(t, err, nil)
+}
+
+// test flags take precedence over the configuration file, which takes precedence over the defaults
+func TestParseConfigFile(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "config")
+	defer os.RemoveAll(dir)
+	path := writeConfig(t, dir, `
+listen: 0.0.0.0:4000
+seeds: [a:1, b:2]
+storage:
+  eviction: lfu
+  max-bytes: 1024
+timeouts:
+  rpc: 2s
+  retries: 5
+log:
+  level: error
+`)
+
+	config, _, err := parseConfig("shell", []string{"-config", path})
+	assert.Equal(t, err, nil)
+	assert.Equal(t, config.File, path)
+	assert.Equal(t, config.Listen, "0.0.0.0:4000")
+	assert.Equal(t, config.Seeds, []string{"a:1", "b:2"})
+	assert.Equal(t, config.Storage.Eviction, "lfu")
+	assert.Equal(t, config.Storage.MaxBytes, int64(1024))
+	assert.Equal(t, config.Timeouts.RPC, 2*time.Second)
+	assert.Equal(t, config.Timeouts.Retries, 5)
+	assert.Equal(t, config.Timeouts.Registration, tapestry.TIMEOUT)
+	assert.Equal(t, config.Log.Level, "error")
+
+	// Flags win wherever they are given, before or after -config
+	config, _, err = parseConfig("shell", []string{"-c", "c:3", "-config", path, "--eviction", "reject", "--rpc-timeout", "1s", "-d"})
+	assert.Equal(t, err, nil)
+	assert.Equal(t, config.Listen, "0.0.0.0:4000")
+	assert.Equal(t, config.Seeds, []string{"c:3"})
+	assert.Equal(t, config.Storage.Eviction, "reject")
+	assert.Equal(t, config.Storage.MaxBytes, int64(1024))
+	assert.Equal(t, config.Timeouts.RPC, time.Second)
+	assert.Equal(t, config.Timeouts.Retries, 5)
+	assert.Equal(t, config.Log.Level, "debug")
+
+	_, _, err = parseConfig("shell", []string{"-config", filepath.Join(dir, "missing.yaml")})
+	assert.NotEqual(t, err, nil)
+	_, _, err = parseConfig("shell", []string{"-config", writeConfig(t, dir, "unknown: true\n")})
+	assert.NotEqual(t, err, nil)
+	_, _, err = parseConfig("shell", []string{"-config", writeConfig(t, dir, "port: many\n")})
+	assert.NotEqual(t, err, nil)
+}
+
+// test validate accepts the defaults, and reports each problem with the field it comes from
+func TestValidateConfig(t *testing.T) {
+	config := defaultConfig()
+	assert.Equal(t, config.validate(), nil)
+
+	tests := []struct {
+		change  func(config *nodeConfig)
+		problem string
+	}{
+		{func(c *nodeConfig) { c.Port = 70000 }, "port:"},
+		{func(c *nodeConfig) { c.ID = "xyz" }, "id:"},
+		{func(c *nodeConfig) { c.ID, c.IDFile = "1", "node-id" }, "id and id-file"},
+		{func(c *nodeConfig) { c.SeedFile = "/nonexistent/seeds" }, "seed-file:"},
+		{func(c *nodeConfig) { c.HTTPMaxBody = -1 }, "http-max-body:"},
+		{func(c *nodeConfig) { c.Storage.Eviction = "random" }, "storage.eviction:"},
+		{func(c *nodeConfig) { c.Storage.Hedge = 1 }, "storage.hedge:"},
+		{func(c *nodeConfig) { c.Timeouts.RPC = 0 }, "timeouts.rpc:"},
+		{func(c *nodeConfig) { c.Timeouts.Republish = c.Timeouts.Registration }, "timeouts.republish:"},
+		{func(c *nodeConfig) { c.Timeouts.Retries = 0 }, "timeouts.retries:"},
+		{func(c *nodeConfig) { c.Log.Level = "loud" }, "log.level:"},
+		{func(c *nodeConfig) { c.Admin.Listen, c.Admin.Token = "localhost:7000", "" }, "admin: the admin service requires a token"},
+		{func(c *nodeConfig) { c.Admin.Listen, c.Admin.Token = "0.0.0.0:7000", "secret" }, "admin.listen:"},
+		{func(c *nodeConfig) { c.Admin.Cert = "admin.crt" }, "admin: cert and key"},
+		{func(c *nodeConfig) { c.PIDFile = "tapestry.pid" }, "pid-file:"},
+	}
+	for _, test := range tests {
+		config := defaultConfig()
+		test.change(&config)
+		err := config.validate()
+		assert.NotEqual(t, err, nil, test.problem)
+		if err != nil {
+			assert.Contains(t, err.Error(), test.problem)
+		}
+	}
+
+	config = defaultConfig()
+	config.Port, config.Log.Level = -1, "loud"
+	err := config.validate()
+	assert.NotEqual(t, err, nil)
+	if err != nil {
+		assert.Contains(t, err.Error(), "port:")
+		assert.Contains(t, err.Error(), "log.level:")
+	}
+
+	config = defaultConfig()
+	config.Admin.Listen, config.Admin.Token = "localhost:7000", "secret"
+	assert.Equal(t, config.validate(), nil)
+}
+
+// test the config subcommand and its exit codes
+func TestConfigCommand(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "config")
+	defer os.RemoveAll(dir)
+	valid := writeConfig(t, dir, "log:\n  level: error\n")
+
+	tests := []struct {
+		args []string
+		code int
+	}{
+		{[]string{}, exitUsage},
+		{[]string{"check"}, exitUsage},
+		{[]string{"validate", "extra"}, exitUsage},
+		{[]string{"validate", "--unknown"}, exitUsage},
+		{[]string{"validate"}, exitOK},
+		{[]string{"validate", "-config", valid}, exitOK},
+		{[]string{"validate", "-config", valid, "--log-level", "loud"}, exitError},
+		{[]string{"validate", "-config", filepath.Join(dir, "missing.yaml")}, exitUsage},
+	}
+	for _, test := range tests {
+		code, _ := run(t, "config", test.args...)
+		assert.Equal(t, code, test.code, "%v", test.args)
+	}
+}
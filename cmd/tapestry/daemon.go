@@ -0,0 +1,119 @@
+/*
+ *  Brown University, CS138, Spring 2022
+ *
+ *  Purpose: runs a Tapestry node without the interactive shell, for service
+ *  managers such as systemd and for containers. The node reports readiness
+ *  through a PID file and the systemd notification socket, leaves the
+ *  tapestry on SIGTERM and reloads its log level on SIGHUP.
+ */
+
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	tapestry "tapestry/pkg"
+	"time"
+)
+
+// notifySocketEnv is the environment variable through which systemd passes its notification socket
+const notifySocketEnv = "NOTIFY_SOCKET"
+
+// stopTimeout is how long the daemon waits for the server to stop after leaving, before killing it
+const stopTimeout = 10 * time.Second
+
+// runDaemon reports that t is ready and waits for a signal. SIGTERM and SIGINT make the node leave
+// the tapestry, and SIGHUP reloads the log level. Once the node has left, waits for its server to
+// stop, killing the node if it does not within stopTimeout. Returns the process exit code.
+func runDaemon(t *tapestry.Node, config nodeConfig) int {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGTERM, syscall.SIGINT, syscall.SIGHUP)
+	defer signal.Stop(signals)
+
+	if config.PIDFile != "" {
+		if err := ioutil.WriteFile(config.PIDFile, []byte(fmt.Sprintf("%v\n", os.Getpid())), 0644); err != nil {
+			tapestry.Error.Printf("Unable to write the PID file %v: %v\n", config.PIDFile, err)
+			t.Kill()
+			return exitError
+		}
+		defer os.Remove(config.PIDFile)
+	}
+	if err := notify(fmt.Sprintf("READY=1\nSTATUS=Serving %v at %v\nMAINPID=%v", t.Node.ID, t.Node.Address, os.Getpid())); err != nil {
+		tapestry.Error.Printf("Unable to notify the service manager: %v\n", err)
+	}
+	tapestry.Out.Printf("Running as a daemon with PID %v\n", os.Getpid())
+
+	for sig := range signals {
+		if sig == syscall.SIGHUP {
+			reloadLogLevel(config)
+			continue
+		}
+		tapestry.Out.Printf("Received %v, leaving the tapestry\n", sig)
+		notify("STOPPING=1")
+		if err := t.Leave(); err != nil {
+			tapestry.Error.Printf("Unable to leave gracefully: %v\n", err)
+			t.Kill()
+			return exitError
+		}
+		select {
+		case <-t.Stopped():
+		case <-time.After(stopTimeout):
+			tapestry.Error.Printf("The server did not stop within %v, killing it\n", stopTimeout)
+			t.Kill()
+			return exitError
+		}
+		return exitOK
+	}
+	return exitOK
+}
+
+// Set the log level from the log level file if there is one, or else from the configuration file,
+// or else back to the configured level
+func reloadLogLevel(config nodeConfig) {
+	level := config.Log.Level
+	if config.Log.LevelFile != "" {
+		contents, err := ioutil.ReadFile(config.Log.LevelFile)
+		if err != nil {
+			tapestry.Error.Printf("Unable to reload the log level from %v: %v\n", config.Log.LevelFile, err)
+			return
+		}
+		level = strings.TrimSpace(string(contents))
+	} else if config.File != "" {
+		reloaded := defaultConfig()
+		if err := reloaded.load(config.File); err != nil {
+			tapestry.Error.Printf("Unable to reload the log level: %v\n", err)
+			return
+		}
+		level = reloaded.Log.Level
+	}
+	previous, err := tapestry.SetLogLevel(level)
+	if err != nil {
+		tapestry.Error.Printf("Unable to reload the log level: %v\n", err)
+		return
+	}
+	tapestry.Out.Printf("Log level reloaded from %v to %v\n", previous, level)
+}
+
+// Send a state notification to the service manager, if it is listening. See sd_notify(3).
+func notify(state string) error {
+	socket := os.Getenv(notifySocketEnv)
+	if socket == "" {
+		return nil
+	}
+	// Sockets in the abstract namespace are given with a leading @
+	if strings.HasPrefix(socket, "@") {
+		socket = "\x00" + socket[1:]
+	}
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: socket, Net: "unixgram"})
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	_, err = conn.Write([]byte(state))
+	return err
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	tapestry "tapestry/pkg"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Wait up to a second for done to return true
+func eventually(done func() bool) bool {
+	for deadline := time.Now().Add(time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		if done() {
+			return true
+		}
+	}
+	return done()
+}
+
+// test the daemon reports readiness, reloads the log level on SIGHUP, and on SIGTERM leaves the
+// tapestry and returns once the server has stopped
+func TestDaemon(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "daemon")
+	defer os.RemoveAll(dir)
+	socket, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: filepath.Join(dir, "notify"), Net: "unixgram"})
+	assert.Equal(t, err, nil)
+	defer socket.Close()
+	os.Setenv(notifySocketEnv, filepath.Join(dir, "notify"))
+	defer os.Unsetenv(notifySocketEnv)
+	level := tapestry.LogLevel()
+	defer tapestry.SetLogLevel(level)
+
+	tap, err := tapestry.MakeTapestries(true, "1")
+	assert.Equal(t, err, nil)
+	node, err := tapestry.Start(tapestry.MakeID("5"), 0, tap[0].Addr())
+	assert.Equal(t, err, nil)
+	defer tapestry.KillTapestries(tap[0], node)
+
+	config := defaultConfig()
+	config.PIDFile = filepath.Join(dir, "pid")
+	config.Log.LevelFile = filepath.Join(dir, "level")
+	assert.Equal(t, ioutil.WriteFile(config.Log.LevelFile, []byte("error\n"), 0644), nil)
+	exited := make(chan int)
+	go func() {
+		exited <- runDaemon(node, config)
+	}()
+
+	buf := make([]byte, 1024)
+	n, err := socket.Read(buf)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, strings.HasPrefix(string(buf[:n]), "READY=1\n"), true)
+	pid, err := ioutil.ReadFile(config.PIDFile)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, string(pid), fmt.Sprintf("%v\n", os.Getpid()))
+
+	assert.Equal(t, syscall.Kill(os.Getpid(), syscall.SIGHUP), nil)
+	assert.Equal(t, eventually(func() bool { return tapestry.LogLevel() == "error" }), true)
+
+	assert.Equal(t, syscall.Kill(os.Getpid(), syscall.SIGTERM), nil)
+	n, err = socket.Read(buf)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, string(buf[:n]), "STOPPING=1")
+	select {
+	case code := <-exited:
+		assert.Equal(t, code, exitOK)
+	case <-time.After(stopTimeout):
+		t.Fatal("the daemon did not return after SIGTERM")
+	}
+
+	select {
+	case <-node.Stopped():
+	default:
+		t.Error("the daemon returned before the server stopped")
+	}
+	_, err = os.Stat(config.PIDFile)
+	assert.Equal(t, os.IsNotExist(err), true)
+	assert.Equal(t, tap[0].Table.Contains(node.Node), false)
+}
+
+// test the log level is reloaded from the log level file, else from the configuration file
+func TestReloadLogLevel(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "daemon")
+	defer os.RemoveAll(dir)
+	level := tapestry.LogLevel()
+	defer tapestry.SetLogLevel(level)
+
+	config := defaultConfig()
+	config.File = filepath.Join(dir, "tapestry.yaml")
+	assert.Equal(t, ioutil.WriteFile(config.File, []byte("log:\n  level: debug\n"), 0644), nil)
+	reloadLogLevel(config)
+	assert.Equal(t, tapestry.LogLevel(), "debug")
+
+	config.Log.LevelFile = filepath.Join(dir, "level")
+	assert.Equal(t, ioutil.WriteFile(config.Log.LevelFile, []byte("error\n"), 0644), nil)
+	reloadLogLevel(config)
+	assert.Equal(t, tapestry.LogLevel(), "error")
+
+	assert.Equal(t, ioutil.WriteFile(config.Log.LevelFile, []byte("loud\n"), 0644), nil)
+	reloadLogLevel(config)
+	assert.Equal(t, tapestry.LogLevel(), "error")
+}
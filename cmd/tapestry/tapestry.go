@@ -7,10 +7,14 @@
 package main
 
 import (
+	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
+	"os"
 	"strings"
 	tapestry "tapestry/pkg"
+	"time"
 	// xtr "github.com/brown-csci1380/tracing-framework-go/xtrace/client"
 	"github.com/abiosoft/ishell"
 )
@@ -24,50 +28,134 @@ func init() {
 	return
 }
 
+// Subcommands of the tapestry binary. Each returns the process exit code.
+var commands = map[string]func(args []string) int{
+	"shell":  runShell,
+	"get":    runGet,
+	"put":    runPut,
+	"lookup": runLookup,
+	"table":  runTable,
+	"keys":   runKeys,
+	"stat":   runStat,
+
+	"config":    runConfig,
+	"cluster":   runCluster,
+	"bench":     runBench,
+	"published": runPublished,
+	"state":     runState,
+	"topology":  runTopology,
+	"admin":     runAdmin,
+}
+
 func main() {
 	// Uncomment for xtrace
 	// defer xtr.Disconnect()
-	var port int
-	var addr string
-	var debug bool
 
-	flag.IntVar(&port, "port", 0, "The server port to bind to. Defaults to a random port.")
-	flag.IntVar(&port, "p", 0, "The server port to bind to. Defaults to a random port. (shorthand)")
+	// Without a subcommand, start a node and the interactive shell as before
+	name, args := "shell", os.Args[1:]
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		name, args = args[0], args[1:]
+	}
 
-	flag.StringVar(&addr, "connect", "", "An existing node to connect to. If left blank, does not attempt to connect to another node.")
-	flag.StringVar(&addr, "c", "", "An existing node to connect to. If left blank, does not attempt to connect to another node.  (shorthand)")
+	command, ok := commands[name]
+	if !ok {
+		printUsage()
+		os.Exit(exitUsage)
+	}
+	os.Exit(command(args))
+}
 
-	flag.BoolVar(&debug, "debug", false, "Turn on debug message printing.")
-	flag.BoolVar(&debug, "d", false, "Turn on debug message printing. (shorthand)")
+// runShell starts a local node and kicks off the interactive shell, or runs it as a daemon
+func runShell(args []string) int {
+	config, _, err := parseConfig("shell", args)
+	if err != nil {
+		if err != flag.ErrHelp {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		}
+		return exitUsage
+	}
+	if err := config.validate(); err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid configuration:\n%v\n", err)
+		return exitUsage
+	}
 
-	flag.Parse()
+	tapestry.SetLogLevel(config.Log.Level)
+	tapestry.SetRPCTimeout(config.Timeouts.RPC)
+
+	opts, err := config.options()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return exitUsage
+	}
+	opts = append(opts, tapestry.WithPublishFailureHandler(func(status tapestry.PublishStatus, err error) {
+		tapestry.Error.Printf("Unable to republish %v (%v failures): %v\n", status.Key, status.Failures, err)
+	}))
 
-	tapestry.SetDebug(debug)
+	var seeds []string
+	if len(config.Seeds) > 0 {
+		seeds = append(seeds, strings.Join(config.Seeds, ","))
+	}
+	if config.SeedFile != "" {
+		seeds = append(seeds, config.SeedFile)
+	}
+	if config.SRV != "" {
+		seeds = append(seeds, config.SRV)
+	}
+	connectTo := strings.Join(seeds, ", ")
 
+	listen, port := config.Listen, config.Port
 	switch {
-	case port != 0 && addr != "":
-		tapestry.Out.Printf("Starting a node on port %v and connecting to %v\n", port, addr)
+	case listen != "" && connectTo != "":
+		tapestry.Out.Printf("Starting a node on %v and connecting to %v\n", listen, connectTo)
+	case listen != "":
+		tapestry.Out.Printf("Starting a standalone node on %v\n", listen)
+	case port != 0 && connectTo != "":
+		tapestry.Out.Printf("Starting a node on port %v and connecting to %v\n", port, connectTo)
 	case port != 0:
 		tapestry.Out.Printf("Starting a standalone node on port %v\n", port)
-	case addr != "":
-		tapestry.Out.Printf("Starting a node on a random port and connecting to %v\n", addr)
+	case connectTo != "":
+		tapestry.Out.Printf("Starting a node on a random port and connecting to %v\n", connectTo)
 	default:
 		tapestry.Out.Printf("Starting a standalone node on a random port\n")
 	}
 
-	t, err := tapestry.Start(tapestry.RandomID(), port, addr)
+	id, fixed, err := config.nodeID()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return exitError
+	}
+	if !fixed {
+		id = tapestry.RandomID()
+	}
+	t, err := tapestry.Start(id, port, "", opts...)
+
+	// Another node already owns our randomly chosen ID, so pick a fresh one and try again. A
+	// configured ID is kept, so the collision is reported instead.
+	var collision *tapestry.IDCollisionError
+	for i := 0; !fixed && i < config.Timeouts.Retries && errors.As(err, &collision); i++ {
+		tapestry.Out.Printf("ID %v is already in use by %v, retrying with a new ID\n", collision.ID, collision.Existing.Address)
+		t, err = tapestry.Start(tapestry.RandomID(), port, "", opts...)
+	}
 
 	if err != nil {
 		fmt.Printf("Error starting tapestry node: %v\n", err)
-		return
+		return exitError
 	}
 
 	tapestry.Out.Printf("Successfully started: %v\n", t)
+	if err := config.saveID(t.Node.ID); err != nil {
+		tapestry.Error.Printf("Unable to keep the node ID in %v: %v\n", config.idFile(), err)
+	}
+
+	if config.Daemon {
+		return runDaemon(t, config)
+	}
 
 	// Kick off CLI, await exit
 	CLI(t)
 
 	tapestry.Out.Println("Closing tapestry")
+	return exitOK
 }
 
 // CLI starts the CLI
@@ -103,6 +191,18 @@ func CLI(t *tapestry.Node) {
 		},
 	})
 
+	shell.AddCmd(&ishell.Cmd{
+		Name: "snapshot",
+		Func: func(c *ishell.Context) {
+			snapshot, err := json.MarshalIndent(t.Snapshot(true), "", "  ")
+			if err != nil {
+				c.Err(err)
+				return
+			}
+			c.Println(string(snapshot))
+		},
+	})
+
 	shell.AddCmd(&ishell.Cmd{
 		Name: "leave",
 		Func: func(c *ishell.Context) {
@@ -111,6 +211,17 @@ func CLI(t *tapestry.Node) {
 		},
 	})
 
+	shell.AddCmd(&ishell.Cmd{
+		Name: "drain",
+		Func: func(c *ishell.Context) {
+			if err := t.Drain(); err != nil {
+				c.Err(err)
+				return
+			}
+			c.Println("Drained the local node and left the tapestry")
+		},
+	})
+
 	shell.AddCmd(&ishell.Cmd{
 		Name: "kill",
 		Func: func(c *ishell.Context) {
@@ -126,14 +237,61 @@ func CLI(t *tapestry.Node) {
 		},
 	})
 
+	shell.AddCmd(&ishell.Cmd{
+		Name: "published",
+		Func: func(c *ishell.Context) {
+			c.Println(t.PublishedKeysToString())
+		},
+	})
+
+	shell.AddCmd(&ishell.Cmd{
+		Name: "keys",
+		Func: func(c *ishell.Context) {
+			if len(c.Args) > 1 {
+				c.Println("USAGE: keys [prefix]")
+				return
+			}
+			prefix := ""
+			if len(c.Args) == 1 {
+				prefix = c.Args[0]
+			}
+			count := 0
+			token := ""
+			for {
+				keys, next, err := t.ListKeys(prefix, token)
+				if err != nil {
+					c.Err(err)
+					return
+				}
+				for _, key := range keys {
+					c.Println(key)
+				}
+				count += len(keys)
+				if next == "" {
+					break
+				}
+				token = next
+			}
+			c.Printf("%v keys\n", count)
+		},
+	})
+
 	shell.AddCmd(&ishell.Cmd{
 		Name: "put",
 		Func: func(c *ishell.Context) {
-			if len(c.Args) != 2 {
-				c.Println("USAGE: put <key> <value>")
+			if len(c.Args) != 2 && len(c.Args) != 3 {
+				c.Println("USAGE: put <key> <value> [ttl]")
 				return
 			}
-			err := t.Store(c.Args[0], []byte(c.Args[1]))
+			var ttl time.Duration
+			if len(c.Args) == 3 {
+				var err error
+				if ttl, err = time.ParseDuration(c.Args[2]); err != nil {
+					c.Err(err)
+					return
+				}
+			}
+			err := t.Store(c.Args[0], []byte(c.Args[1]), tapestry.WithTTL(ttl))
 			if err != nil {
 				c.Err(err)
 				return
@@ -142,6 +300,27 @@ func CLI(t *tapestry.Node) {
 		},
 	})
 
+	shell.AddCmd(&ishell.Cmd{
+		Name: "putif",
+		Func: func(c *ishell.Context) {
+			if len(c.Args) != 3 {
+				c.Println("USAGE: putif <key> <v> <value>")
+				return
+			}
+			expected, err := tapestry.ParseVersion(c.Args[1])
+			if err != nil {
+				c.Err(err)
+				return
+			}
+			version, err := t.StoreIf(c.Args[0], []byte(c.Args[2]), expected)
+			if err != nil {
+				c.Err(err)
+				return
+			}
+			c.Printf("Successfully stored value (%v) at key (%v) with version %v\n", c.Args[2], c.Args[0], version)
+		},
+	})
+
 	shell.AddCmd(&ishell.Cmd{
 		Name: "lookup",
 		Func: func(c *ishell.Context) {
@@ -149,7 +328,7 @@ func CLI(t *tapestry.Node) {
 				c.Println("USAGE: lookup <key>")
 				return
 			}
-			replicas, err := t.Lookup(c.Args[0])
+			replicas, err := t.LookupVersions(c.Args[0])
 			if err != nil {
 				c.Err(err)
 				return
@@ -174,6 +353,22 @@ func CLI(t *tapestry.Node) {
 		},
 	})
 
+	shell.AddCmd(&ishell.Cmd{
+		Name: "stat",
+		Func: func(c *ishell.Context) {
+			if len(c.Args) != 1 {
+				c.Println("USAGE: stat <key>")
+				return
+			}
+			metadata, err := t.Stat(c.Args[0])
+			if err != nil {
+				c.Err(err)
+				return
+			}
+			c.Printf("%v: %v\n", c.Args[0], metadata)
+		},
+	})
+
 	shell.AddCmd(&ishell.Cmd{
 		Name: "remove",
 		Func: func(c *ishell.Context) {
@@ -226,16 +421,22 @@ func printHelp(shell *ishell.Shell) {
 	shell.Println(" - table                   Prints this node's routing table")
 	shell.Println(" - backpointers            Prints this node's backpointers")
 	shell.Println(" - replicas                Prints the advertised objects that are registered to this node")
+	shell.Println(" - snapshot                Prints this node's routing table, backpointers and location maps as JSON")
 	shell.Println("")
-	shell.Println(" - put <key> <value>       Stores the provided key-value pair on the local node and advertises the key to the tapestry")
-	shell.Println(" - lookup <key>            Looks up the specified key in the tapestry and prints its location")
+	shell.Println(" - put <key> <value> [ttl] Stores the provided key-value pair on the local node and advertises the key to the tapestry, expiring after ttl if given")
+	shell.Println(" - putif <key> <v> <value> Stores the provided value if v is the newest version of the key (0 if it must not exist)")
+	shell.Println(" - lookup <key>            Looks up the specified key in the tapestry and prints its locations and their versions")
 	shell.Println(" - get <key>               Looks up the specified key in the tapestry, then fetches the value from one of the replicas")
+	shell.Println(" - stat <key>              Looks up the specified key in the tapestry, then fetches its metadata from one of the replicas")
 	shell.Println(" - remove <key>            Remove the specified key from the tapestry")
-	shell.Println(" - list                    List the blobs being stored and advertised by the local node")
+	shell.Println(" - list                    List the blobs being stored and advertised by the local node, with their metadata")
+	shell.Println(" - keys [prefix]           List the keys advertised anywhere in the tapestry, optionally by prefix")
+	shell.Println(" - published               Show the root, last registration and failures of each key the local node publishes")
 	shell.Println("")
 	shell.Println(" - debug on|off            Turn debug on or off.  Off by default")
 	shell.Println("")
 	shell.Println(" - leave                   Instructs the local node to gracefully leave the tapestry")
+	shell.Println(" - drain                   Hands off the blobs and keys of the local node, then leaves the tapestry")
 	shell.Println(" - kill                    Leaves the tapestry without graceful exit")
 	shell.Println(" - exit                    Quit this CLI")
 }
@@ -8,6 +8,7 @@ require (
 	golang.org/x/net v0.0.0-20201224014010-6772e930b67b
 	google.golang.org/grpc v1.35.0
 	google.golang.org/protobuf v1.27.1
+	gopkg.in/yaml.v2 v2.2.2
 )
 
 require (
@@ -24,5 +25,4 @@ require (
 	golang.org/x/sys v0.0.0-20201119102817-f84b799fce68 // indirect
 	golang.org/x/text v0.3.3 // indirect
 	google.golang.org/genproto v0.0.0-20200526211855-cb27e3aa2013 // indirect
-	gopkg.in/yaml.v2 v2.2.2 // indirect
 )
@@ -0,0 +1,104 @@
+/*
+ *  Brown University, CS138, Spring 2022
+ *
+ *  Purpose: Resolves the address a node binds to and the address it
+ *  advertises to the rest of the Tapestry mesh.
+ */
+
+package pkg
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// UNIXPREFIX marks an address as a unix-domain socket path, e.g. unix:/var/run/tapestry.sock
+const UNIXPREFIX = "unix:"
+
+// Listen on the given address. Addresses are host:port pairs (IPv6 hosts in brackets),
+// or unix-domain socket paths prefixed with unix: or unix://
+func listen(addr string) (net.Listener, error) {
+	if path, ok := unixPath(addr); ok {
+		return net.Listen("unix", path)
+	}
+	return net.Listen("tcp", addr)
+}
+
+// Works out the address other nodes should use to reach a listener.
+//
+// - If an advertise address is given it is used as is, with the bound port filled in if it has none
+// - Unix-domain sockets advertise their absolute path
+// - TCP listeners on a specific IP advertise that IP, otherwise the hostname of this machine
+func advertiseAddress(lis net.Listener, advertise string) (string, error) {
+	if path, ok := unixPath(advertise); ok {
+		return unixAddress(path)
+	}
+	if lis.Addr().Network() == "unix" {
+		if advertise != "" {
+			return advertise, nil
+		}
+		return unixAddress(lis.Addr().String())
+	}
+
+	// Get the port we are bound to
+	host, port, err := net.SplitHostPort(lis.Addr().String())
+	if err != nil {
+		return "", err
+	}
+
+	if advertise != "" {
+		if _, _, err := net.SplitHostPort(advertise); err == nil {
+			return advertise, nil
+		}
+		// No port given, so advertise the one we are bound to
+		return net.JoinHostPort(strings.Trim(advertise, "[]"), port), nil
+	}
+
+	if ip := net.ParseIP(host); ip != nil && !ip.IsUnspecified() {
+		return net.JoinHostPort(host, port), nil
+	}
+
+	// Get the hostname of this machine. If other nodes cannot resolve it, set an advertise address instead.
+	name, err := os.Hostname()
+	if err != nil {
+		return "", fmt.Errorf("Unable to get hostname of local machine to start Tapestry node. Reason: %v", err)
+	}
+	return net.JoinHostPort(name, port), nil
+}
+
+// Formats a socket path as an absolute unix: address
+func unixAddress(path string) (string, error) {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	return UNIXPREFIX + path, nil
+}
+
+// Returns the socket path if addr names a unix-domain socket
+func unixPath(addr string) (string, bool) {
+	if !strings.HasPrefix(addr, UNIXPREFIX) {
+		return "", false
+	}
+	return strings.TrimPrefix(strings.TrimPrefix(addr, UNIXPREFIX), "//"), true
+}
+
+// IsLoopback reports whether addr can only be reached from this machine: a unix-domain socket, or
+// a host:port pair whose host is localhost or a loopback IP
+func IsLoopback(addr string) bool {
+	if _, ok := unixPath(addr); ok {
+		return true
+	}
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
@@ -0,0 +1,249 @@
+/*
+ *  Brown University, CS138, Spring 2022
+ *
+ *  Purpose: Serves the admin service, through which operators inspect and
+ *  control a node from another machine, and provides a client for it. The
+ *  service runs on its own address, separately from the peer protocol, and
+ *  every call must present the node's admin token.
+ */
+
+package pkg
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"strings"
+	"time"
+
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// ADMINTOKENPREFIX precedes the admin token in the authorization metadata of admin calls
+const ADMINTOKENPREFIX = "Bearer "
+
+// Implements the admin service for the local node
+type adminServer struct {
+	UnimplementedTapestryAdminServer
+	local *Node
+}
+
+// Start serving the admin service in the background. The server is stopped when the node exits.
+func (local *Node) serveAdmin(config startConfig) error {
+	if config.adminToken == "" {
+		return fmt.Errorf("the admin service requires a token")
+	}
+	if config.adminCert == "" && !IsLoopback(config.admin) {
+		return fmt.Errorf("the admin service on %v requires TLS, as it is not a loopback address", config.admin)
+	}
+	options := []grpc.ServerOption{grpc.UnaryInterceptor(adminAuthInterceptor(config.adminToken))}
+	if config.adminCert != "" {
+		creds, err := credentials.NewServerTLSFromFile(config.adminCert, config.adminKey)
+		if err != nil {
+			return err
+		}
+		options = append(options, grpc.Creds(creds))
+	}
+
+	lis, err := listen(config.admin)
+	if err != nil {
+		return err
+	}
+	local.adminServer = grpc.NewServer(options...)
+	local.adminAddr = lis.Addr().String()
+	RegisterTapestryAdminServer(local.adminServer, &adminServer{local: local})
+	go local.adminServer.Serve(lis)
+	Out.Printf("Serving admin service on %v\n", local.adminAddr)
+	return nil
+}
+
+// AdminAddr returns the address the admin service is bound to, or an empty string if it is not served
+func (local *Node) AdminAddr() string {
+	return local.adminAddr
+}
+
+// Rejects admin calls that do not present the token
+func adminAuthInterceptor(token string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		presented := ""
+		if values := md.Get("authorization"); len(values) > 0 {
+			presented = strings.TrimPrefix(values[0], ADMINTOKENPREFIX)
+		}
+		if subtle.ConstantTimeCompare([]byte(presented), []byte(token)) != 1 {
+			return nil, status.Error(codes.Unauthenticated, "invalid admin token")
+		}
+		return handler(ctx, req)
+	}
+}
+
+func (s *adminServer) GetRoutingTable(ctx context.Context, req *AdminRequest) (*RoutingTableMsg, error) {
+	return &RoutingTableMsg{
+		Node:    s.local.Node.toNodeMsg(),
+		Entries: routingEntriesToRoutingEntryMsgs(s.local.Table.Entries()),
+	}, nil
+}
+
+func (s *adminServer) GetBackpointers(ctx context.Context, req *AdminRequest) (*BackpointersMsg, error) {
+	return &BackpointersMsg{
+		Node:         s.local.Node.toNodeMsg(),
+		Backpointers: backpointerEntriesToBackpointerMsgs(s.local.Backpointers.Entries()),
+	}, nil
+}
+
+func (s *adminServer) GetLocationMap(ctx context.Context, req *AdminRequest) (*LocationMapMsg, error) {
+	return &LocationMapMsg{
+		Node:            s.local.Node.toNodeMsg(),
+		Locations:       locationEntriesToLocationEntryMsgs(s.local.LocationsByKey.Entries()),
+		BackupLocations: locationEntriesToLocationEntryMsgs(s.local.BackupLocations.Entries()),
+	}, nil
+}
+
+func (s *adminServer) ListBlobs(ctx context.Context, req *AdminRequest) (*BlobList, error) {
+	blobs := s.local.blobstore.List()
+	rsp := &BlobList{Blobs: make([]*BlobInfoMsg, len(blobs))}
+	for i, blob := range blobs {
+		rsp.Blobs[i] = &BlobInfoMsg{Key: blob.Key, Metadata: blob.Metadata.toMetadataMsg()}
+	}
+	return rsp, nil
+}
+
+func (s *adminServer) SetLogLevel(ctx context.Context, req *LogLevelRequest) (*LogLevelReply, error) {
+	previous, err := SetLogLevel(req.Level)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	Out.Printf("Log level changed from %v to %v by an admin\n", previous, req.Level)
+	return &LogLevelReply{Previous: previous}, nil
+}
+
+func (s *adminServer) Leave(ctx context.Context, req *AdminRequest) (*AdminReply, error) {
+	return &AdminReply{}, s.local.Leave()
+}
+
+func (s *adminServer) Drain(ctx context.Context, req *AdminRequest) (*AdminReply, error) {
+	return &AdminReply{}, s.local.Drain()
+}
+
+// Presents the admin token with every call. The token may only be sent in the clear to a loopback
+// address.
+type adminToken struct {
+	token    string
+	loopback bool
+}
+
+func (t adminToken) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	return map[string]string{"authorization": ADMINTOKENPREFIX + t.token}, nil
+}
+
+func (t adminToken) RequireTransportSecurity() bool {
+	return !t.loopback
+}
+
+// AdminClient calls the admin service of a node
+type AdminClient struct {
+	conn   *grpc.ClientConn
+	client TapestryAdminClient
+}
+
+// DialAdmin connects to the admin service of a node at addr, presenting token. If caFile is not
+// empty, the connection uses TLS, and the certificate of the service must be signed by the
+// certificate authority in caFile. caFile may only be empty if addr is a loopback address.
+func DialAdmin(addr string, token string, caFile string) (*AdminClient, error) {
+	loopback := IsLoopback(addr)
+	if caFile == "" && !loopback {
+		return nil, fmt.Errorf("the admin service at %v requires TLS, as it is not a loopback address", addr)
+	}
+	options := []grpc.DialOption{
+		grpc.WithPerRPCCredentials(adminToken{token: token, loopback: loopback}),
+		grpc.WithUnaryInterceptor(clientUnaryInterceptor),
+	}
+	if caFile != "" {
+		creds, err := credentials.NewClientTLSFromFile(caFile, "")
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, grpc.WithTransportCredentials(creds))
+	} else {
+		options = append(options, grpc.WithInsecure())
+	}
+	conn, err := grpc.Dial(addr, options...)
+	if err != nil {
+		return nil, err
+	}
+	return &AdminClient{conn: conn, client: NewTapestryAdminClient(conn)}, nil
+}
+
+// Close the connection to the admin service
+func (admin *AdminClient) Close() error {
+	return admin.conn.Close()
+}
+
+// RoutingTable fetches the routing table of the node
+func (admin *AdminClient) RoutingTable() ([]RoutingEntry, error) {
+	rsp, err := admin.client.GetRoutingTable(context.Background(), &AdminRequest{})
+	if err != nil {
+		return nil, err
+	}
+	return routingEntryMsgsToRoutingEntries(rsp.Entries), nil
+}
+
+// Backpointers fetches the backpointers of the node
+func (admin *AdminClient) Backpointers() ([]BackpointerEntry, error) {
+	rsp, err := admin.client.GetBackpointers(context.Background(), &AdminRequest{})
+	if err != nil {
+		return nil, err
+	}
+	return backpointerMsgsToBackpointerEntries(rsp.Backpointers), nil
+}
+
+// LocationMap fetches the registrations of the keys the node is root of, and of the keys it is a
+// backup root of
+func (admin *AdminClient) LocationMap() (locations []LocationEntry, backups []LocationEntry, err error) {
+	rsp, err := admin.client.GetLocationMap(context.Background(), &AdminRequest{})
+	if err != nil {
+		return nil, nil, err
+	}
+	return locationEntryMsgsToLocationEntries(rsp.Locations), locationEntryMsgsToLocationEntries(rsp.BackupLocations), nil
+}
+
+// ListBlobs fetches the key and metadata of every blob stored on the node
+func (admin *AdminClient) ListBlobs() ([]BlobInfo, error) {
+	rsp, err := admin.client.ListBlobs(context.Background(), &AdminRequest{})
+	if err != nil {
+		return nil, err
+	}
+	blobs := make([]BlobInfo, len(rsp.Blobs))
+	for i, blob := range rsp.Blobs {
+		blobs[i] = BlobInfo{Key: blob.Key, Metadata: blob.Metadata.toMetadata()}
+	}
+	return blobs, nil
+}
+
+// SetLogLevel sets the log level of the node's process, returning the previous level. See SetLogLevel.
+func (admin *AdminClient) SetLogLevel(level string) (previous string, err error) {
+	rsp, err := admin.client.SetLogLevel(context.Background(), &LogLevelRequest{Level: level})
+	if err != nil {
+		return "", err
+	}
+	return rsp.Previous, nil
+}
+
+// Leave makes the node leave the tapestry gracefully
+func (admin *AdminClient) Leave() error {
+	_, err := admin.client.Leave(context.Background(), &AdminRequest{})
+	return err
+}
+
+// Drain hands off the blobs and keys of the node to other nodes, then stops it. Waits up to timeout
+// for the node to drain. See Node.Drain.
+func (admin *AdminClient) Drain(timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	_, err := admin.client.Drain(ctx, &AdminRequest{})
+	return err
+}
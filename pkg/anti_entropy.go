@@ -0,0 +1,187 @@
+/*
+ *  Brown University, CS138, Spring 2022
+ *
+ *  Purpose: Periodically compares the location map of the local node with
+ *  those of its nearest neighbours, and transfers registrations of keys the
+ *  local node is not the root of to their true roots, for example after a
+ *  network partition heals.
+ */
+
+package pkg
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ANTIENTROPY is how often each node reconciles its location map with its neighbours by default
+const ANTIENTROPY = 30 * time.Second
+
+// ANTIENTROPYPEERS is how many neighbours each node reconciles its location map with
+const ANTIENTROPYPEERS = 3
+
+// ANTIENTROPYDEPTH is how many digits of the key hashes summaries are compared to, before the
+// keys under the prefixes that differ are checked one by one
+const ANTIENTROPYDEPTH = 2
+
+// reconciler runs anti-entropy rounds on the local node
+type reconciler struct {
+	local    *Node
+	interval time.Duration
+	stop     chan bool
+	stopOnce sync.Once
+}
+
+func newReconciler(local *Node, interval time.Duration) *reconciler {
+	if interval <= 0 {
+		interval = ANTIENTROPY
+	}
+	return &reconciler{local: local, interval: interval, stop: make(chan bool)}
+}
+
+// Reconcile every interval until stopped
+func (r *reconciler) run() {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if moved, err := r.local.Reconcile(); err != nil {
+				Debug.Printf("Anti-entropy moved %v keys, with error: %v\n", moved, err)
+			}
+		case <-r.stop:
+			return
+		}
+	}
+}
+
+// Stop reconciling
+func (r *reconciler) close() {
+	r.stopOnce.Do(func() {
+		close(r.stop)
+	})
+}
+
+// Reconcile runs a round of anti-entropy. The summaries of the local location map are compared with
+// those of the ANTIENTROPYPEERS nodes in the routing table that share the longest prefix with the
+// local node, which are the nodes most likely to share root responsibility with it. Of the keys under
+// a prefix whose summaries differ, those the local routing table no longer makes the local node the
+// root of have their registrations transferred to the next hop towards their root, which moves them
+// on in turn if it is not the root either. Returns how many keys were transferred.
+func (local *Node) Reconcile() (moved int, err error) {
+	candidates := make(map[string]bool)
+	for _, peer := range local.antiEntropyPeers() {
+		keys, diffErr := local.diff(peer, "")
+		if diffErr != nil {
+			Debug.Printf("Unable to compare location maps with %v: %v\n", peer, diffErr)
+			local.RemoveBadNodes([]RemoteNode{peer})
+			continue
+		}
+		for _, key := range keys {
+			candidates[key] = true
+		}
+	}
+	if len(candidates) == 0 {
+		return 0, nil
+	}
+	keys := make([]string, 0, len(candidates))
+	for key := range candidates {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	// Rootedness is decided by the local routing table alone, without asking other nodes
+	byHop := make(map[RemoteNode]map[string][]Replica)
+	for _, key := range keys {
+		hop := local.Table.FindNextHop(Hash(key), 0)
+		if hop == local.Node {
+			continue
+		}
+		if replicas := local.LocationsByKey.Versions(key); len(replicas) > 0 {
+			if byHop[hop] == nil {
+				byHop[hop] = make(map[string][]Replica)
+			}
+			byHop[hop][key] = replicas
+		}
+	}
+	for hop, data := range byHop {
+		if transferErr := hop.TransferRPC(local.Node, data); transferErr != nil {
+			err = fmt.Errorf("transfer %v keys to %v: %v", len(data), hop, transferErr)
+			continue
+		}
+		Debug.Printf("Transferred %v misplaced keys to %v\n", len(data), hop)
+		for key, replicas := range data {
+			for _, replica := range replicas {
+				local.Unregister(key, replica.Node)
+			}
+		}
+		moved += len(data)
+	}
+	return moved, err
+}
+
+// The nodes in the routing table sharing the longest prefix with the local node, the nearest first
+func (local *Node) antiEntropyPeers() []RemoteNode {
+	seen := map[RemoteNode]bool{local.Node: true}
+	var peers []RemoteNode
+	for _, entry := range local.Table.Entries() {
+		if !seen[entry.Node] {
+			seen[entry.Node] = true
+			peers = append(peers, entry.Node)
+		}
+	}
+	sort.Slice(peers, func(i, j int) bool {
+		first := SharedPrefixLength(local.Node.ID, peers[i].ID)
+		second := SharedPrefixLength(local.Node.ID, peers[j].ID)
+		if first != second {
+			return first > second
+		}
+		return local.Node.ID.Closer(peers[i].ID, peers[j].ID)
+	})
+	if len(peers) > ANTIENTROPYPEERS {
+		peers = peers[:ANTIENTROPYPEERS]
+	}
+	return peers
+}
+
+// The local keys under prefix whose registrations may differ from those held by peer. Summaries are
+// compared digit by digit, down to ANTIENTROPYDEPTH digits. Under a prefix the peer holds no
+// registrations for, only the keys the local routing table makes another node the root of are kept.
+func (local *Node) diff(peer RemoteNode, prefix string) (keys []string, err error) {
+	theirs, err := peer.SummaryRPC(prefix)
+	if err != nil {
+		return nil, err
+	}
+	if len(theirs) != BASE {
+		return nil, fmt.Errorf("summary of %v from %v has %v digests", prefix, peer, len(theirs))
+	}
+	ours := local.LocationsByKey.Summary(prefix)
+	for digit := 0; digit < BASE; digit++ {
+		// Keys held only by the peer are for the peer to move
+		if ours[digit] == nil || bytes.Equal(ours[digit], theirs[digit]) {
+			continue
+		}
+		child := prefix + Digit(digit).String()
+		if theirs[digit] == nil {
+			for _, key := range local.LocationsByKey.KeysWithHashPrefix(child) {
+				if local.Table.FindNextHop(Hash(key), 0) != local.Node {
+					keys = append(keys, key)
+				}
+			}
+			continue
+		}
+		if len(child) >= ANTIENTROPYDEPTH {
+			keys = append(keys, local.LocationsByKey.KeysWithHashPrefix(child)...)
+			continue
+		}
+		more, err := local.diff(peer, child)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, more...)
+	}
+	return keys, nil
+}
@@ -8,6 +8,7 @@
 package pkg
 
 import (
+	"sort"
 	"sync"
 )
 
@@ -62,6 +63,27 @@ func (b *Backpointers) Get(level int) []RemoteNode {
 	return b.sets[level].Nodes()
 }
 
+// BackpointerEntry is a backpointer along with its level, the length of the prefix it shares with the local node
+type BackpointerEntry struct {
+	Level int        `json:"level"`
+	Node  RemoteNode `json:"node"`
+}
+
+// Entries returns every backpointer, ordered by level and then by ID
+func (b *Backpointers) Entries() (entries []BackpointerEntry) {
+	entries = make([]BackpointerEntry, 0)
+	for level := range b.sets {
+		nodes := b.sets[level].Nodes()
+		sort.Slice(nodes, func(i, j int) bool {
+			return nodes[i].ID.String() < nodes[j].ID.String()
+		})
+		for _, node := range nodes {
+			entries = append(entries, BackpointerEntry{level, node})
+		}
+	}
+	return entries
+}
+
 // Gets the node set for the level that the specified node should occupy.
 func (b *Backpointers) level(node RemoteNode) *NodeSet {
 	return b.sets[SharedPrefixLength(b.local.ID, node.ID)]
@@ -0,0 +1,214 @@
+/*
+ *  Brown University, CS138, Spring 2022
+ *
+ *  Purpose: Mirrors the registrations made with a root to the surrogate roots
+ *  that would take over its keys if it disappeared, so that keys stay
+ *  findable when a root crashes, and lets a backup root take over the
+ *  mirrored registrations once it becomes root.
+ */
+
+package pkg
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// BACKUPROOTS is how many surrogate roots each root mirrors its registrations to by default
+const BACKUPROOTS = 2
+
+// MIRRORRETRY is how long the mirrorer waits before sending again the entries a backup root failed to receive
+const MIRRORRETRY = time.Second
+
+// A registration or unregistration made with the local node as root
+type mirrorEntry struct {
+	key     string
+	replica Replica
+	removed bool
+}
+
+// mirrorer sends the registrations made with the local node to the backup roots of their keys.
+// Entries are sent in batches from a single goroutine, so each backup root receives them in order.
+type mirrorer struct {
+	local    *Node
+	backups  int // How many backup roots each key is mirrored to
+	pending  []mirrorEntry
+	failed   []mirrorEntry // Entries a backup root failed to receive, queued again after MIRRORRETRY
+	retrying bool          // Whether the failed entries are due to be queued again
+	wake     chan bool     // Receives a value when entries are pending
+	stop     chan bool
+	stopOnce sync.Once
+	mutex    sync.Mutex
+}
+
+func newMirrorer(local *Node, backups int) *mirrorer {
+	return &mirrorer{
+		local:   local,
+		backups: backups,
+		wake:    make(chan bool, 1),
+		stop:    make(chan bool),
+	}
+}
+
+// Send pending entries until stopped
+func (m *mirrorer) run() {
+	for {
+		select {
+		case <-m.wake:
+			m.flush()
+		case <-m.stop:
+			return
+		}
+	}
+}
+
+// Stop mirroring. The mirrors time out at the backup roots.
+func (m *mirrorer) close() {
+	m.stopOnce.Do(func() {
+		close(m.stop)
+	})
+}
+
+// Queue a registration, or unregistration if removed, to be mirrored to the backup roots of key
+func (m *mirrorer) add(key string, replica Replica, removed bool) {
+	if m.backups <= 0 {
+		return
+	}
+	m.mutex.Lock()
+	// A failed entry for the same replica is out of date once this one is sent
+	failed := m.failed[:0]
+	for _, entry := range m.failed {
+		if entry.key != key || entry.replica.Node != replica.Node {
+			failed = append(failed, entry)
+		}
+	}
+	m.failed = failed
+	m.pending = append(m.pending, mirrorEntry{key, replica, removed})
+	m.mutex.Unlock()
+	m.signal()
+}
+
+// Wake up the goroutine sending pending entries
+func (m *mirrorer) signal() {
+	select {
+	case m.wake <- true:
+	default:
+	}
+}
+
+// Keep entries a backup root failed to receive, and queue them again after MIRRORRETRY. They are
+// sent to the backup roots of their keys at that time, which no longer include a root that failed.
+func (m *mirrorer) fail(entries []mirrorEntry) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	queued := make(map[mirrorEntry]bool, len(m.failed))
+	for _, entry := range m.failed {
+		queued[entry] = true
+	}
+	for _, entry := range entries {
+		if !queued[entry] {
+			queued[entry] = true
+			m.failed = append(m.failed, entry)
+		}
+	}
+	if m.retrying {
+		return
+	}
+	m.retrying = true
+	time.AfterFunc(MIRRORRETRY, func() {
+		m.mutex.Lock()
+		m.pending = append(m.failed, m.pending...)
+		m.failed, m.retrying = nil, false
+		m.mutex.Unlock()
+		m.signal()
+	})
+}
+
+// Send the pending entries to their backup roots, one batch of up to BATCHSIZE entries at a time
+// per backup root
+func (m *mirrorer) flush() {
+	for {
+		m.mutex.Lock()
+		entries := m.pending
+		m.pending = nil
+		m.mutex.Unlock()
+		if len(entries) == 0 {
+			return
+		}
+
+		byBackup := make(map[RemoteNode][]mirrorEntry)
+		for _, entry := range entries {
+			for _, backup := range m.local.backupRoots(entry.key, m.backups) {
+				byBackup[backup] = append(byBackup[backup], entry)
+			}
+		}
+		backups := make([]RemoteNode, 0, len(byBackup))
+		for backup := range byBackup {
+			backups = append(backups, backup)
+		}
+		forEach(len(backups), func(i int) {
+			backup, entries := backups[i], byBackup[backups[i]]
+			for len(entries) > 0 {
+				n := len(entries)
+				if n > BATCHSIZE {
+					n = BATCHSIZE
+				}
+				if err := backup.MirrorRPC(m.local.Node, entries[:n]); err != nil {
+					Debug.Printf("Unable to mirror %v registrations to %v: %v\n", len(entries), backup, err)
+					m.local.RemoveBadNodes([]RemoteNode{backup})
+					m.fail(entries)
+					return
+				}
+				entries = entries[n:]
+			}
+		})
+	}
+}
+
+// The nodes that would become root of key, in order, if the local node disappeared. They are the
+// nodes in the routing table that surrogate routing prefers to any other, after the local node.
+func (local *Node) backupRoots(key string, k int) []RemoteNode {
+	id := Hash(key)
+	seen := map[RemoteNode]bool{local.Node: true}
+	var candidates []RemoteNode
+	for _, entry := range local.Table.Entries() {
+		if !seen[entry.Node] {
+			seen[entry.Node] = true
+			candidates = append(candidates, entry.Node)
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		return id.IsNewRoute(candidates[i].ID, candidates[j].ID)
+	})
+	if len(candidates) > k {
+		candidates = candidates[:k]
+	}
+	return candidates
+}
+
+// Record registrations made with another root, from which the local node may take over
+// the keys if that root disappears
+func (local *Node) mirror(from RemoteNode, entries []mirrorEntry) {
+	Debug.Printf("Mirroring %v registrations from %v\n", len(entries), from)
+	for _, entry := range entries {
+		if entry.removed {
+			local.BackupLocations.Unregister(entry.key, entry.replica.Node)
+		} else {
+			local.BackupLocations.Register(entry.key, entry.replica, local.timeout)
+		}
+	}
+}
+
+// Take over the mirrored registrations of key, now that the local node is its root. Replicas that
+// have already registered with the local node keep their registration.
+func (local *Node) promote(key string) {
+	replicas := local.BackupLocations.Take(key)
+	if len(replicas) == 0 {
+		return
+	}
+	Debug.Printf("Taking over %v mirrored registrations of %v\n", len(replicas), key)
+	for _, replica := range local.LocationsByKey.RegisterMissing(key, replicas, local.timeout) {
+		local.mirrorer.add(key, replica, false)
+	}
+}
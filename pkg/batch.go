@@ -0,0 +1,424 @@
+/*
+ *  Brown University, CS138, Spring 2022
+ *
+ *  Purpose: Stores, gets and looks up many keys at once. Keys are grouped by
+ *  their root, so that each root is sent a single batch of registrations or
+ *  fetches, and the work is spread over a bounded number of goroutines.
+ */
+
+package pkg
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// BATCHCONCURRENCY is the most RPCs a batch operation makes at once
+const BATCHCONCURRENCY = 16
+
+// BATCHSIZE is the most keys sent to a node in a single batch RPC
+const BATCHSIZE = 100
+
+// BATCHBYTES is roughly the most blob bytes sent to a node in a single batch RPC.
+// A blob larger than this is sent on its own.
+const BATCHBYTES = 1 << 20
+
+// BatchError is returned by the batch operations when some keys failed. Keys that are not listed succeeded.
+type BatchError struct {
+	Errors map[string]error // The error of each key that failed
+}
+
+func (e *BatchError) Error() string {
+	keys := make([]string, 0, len(e.Errors))
+	for key := range e.Errors {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return fmt.Sprintf("%v keys failed, first %v: %v", len(keys), keys[0], e.Errors[keys[0]])
+}
+
+// Returns a BatchError for the failed keys, or nil if there are none
+func batchError(errs map[string]error) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	return &BatchError{Errors: errs}
+}
+
+// Call fn with each of 0 to n-1, running at most BATCHCONCURRENCY calls at once
+func forEach(n int, fn func(i int)) {
+	var wg sync.WaitGroup
+	slots := make(chan bool, BATCHCONCURRENCY)
+	for i := 0; i < n; i++ {
+		slots <- true
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			fn(i)
+			<-slots
+		}(i)
+	}
+	wg.Wait()
+}
+
+// Split keys into chunks of at most BATCHSIZE keys
+func chunks(keys []string) (batches [][]string) {
+	for len(keys) > BATCHSIZE {
+		batches = append(batches, keys[:BATCHSIZE])
+		keys = keys[BATCHSIZE:]
+	}
+	if len(keys) > 0 {
+		batches = append(batches, keys)
+	}
+	return batches
+}
+
+// Split values into chunks of at most BATCHSIZE keys and about BATCHBYTES bytes
+func valueChunks(values map[string][]byte) (batches []map[string][]byte) {
+	batch, size := make(map[string][]byte), 0
+	for _, key := range sortedKeys(values) {
+		if len(batch) > 0 && (len(batch) == BATCHSIZE || size+len(values[key]) > BATCHBYTES) {
+			batches = append(batches, batch)
+			batch, size = make(map[string][]byte), 0
+		}
+		batch[key] = values[key]
+		size += len(values[key])
+	}
+	if len(batch) > 0 {
+		batches = append(batches, batch)
+	}
+	return batches
+}
+
+func sortedKeys(values map[string][]byte) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// The results of a batch operation, safe to record from several goroutines
+type batchResults struct {
+	errs  map[string]error
+	mutex sync.Mutex
+}
+
+func newBatchResults() *batchResults {
+	return &batchResults{errs: make(map[string]error)}
+}
+
+func (r *batchResults) fail(key string, err error) {
+	r.mutex.Lock()
+	r.errs[key] = err
+	r.mutex.Unlock()
+}
+
+// A batch of keys that share a root
+type rootBatch struct {
+	root RemoteNode
+	keys []string
+}
+
+// Find the root of each key, and group the keys by root into batches of at most BATCHSIZE keys.
+// Returns the keys whose root could not be found separately.
+func (local *Node) groupByRoot(keys []string) (batches []rootBatch, unrouted []string) {
+	roots := make([]RemoteNode, len(keys))
+	found := make([]bool, len(keys))
+	forEach(len(keys), func(i int) {
+		root, err := local.FindRootOnRemoteNode(local.Node, Hash(keys[i]))
+		roots[i], found[i] = root, err == nil
+	})
+
+	byRoot := make(map[RemoteNode][]string)
+	var order []RemoteNode
+	for i, key := range keys {
+		if !found[i] {
+			unrouted = append(unrouted, key)
+			continue
+		}
+		if _, exists := byRoot[roots[i]]; !exists {
+			order = append(order, roots[i])
+		}
+		byRoot[roots[i]] = append(byRoot[roots[i]], key)
+	}
+	for _, root := range order {
+		for _, chunk := range chunks(byRoot[root]) {
+			batches = append(batches, rootBatch{root, chunk})
+		}
+	}
+	return batches, unrouted
+}
+
+// StoreMany stores each value under its key, applying the options to every one of them.
+// Keys are registered with their roots in batches, and any key that cannot be registered that way,
+// for example because another node stores a newer version, is stored on its own.
+// Returns a BatchError listing the keys that could not be stored.
+func (local *Node) StoreMany(values map[string][]byte, opts ...StoreOption) error {
+	config := newStoreConfig(opts)
+	metadata := make(map[string]Metadata, len(values))
+	for key := range values {
+		metadata[key] = config.metadata
+	}
+	_, errs := local.storeMany(values, metadata)
+	return batchError(errs)
+}
+
+// Store many values, each with its own metadata, returning the version of each stored value and the
+// error of each that failed
+func (local *Node) storeMany(values map[string][]byte, metadata map[string]Metadata) (map[string]Version, map[string]error) {
+	results := newBatchResults()
+	versions := make(map[string]Version, len(values))
+	stored := func(key string, version Version) {
+		results.mutex.Lock()
+		versions[key] = version
+		results.mutex.Unlock()
+	}
+
+	// Reject blobs that cannot fit before advertising them
+	var admitted []string
+	for _, key := range sortedKeys(values) {
+		if local.Draining() {
+			results.fail(key, &DrainingError{Key: key})
+		} else if err := local.blobstore.Admits(key, len(values[key])); err != nil {
+			results.fail(key, err)
+		} else {
+			admitted = append(admitted, key)
+		}
+	}
+
+	batches, unrouted := local.groupByRoot(admitted)
+	retry := make([][]string, len(batches))
+	forEach(len(batches), func(i int) {
+		batch := batches[i]
+		replicas := make([]Replica, len(batch.keys))
+		for j, key := range batch.keys {
+			replicas[j] = Replica{Node: local.Node, Version: local.clock.Now(), Expires: metadata[key].Expires}
+		}
+		isRoot, newest, err := batch.root.RegisterManyRPC(batch.keys, replicas)
+		if err != nil {
+			Debug.Printf("Unable to register %v keys with %v: %v\n", len(batch.keys), batch.root, err)
+			local.RemoveBadNodes([]RemoteNode{batch.root})
+			retry[i] = batch.keys
+			return
+		}
+		for j, key := range batch.keys {
+			if !isRoot[j] || newest[j] >= replicas[j].Version {
+				retry[i] = append(retry[i], key)
+				continue
+			}
+			kept := metadata[key]
+			kept.Version = replicas[j].Version
+			if err := local.keep(key, values[key], kept, replicas[j], batch.root); err != nil {
+				results.fail(key, err)
+			} else {
+				stored(key, replicas[j].Version)
+			}
+		}
+	})
+
+	// Store the rest one at a time, which finds their roots again and retries newer versions
+	for _, keys := range retry {
+		unrouted = append(unrouted, keys...)
+	}
+	forEach(len(unrouted), func(i int) {
+		key := unrouted[i]
+		version, err := local.store(key, values[key], storeConfig{metadata: metadata[key]})
+		if err != nil {
+			results.fail(key, err)
+		} else {
+			stored(key, version)
+		}
+	})
+	return versions, results.errs
+}
+
+// LookupMany looks up the nodes storing each key, fetching the replicas of keys that share a root
+// in batches. Nodes are ordered as by Lookup. Returns a BatchError listing the keys that could
+// not be looked up; keys that are not stored anywhere have no nodes, but are not errors.
+func (local *Node) LookupMany(keys []string) (map[string][]RemoteNode, error) {
+	replicas, errs := local.lookupMany(keys)
+	nodes := make(map[string][]RemoteNode, len(replicas))
+	for key, keyReplicas := range replicas {
+		nodes[key] = make([]RemoteNode, len(keyReplicas))
+		for i, replica := range keyReplicas {
+			nodes[key][i] = replica.Node
+		}
+	}
+	return nodes, batchError(errs)
+}
+
+// Look up many keys, returning the replicas of each key that was looked up, ranked as by
+// LookupVersions, and the error of each that could not be
+func (local *Node) lookupMany(keys []string) (map[string][]Replica, map[string]error) {
+	results := newBatchResults()
+	replicas := make(map[string][]Replica, len(keys))
+	found := func(key string, keyReplicas []Replica) {
+		if len(keyReplicas) > 0 {
+			local.clock.Observe(keyReplicas[0].Version)
+		}
+		local.latencies.Rank(local.Node, keyReplicas)
+		local.probe(keyReplicas)
+		results.mutex.Lock()
+		replicas[key] = keyReplicas
+		results.mutex.Unlock()
+	}
+
+	batches, unrouted := local.groupByRoot(keys)
+	retry := make([][]string, len(batches))
+	forEach(len(batches), func(i int) {
+		batch := batches[i]
+		isRoot, batchReplicas, err := batch.root.FetchManyRPC(batch.keys)
+		if err != nil {
+			Debug.Printf("Unable to fetch %v keys from %v: %v\n", len(batch.keys), batch.root, err)
+			retry[i] = batch.keys
+			return
+		}
+		for j, key := range batch.keys {
+			if isRoot[j] {
+				found(key, batchReplicas[j])
+			} else {
+				retry[i] = append(retry[i], key)
+			}
+		}
+	})
+
+	for _, keys := range retry {
+		unrouted = append(unrouted, keys...)
+	}
+	forEach(len(unrouted), func(i int) {
+		key := unrouted[i]
+		keyReplicas, err := local.LookupVersions(key)
+		if err != nil {
+			results.fail(key, err)
+			return
+		}
+		results.mutex.Lock()
+		replicas[key] = keyReplicas
+		results.mutex.Unlock()
+	})
+	return replicas, results.errs
+}
+
+// GetMany gets the blob stored under each key, looking the keys up with LookupMany and then
+// fetching each blob as Get does. Returns a BatchError listing the keys that could not be got,
+// including a NotFoundError for each key that is not stored anywhere.
+func (local *Node) GetMany(keys []string) (map[string][]byte, error) {
+	replicas, errs := local.lookupMany(keys)
+	results := &batchResults{errs: errs}
+	blobs := make(map[string][]byte, len(replicas))
+
+	var found []string
+	for _, key := range keys {
+		if _, exists := replicas[key]; exists {
+			found = append(found, key)
+		}
+	}
+	forEach(len(found), func(i int) {
+		key := found[i]
+		blob, _, err := local.getFromReplicas(key, replicas[key])
+		if err != nil {
+			results.fail(key, err)
+			return
+		}
+		results.mutex.Lock()
+		blobs[key] = blob
+		results.mutex.Unlock()
+	})
+	return blobs, batchError(results.errs)
+}
+
+// StoreMany invokes tapestry.StoreMany on the remote Tapestry node, sending the values in
+// batches of at most BATCHSIZE keys and about BATCHBYTES bytes
+func (client *Client) StoreMany(values map[string][]byte, opts ...StoreOption) error {
+	Debug.Printf("Making remote TapestryStoreMany calls\n")
+	batches := valueChunks(values)
+	results := newBatchResults()
+	forEach(len(batches), func(i int) {
+		_, errs, err := client.node.TapestryStoreManyRPC(batches[i], opts...)
+		for key := range batches[i] {
+			if err != nil {
+				results.fail(key, err)
+			} else if errs[key] != nil {
+				results.fail(key, errs[key])
+			}
+		}
+	})
+	return batchError(results.errs)
+}
+
+// LookupMany invokes tapestry.LookupMany on the remote Tapestry node, in batches of at most BATCHSIZE keys
+func (client *Client) LookupMany(keys []string) (map[string][]*Client, error) {
+	replicas, errs := client.lookupMany(keys)
+	clients := make(map[string][]*Client, len(replicas))
+	for key, keyReplicas := range replicas {
+		clients[key] = make([]*Client, len(keyReplicas))
+		for i, replica := range keyReplicas {
+			node := replica.Node
+			clients[key][i] = &Client{node.ID.String(), &node, new(crawlCache)}
+		}
+	}
+	return clients, batchError(errs)
+}
+
+// Look up many keys on the remote node, returning the replicas of each key that was looked up
+// and the error of each that could not be
+func (client *Client) lookupMany(keys []string) (map[string][]Replica, map[string]error) {
+	Debug.Printf("Making remote TapestryLookupMany calls\n")
+	batches := chunks(keys)
+	results := newBatchResults()
+	replicas := make(map[string][]Replica, len(keys))
+	forEach(len(batches), func(i int) {
+		batchReplicas, errs, err := client.node.TapestryLookupManyRPC(batches[i])
+		results.mutex.Lock()
+		defer results.mutex.Unlock()
+		for _, key := range batches[i] {
+			if err != nil {
+				results.errs[key] = err
+			} else if errs[key] != nil {
+				results.errs[key] = errs[key]
+			} else {
+				replicas[key] = batchReplicas[key]
+			}
+		}
+	})
+	return replicas, results.errs
+}
+
+// GetMany looks up the keys with LookupMany, then fetches each blob directly from its replicas
+func (client *Client) GetMany(keys []string) (map[string][]byte, error) {
+	replicas, errs := client.lookupMany(keys)
+	results := &batchResults{errs: errs}
+	blobs := make(map[string][]byte, len(replicas))
+
+	var found []string
+	for _, key := range keys {
+		if _, exists := replicas[key]; exists {
+			found = append(found, key)
+		}
+	}
+	forEach(len(found), func(i int) {
+		key := found[i]
+		nodes := make([]RemoteNode, len(replicas[key]))
+		for j, replica := range replicas[key] {
+			nodes[j] = replica.Node
+		}
+		var blob []byte
+		var err error
+		if len(nodes) == 0 {
+			err = &NotFoundError{Key: key}
+		} else {
+			blob, _, err = fetchFromReplicas(key, nodes)
+		}
+		if err != nil {
+			results.fail(key, err)
+			return
+		}
+		results.mutex.Lock()
+		blobs[key] = blob
+		results.mutex.Unlock()
+	})
+	return blobs, batchError(results.errs)
+}
@@ -0,0 +1,358 @@
+/*
+ *  Brown University, CS138, Spring 2022
+ *
+ *  Purpose: Drives a configurable workload of stores, gets and lookups
+ *  against a tapestry, through local nodes or clients, and reports the
+ *  throughput and latency of each operation, how many hops keys took to
+ *  route to their roots, and which errors occurred.
+ */
+
+package pkg
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Operations of a benchmark
+const (
+	BenchStore  = "store"
+	BenchGet    = "get"
+	BenchLookup = "lookup"
+)
+
+// BenchPercentiles are the latency percentiles reported for each operation
+var BenchPercentiles = []float64{0.5, 0.9, 0.99, 0.999}
+
+// BenchTarget is what a benchmark issues operations through: a local *Node or a *Client. Hop counts
+// are only measured from a *Node.
+type BenchTarget interface {
+	Store(key string, value []byte, opts ...StoreOption) error
+	Get(key string) ([]byte, error)
+}
+
+// BenchConfig describes the workload of a benchmark
+type BenchConfig struct {
+	Keys        int           // How many distinct keys are used
+	Zipf        float64       // The exponent of the Zipf distribution keys are drawn from, over 1, or zero for uniform
+	ValueSize   int           // The size in bytes of stored values
+	ReadRatio   float64       // The fraction of operations that read a key rather than store it
+	LookupRatio float64       // The fraction of reads that are lookups rather than gets
+	Concurrency int           // How many operations are in flight at once
+	Duration    time.Duration // How long operations are issued for
+	Hops        bool          // Once operations are over, route each key from a target to measure hop counts
+	Seed        int64         // Seeds the choice of operations and keys
+}
+
+// BenchReport is the outcome of a benchmark. It encodes to JSON as is.
+type BenchReport struct {
+	Duration   time.Duration           `json:"duration"`
+	Operations map[string]BenchOpStats `json:"operations"`
+	Hops       map[int]int             `json:"hops,omitempty"` // How many keys took each number of hops to route
+	Errors     map[string]int          `json:"errors,omitempty"`
+}
+
+// BenchOpStats is the throughput and latency of one operation. Latencies are of successful operations.
+type BenchOpStats struct {
+	Count       int                      `json:"count"`
+	Errors      int                      `json:"errors"`
+	Throughput  float64                  `json:"throughput"` // Successful operations per second
+	Mean        time.Duration            `json:"mean"`
+	Max         time.Duration            `json:"max"`
+	Percentiles map[string]time.Duration `json:"percentiles"` // Keyed by percentile, such as "p99"
+}
+
+// The key with the given rank in the workload
+func benchKey(i int) string {
+	return fmt.Sprintf("bench-%06d", i)
+}
+
+// What one worker measured
+type benchSample struct {
+	latencies map[string][]time.Duration // Of successful operations, by operation
+	failures  map[string]int             // Failed operations, by operation
+	errors    map[string]int             // Failed operations, by operation and kind of error
+}
+
+// Bench stores every key of the workload once, then issues operations through the targets for the
+// configured duration, spreading the workers over the targets. Keys are drawn uniformly, or from a
+// Zipf distribution in which lower ranked keys are more popular. If hop counts are measured, each
+// key is then routed once more, from the target it was stored through, so that routing does not
+// add to the latencies of the operations.
+func Bench(targets []BenchTarget, config BenchConfig) (report BenchReport, err error) {
+	if len(targets) == 0 {
+		return report, fmt.Errorf("no targets to benchmark")
+	}
+	if config.Keys < 1 || config.Concurrency < 1 || config.ValueSize < 0 {
+		return report, fmt.Errorf("a benchmark needs at least one key and one worker, and a value size of at least 0")
+	}
+	if config.Zipf != 0 && config.Zipf <= 1 {
+		return report, fmt.Errorf("the Zipf exponent must be over 1, got %v", config.Zipf)
+	}
+	if config.ReadRatio < 0 || config.ReadRatio > 1 || config.LookupRatio < 0 || config.LookupRatio > 1 {
+		return report, fmt.Errorf("the read and lookup ratios must be between 0 and 1")
+	}
+	value := bytes.Repeat([]byte("v"), config.ValueSize)
+
+	// Store every key first, so that reads find them
+	var prefillErr error
+	var prefillMutex sync.Mutex
+	forEach(config.Keys, func(i int) {
+		if err := targets[i%len(targets)].Store(benchKey(i), value); err != nil {
+			prefillMutex.Lock()
+			prefillErr = fmt.Errorf("store %v before the benchmark: %v", benchKey(i), err)
+			prefillMutex.Unlock()
+		}
+	})
+	if prefillErr != nil {
+		return report, prefillErr
+	}
+
+	samples := make([]benchSample, config.Concurrency)
+	deadline := time.Now().Add(config.Duration)
+	start := time.Now()
+	var wg sync.WaitGroup
+	for w := 0; w < config.Concurrency; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			samples[w] = benchWorker(targets[w%len(targets)], config, value, config.Seed+int64(w), deadline)
+		}(w)
+	}
+	wg.Wait()
+	report.Duration = time.Since(start)
+
+	report.Operations = make(map[string]BenchOpStats)
+	report.Hops = make(map[int]int)
+	if config.Hops {
+		report.Hops = benchHops(targets, config.Keys)
+	}
+	report.Errors = make(map[string]int)
+	latencies := make(map[string][]time.Duration)
+	failures := make(map[string]int)
+	for _, sample := range samples {
+		for op, durations := range sample.latencies {
+			latencies[op] = append(latencies[op], durations...)
+		}
+		for op, count := range sample.failures {
+			failures[op] += count
+		}
+		for kind, count := range sample.errors {
+			report.Errors[kind] += count
+		}
+	}
+	for _, op := range []string{BenchStore, BenchGet, BenchLookup} {
+		if len(latencies[op]) > 0 || failures[op] > 0 {
+			report.Operations[op] = benchStats(latencies[op], failures[op], report.Duration)
+		}
+	}
+	return report, nil
+}
+
+// Issue operations until the deadline
+func benchWorker(target BenchTarget, config BenchConfig, value []byte, seed int64, deadline time.Time) benchSample {
+	sample := benchSample{
+		latencies: make(map[string][]time.Duration),
+		failures:  make(map[string]int),
+		errors:    make(map[string]int),
+	}
+	random := rand.New(rand.NewSource(seed))
+	var zipf *rand.Zipf
+	if config.Zipf > 1 && config.Keys > 1 {
+		zipf = rand.NewZipf(random, config.Zipf, 1, uint64(config.Keys-1))
+	}
+	for time.Now().Before(deadline) {
+		var key string
+		if zipf != nil {
+			key = benchKey(int(zipf.Uint64()))
+		} else {
+			key = benchKey(random.Intn(config.Keys))
+		}
+
+		op := BenchStore
+		if random.Float64() < config.ReadRatio {
+			op = BenchGet
+			if random.Float64() < config.LookupRatio {
+				op = BenchLookup
+			}
+		}
+
+		start := time.Now()
+		var err error
+		switch op {
+		case BenchStore:
+			err = target.Store(key, value)
+		case BenchGet:
+			_, err = target.Get(key)
+		case BenchLookup:
+			err = benchLookup(target, key)
+		}
+		latency := time.Since(start)
+		if err != nil {
+			sample.failures[op]++
+			sample.errors[op+": "+benchErrorKind(err)]++
+		} else {
+			sample.latencies[op] = append(sample.latencies[op], latency)
+		}
+	}
+	return sample
+}
+
+// Route each key from the target it was stored through, counting how many keys took each number of
+// hops to reach their root. Keys stored through a *Client are skipped, as clients cannot route.
+func benchHops(targets []BenchTarget, keys int) map[int]int {
+	hops := make(map[int]int)
+	var mutex sync.Mutex
+	forEach(keys, func(i int) {
+		node, ok := targets[i%len(targets)].(*Node)
+		if !ok {
+			return
+		}
+		if _, n, err := node.Route(benchKey(i)); err == nil {
+			mutex.Lock()
+			hops[n]++
+			mutex.Unlock()
+		}
+	})
+	return hops
+}
+
+// Look up the replicas of key through the target
+func benchLookup(target BenchTarget, key string) (err error) {
+	switch target := target.(type) {
+	case *Node:
+		_, err = target.Lookup(key)
+	case *Client:
+		_, err = target.Lookup(key)
+	default:
+		err = fmt.Errorf("lookups are not supported through %T", target)
+	}
+	return err
+}
+
+// Classifies an error for the error breakdown of a report
+func benchErrorKind(err error) string {
+	var notFound *NotFoundError
+	var full *StoreFullError
+	var conflict *VersionConflictError
+	var draining *DrainingError
+	switch {
+	case errors.As(err, &notFound):
+		return "not found"
+	case errors.As(err, &full):
+		return "store full"
+	case errors.As(err, &conflict):
+		return "version conflict"
+	case errors.As(err, &draining):
+		return "draining"
+	}
+	switch status.Code(err) {
+	case codes.DeadlineExceeded:
+		return "timeout"
+	case codes.Unavailable:
+		return "unavailable"
+	}
+	return "other"
+}
+
+// Summarises the latencies of one operation
+func benchStats(latencies []time.Duration, errs int, elapsed time.Duration) BenchOpStats {
+	stats := BenchOpStats{
+		Count:       len(latencies) + errs,
+		Errors:      errs,
+		Percentiles: make(map[string]time.Duration),
+	}
+	if len(latencies) == 0 {
+		return stats
+	}
+	sort.Slice(latencies, func(i, j int) bool {
+		return latencies[i] < latencies[j]
+	})
+	var total time.Duration
+	for _, latency := range latencies {
+		total += latency
+	}
+	stats.Mean = total / time.Duration(len(latencies))
+	stats.Max = latencies[len(latencies)-1]
+	stats.Throughput = float64(len(latencies)) / elapsed.Seconds()
+	for _, p := range BenchPercentiles {
+		// The nearest rank
+		rank := int(math.Ceil(p*float64(len(latencies)))) - 1
+		if rank < 0 {
+			rank = 0
+		}
+		stats.Percentiles[benchPercentileName(p)] = latencies[rank]
+	}
+	return stats
+}
+
+// The name of a percentile, such as p99 or p99.9
+func benchPercentileName(p float64) string {
+	return "p" + fmt.Sprint(math.Round(p*1000)/10)
+}
+
+// String formats the report as tables of latencies, hop counts and errors
+func (report BenchReport) String() string {
+	var buffer bytes.Buffer
+	fmt.Fprintf(&buffer, "Ran for %v\n\n", report.Duration.Round(time.Millisecond))
+	fmt.Fprintf(&buffer, "%-8v %8v %7v %10v %10v", "op", "count", "errors", "ops/s", "mean")
+	for _, p := range BenchPercentiles {
+		fmt.Fprintf(&buffer, " %10v", benchPercentileName(p))
+	}
+	fmt.Fprintf(&buffer, " %10v\n", "max")
+	for _, op := range []string{BenchStore, BenchGet, BenchLookup} {
+		stats, ok := report.Operations[op]
+		if !ok {
+			continue
+		}
+		fmt.Fprintf(&buffer, "%-8v %8v %7v %10.1f %10v", op, stats.Count, stats.Errors, stats.Throughput, benchDuration(stats.Mean))
+		for _, p := range BenchPercentiles {
+			fmt.Fprintf(&buffer, " %10v", benchDuration(stats.Percentiles[benchPercentileName(p)]))
+		}
+		fmt.Fprintf(&buffer, " %10v\n", benchDuration(stats.Max))
+	}
+
+	if len(report.Hops) > 0 {
+		hops := make([]int, 0, len(report.Hops))
+		total := 0
+		for h, count := range report.Hops {
+			hops = append(hops, h)
+			total += count
+		}
+		sort.Ints(hops)
+		fmt.Fprintf(&buffer, "\n%-8v %8v %7v\n", "hops", "keys", "share")
+		for _, h := range hops {
+			fmt.Fprintf(&buffer, "%-8v %8v %6.1f%%\n", h, report.Hops[h], 100*float64(report.Hops[h])/float64(total))
+		}
+	}
+
+	if len(report.Errors) > 0 {
+		kinds := make([]string, 0, len(report.Errors))
+		for kind := range report.Errors {
+			kinds = append(kinds, kind)
+		}
+		sort.Strings(kinds)
+		fmt.Fprintf(&buffer, "\n%-24v %8v\n", "error", "count")
+		for _, kind := range kinds {
+			fmt.Fprintf(&buffer, "%-24v %8v\n", kind, report.Errors[kind])
+		}
+	}
+	return buffer.String()
+}
+
+// Rounds a latency for display
+func benchDuration(d time.Duration) time.Duration {
+	if d > time.Millisecond {
+		return d.Round(10 * time.Microsecond)
+	}
+	return d.Round(time.Microsecond)
+}
@@ -8,51 +8,252 @@
 package pkg
 
 import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
 	"sync"
+	"time"
 )
 
 // BlobStore is a utility class tacked on to the tapestry DOLR.  You should not need
 // to use this directly.
+//
+// A BlobStore may have a quota on the total bytes and number of blobs it holds. Once full, its
+// eviction policy picks blobs to drop to make room for new ones, or rejects the new blob.
 type BlobStore struct {
-	blobs map[string]Blob
+	blobs      map[string]Blob
+	bytes      int64          // Total size of the blobs
+	maxBytes   int64          // Quota on bytes, or zero for no quota
+	maxObjects int            // Quota on blobs, or zero for no quota
+	policy     EvictionPolicy // Picks the blobs to evict when full
 	sync.RWMutex
 }
 
-// Blob is an arbitrary collection of bytes
+// StoreFullError is returned when a blob does not fit in a full BlobStore and the eviction policy
+// rejects it, or when the blob is larger than the whole byte quota.
+type StoreFullError struct {
+	Key  string
+	Size int
+}
+
+func (e *StoreFullError) Error() string {
+	return fmt.Sprintf("blob store is full, cannot store %v (%v bytes)", e.Key, e.Size)
+}
+
+// Blob is an arbitrary collection of bytes, along with a description of them
 type Blob struct {
-	bytes []byte
-	done  chan bool
+	bytes    []byte
+	metadata Metadata
+	done     chan bool
+	expiry   *time.Timer // Deletes the blob when it expires, if it has an expiry time
+}
+
+// Metadata describes a blob. It is stored with the blob and can be fetched without the blob's bytes.
+type Metadata struct {
+	Size        int               `json:"size"`
+	ContentType string            `json:"contentType,omitempty"`
+	Created     time.Time         `json:"created"`
+	Modified    time.Time         `json:"modified"`
+	Tags        map[string]string `json:"tags,omitempty"`
+	Version     Version           `json:"version"`
+	Expires     time.Time         `json:"expires,omitempty"` // The zero time if the blob does not expire
+	Cached      bool              `json:"cached,omitempty"`  // The blob is a cached copy of a blob stored elsewhere
+	Lease       time.Time         `json:"lease,omitempty"`   // When a cached copy is dropped, the zero time for other blobs
+}
+
+// Expired returns true if the blob has an expiry time, or a cached copy a lease, that has passed
+func (m Metadata) Expired() bool {
+	end := m.end()
+	return !end.IsZero() && !time.Now().Before(end)
+}
+
+// When the local copy of the blob is dropped: when it expires, or its lease ends if that is sooner.
+// The zero time if neither is set.
+func (m Metadata) end() time.Time {
+	if !m.Lease.IsZero() && (m.Expires.IsZero() || m.Lease.Before(m.Expires)) {
+		return m.Lease
+	}
+	return m.Expires
+}
+
+func (m Metadata) String() string {
+	var buffer bytes.Buffer
+	fmt.Fprintf(&buffer, "version %v  %v bytes", m.Version, m.Size)
+	if m.ContentType != "" {
+		fmt.Fprintf(&buffer, "  %v", m.ContentType)
+	}
+	fmt.Fprintf(&buffer, "  created %v  modified %v", m.Created.Format(time.RFC3339), m.Modified.Format(time.RFC3339))
+	if !m.Expires.IsZero() {
+		fmt.Fprintf(&buffer, "  expires %v", m.Expires.Format(time.RFC3339))
+	}
+	if m.Cached {
+		fmt.Fprintf(&buffer, "  cached")
+		if !m.Lease.IsZero() {
+			fmt.Fprintf(&buffer, " until %v", m.Lease.Format(time.RFC3339))
+		}
+	}
+	tags := make([]string, 0, len(m.Tags))
+	for tag, value := range m.Tags {
+		tags = append(tags, tag+"="+value)
+	}
+	sort.Strings(tags)
+	if len(tags) > 0 {
+		fmt.Fprintf(&buffer, "  %v", strings.Join(tags, ","))
+	}
+	return buffer.String()
 }
 
-// NewBlobStore creates a new blobstore
+// NewBlobStore creates a new blobstore without quotas
 func NewBlobStore() *BlobStore {
+	return NewBlobStoreWithQuota(0, 0, nil)
+}
+
+// NewBlobStoreWithQuota creates a new blobstore holding at most maxBytes bytes in at most maxObjects
+// blobs; zero means no quota. When full, policy picks the blobs to evict; nil means LRU.
+func NewBlobStoreWithQuota(maxBytes int64, maxObjects int, policy EvictionPolicy) *BlobStore {
+	if policy == nil {
+		policy = NewLRUPolicy()
+	}
 	bs := new(BlobStore)
 	bs.blobs = make(map[string]Blob)
+	bs.maxBytes = maxBytes
+	bs.maxObjects = maxObjects
+	bs.policy = policy
 	return bs
 }
 
 // Get bytes from the blobstore
 func (bs *BlobStore) Get(key string) ([]byte, bool) {
+	bs.Lock()
+	defer bs.Unlock()
+
 	blob, exists := bs.blobs[key]
-	if exists {
+	if exists && !blob.metadata.Expired() {
+		bs.policy.Touch(key)
 		return blob.bytes, true
 	}
 	return nil, false
 }
 
-// Put bytes in the blobstore
-func (bs *BlobStore) Put(key string, blob []byte, unregister chan bool) {
+// Stat gets the metadata of a blob from the blobstore
+func (bs *BlobStore) Stat(key string) (Metadata, bool) {
+	bs.RLock()
+	defer bs.RUnlock()
+
+	blob, exists := bs.blobs[key]
+	if exists && !blob.metadata.Expired() {
+		return blob.metadata, true
+	}
+	return Metadata{}, false
+}
+
+// Admits returns a StoreFullError if a blob of the given size could not be stored under key,
+// without changing the store. Put may still fail if other blobs are stored in the meantime.
+func (bs *BlobStore) Admits(key string, size int) error {
+	bs.RLock()
+	defer bs.RUnlock()
+
+	if bs.fits(key, size) {
+		return nil
+	}
+	if _, ok := bs.policy.Victim(key); !ok || (bs.maxBytes > 0 && int64(size) > bs.maxBytes) {
+		return &StoreFullError{Key: key, Size: size}
+	}
+	return nil
+}
+
+// Put bytes in the blobstore. The size and timestamps of the metadata are filled in, keeping the
+// creation time of any blob being replaced. If the metadata has an expiry time, the blob is deleted
+// and unregistered once it passes.
+//
+// If the store is full, blobs are evicted to make room and their keys returned; evicted blobs are
+// unregistered through their done channels. If there is no room, returns a StoreFullError, along
+// with any keys evicted before the policy gave up.
+func (bs *BlobStore) Put(key string, blob []byte, metadata Metadata, unregister chan bool) (evicted []string, err error) {
 	bs.Lock()
 	defer bs.Unlock()
 
+	evicted, err = bs.makeRoom(key, len(blob))
+	if err != nil {
+		return evicted, err
+	}
+
+	now := time.Now()
+	metadata.Size = len(blob)
+	metadata.Created = now
+	metadata.Modified = now
+
 	// If a previous blob exists, delete it
 	previous, exists := bs.blobs[key]
 	if exists {
-		previous.done <- true
+		previous.remove()
+		metadata.Created = previous.metadata.Created
+		bs.bytes -= int64(len(previous.bytes))
 	}
 
 	// Register the new one
-	bs.blobs[key] = Blob{blob, unregister}
+	var expiry *time.Timer
+	if end := metadata.end(); !end.IsZero() {
+		expiry = time.AfterFunc(time.Until(end), func() {
+			bs.expire(key)
+		})
+	}
+	bs.blobs[key] = Blob{blob, metadata, unregister, expiry}
+	bs.bytes += int64(len(blob))
+	bs.policy.Touch(key)
+	return evicted, nil
+}
+
+// Returns true if a blob of the given size would fit under key, replacing any blob already there.
+// The lock must be held.
+func (bs *BlobStore) fits(key string, size int) bool {
+	bytes, objects := bs.bytes+int64(size), len(bs.blobs)+1
+	if previous, exists := bs.blobs[key]; exists {
+		bytes -= int64(len(previous.bytes))
+		objects--
+	}
+	return (bs.maxBytes <= 0 || bytes <= bs.maxBytes) && (bs.maxObjects <= 0 || objects <= bs.maxObjects)
+}
+
+// Evict blobs other than key until a blob of the given size fits. The lock must be held.
+func (bs *BlobStore) makeRoom(key string, size int) (evicted []string, err error) {
+	if bs.maxBytes > 0 && int64(size) > bs.maxBytes {
+		return nil, &StoreFullError{Key: key, Size: size}
+	}
+	for !bs.fits(key, size) {
+		victim, ok := bs.policy.Victim(key)
+		if !ok {
+			return evicted, &StoreFullError{Key: key, Size: size}
+		}
+		Debug.Printf("Evicting blob %v to make room for %v\n", victim, key)
+		bs.delete(victim)
+		evicted = append(evicted, victim)
+	}
+	return evicted, nil
+}
+
+// Unregister and remove a blob. The lock must be held.
+func (bs *BlobStore) delete(key string) {
+	blob, exists := bs.blobs[key]
+	if exists {
+		blob.remove()
+		bs.bytes -= int64(len(blob.bytes))
+		delete(bs.blobs, key)
+	}
+	bs.policy.Remove(key)
+}
+
+// Delete the blob if it has expired, rather than been replaced since the expiry timer fired
+func (bs *BlobStore) expire(key string) {
+	bs.Lock()
+	defer bs.Unlock()
+
+	blob, exists := bs.blobs[key]
+	if exists && blob.metadata.Expired() {
+		Debug.Printf("Expiring blob %v\n", key)
+		bs.delete(key)
+	}
 }
 
 // Delete the blob and unregister it
@@ -62,11 +263,8 @@ func (bs *BlobStore) Delete(key string) bool {
 
 	// If a previous blob exists, unregister it
 	previous, exists := bs.blobs[key]
-	if exists {
-		previous.done <- true
-	}
-	delete(bs.blobs, key)
-	return exists
+	bs.delete(key)
+	return exists && !previous.metadata.Expired()
 }
 
 // DeleteAll removes all blobs from the BlobStore
@@ -74,8 +272,65 @@ func (bs *BlobStore) DeleteAll() {
 	bs.Lock()
 	defer bs.Unlock()
 
-	for key, value := range bs.blobs {
-		value.done <- true
-		delete(bs.blobs, key)
+	for key := range bs.blobs {
+		bs.delete(key)
+	}
+}
+
+// Usage returns the total bytes and number of blobs held
+func (bs *BlobStore) Usage() (bytes int64, objects int) {
+	bs.RLock()
+	defer bs.RUnlock()
+
+	return bs.bytes, len(bs.blobs)
+}
+
+// Stop republishing and expiring a blob that is being removed from the BlobStore
+func (blob Blob) remove() {
+	// Never block while the BlobStore is locked, whoever holds the other end of done
+	select {
+	case blob.done <- true:
+	default:
+	}
+	if blob.expiry != nil {
+		blob.expiry.Stop()
+	}
+}
+
+// BlobInfo describes a blob held in a BlobStore
+type BlobInfo struct {
+	Key      string   `json:"key"`
+	Metadata Metadata `json:"metadata"`
+}
+
+// List returns the key and metadata of every blob in the BlobStore, sorted by key
+func (bs *BlobStore) List() []BlobInfo {
+	bs.RLock()
+	defer bs.RUnlock()
+
+	blobs := make([]BlobInfo, 0, len(bs.blobs))
+	for key, blob := range bs.blobs {
+		if !blob.metadata.Expired() {
+			blobs = append(blobs, BlobInfo{key, blob.metadata})
+		}
+	}
+	sort.Slice(blobs, func(i, j int) bool {
+		return blobs[i].Key < blobs[j].Key
+	})
+	return blobs
+}
+
+// Keys returns the keys of all blobs in the BlobStore, in sorted order
+func (bs *BlobStore) Keys() []string {
+	bs.RLock()
+	defer bs.RUnlock()
+
+	keys := make([]string, 0, len(bs.blobs))
+	for key, blob := range bs.blobs {
+		if !blob.metadata.Expired() {
+			keys = append(keys, key)
+		}
 	}
+	sort.Strings(keys)
+	return keys
 }
@@ -7,12 +7,11 @@
 
 package pkg
 
-import "fmt"
-
 // Client connects to a tapestry node
 type Client struct {
-	ID   string
-	node *RemoteNode
+	ID     string
+	node   *RemoteNode
+	crawls *crawlCache // The nodes found by the last crawl of the mesh, for key listings
 }
 
 // Connect to a Tapestry node
@@ -22,49 +21,106 @@ func Connect(addr string) (*Client, error) {
 		Error.Printf("Failed to make connection to Tapestry node\n")
 		return nil, err
 	}
-	return &Client{node.ID.String(), &node}, nil
+	return &Client{node.ID.String(), &node, new(crawlCache)}, nil
+}
+
+// Address returns the address of the Tapestry node the client is connected to
+func (client *Client) Address() string {
+	return client.node.Address
 }
 
 // Store invokes tapestry.Store on the remote Tapestry node
-func (client *Client) Store(key string, value []byte) error {
+func (client *Client) Store(key string, value []byte, opts ...StoreOption) error {
 	Debug.Printf("Making remote TapestryStore call\n")
-	return client.node.TapestryStoreRPC(key, value)
+	_, err := client.node.TapestryStoreRPC(key, value, opts...)
+	return err
+}
+
+// StoreIf invokes tapestry.StoreIf on the remote Tapestry node
+func (client *Client) StoreIf(key string, value []byte, expected Version, opts ...StoreOption) (Version, error) {
+	Debug.Printf("Making remote conditional TapestryStore call\n")
+	return client.node.TapestryStoreRPC(key, value, append(opts, ifVersion(expected))...)
 }
 
 // Lookup invokes tapestry.Lookup on a remote Tapestry node
 func (client *Client) Lookup(key string) ([]*Client, error) {
 	Debug.Printf("Making remote TapestryLookup call\n")
-	nodes, err := client.node.TapestryLookupRPC(key)
-	clients := make([]*Client, len(nodes))
-	for i, n := range nodes {
-		clients[i] = &Client{n.ID.String(), &n}
+	replicas, err := client.node.TapestryLookupRPC(key)
+	clients := make([]*Client, len(replicas))
+	for i, replica := range replicas {
+		node := replica.Node
+		clients[i] = &Client{node.ID.String(), &node, new(crawlCache)}
 	}
 	return clients, err
 }
 
+// LookupVersions invokes tapestry.LookupVersions on a remote Tapestry node
+func (client *Client) LookupVersions(key string) ([]Replica, error) {
+	Debug.Printf("Making remote TapestryLookup call\n")
+	return client.node.TapestryLookupRPC(key)
+}
+
 // Get data from a Tapestry node. Looks up key then fetches directly.
 func (client *Client) Get(key string) ([]byte, error) {
 	Debug.Printf("Making remote TapestryGet call\n")
 	// Lookup the key
-	replicas, err := client.node.TapestryLookupRPC(key)
+	replicas, err := client.Lookup(key)
 	if err != nil {
 		return nil, err
 	}
 	if len(replicas) == 0 {
-		return nil, fmt.Errorf("No replicas returned for key %v", key)
+		return nil, &NotFoundError{Key: key}
 	}
 
-	// Contact replicas
-	var errs []error
-	for _, replica := range replicas {
-		blob, err := replica.BlobStoreFetchRPC(key)
-		if err != nil {
-			errs = append(errs, err)
-		}
-		if blob != nil {
-			return *blob, nil
-		}
+	// Contact replicas, newest version first and nearest to the node first
+	blob, _, err := fetchFromReplicas(key, clientNodes(replicas))
+	return blob, err
+}
+
+// Stat looks up key then fetches the metadata of the blob directly, without the blob itself.
+func (client *Client) Stat(key string) (Metadata, error) {
+	Debug.Printf("Making remote TapestryStat call\n")
+	replicas, err := client.LookupVersions(key)
+	if err != nil {
+		return Metadata{}, err
 	}
+	if len(replicas) == 0 {
+		return Metadata{}, &NotFoundError{Key: key}
+	}
+	return statFromReplicas(key, primariesFirst(replicas))
+}
+
+// The nodes the clients are connected to
+func clientNodes(clients []*Client) []RemoteNode {
+	nodes := make([]RemoteNode, len(clients))
+	for i, client := range clients {
+		nodes[i] = *client.node
+	}
+	return nodes
+}
+
+// RoutingTable fetches the routing table of the remote Tapestry node
+func (client *Client) RoutingTable() ([]RoutingEntry, error) {
+	Debug.Printf("Making remote GetRoutingTable call\n")
+	return client.node.GetRoutingTableRPC(RemoteNode{})
+}
+
+// State fetches a snapshot of the state of the remote Tapestry node, with its location maps if
+// locations is true
+func (client *Client) State(locations bool) (NodeState, error) {
+	Debug.Printf("Making remote GetState call\n")
+	return client.node.GetStateRPC(locations)
+}
+
+// PublishedKeys returns the status of every key the remote node is publishing
+func (client *Client) PublishedKeys() ([]PublishStatus, error) {
+	Debug.Printf("Making remote PublishedKeys call\n")
+	return client.node.PublishedKeysRPC()
+}
 
-	return nil, fmt.Errorf("Error contacting replicas, %v: %v", replicas, errs)
+// ListKeys lists one page of the keys advertised anywhere in the tapestry that start with prefix,
+// by walking the mesh from the remote node. See Node.ListKeys.
+func (client *Client) ListKeys(prefix string, pageToken string) ([]string, string, error) {
+	Debug.Printf("Listing keys across the tapestry\n")
+	return listKeys(*client.node, client.crawls, prefix, pageToken)
 }
@@ -0,0 +1,105 @@
+/*
+ *  Brown University, CS138, Spring 2022
+ *
+ *  Purpose: Runs a cluster of nodes in the local process, which nodes can be
+ *  added to and crashed from one at a time, for demos and local integration
+ *  testing.
+ */
+
+package pkg
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Cluster is a set of nodes running in the local process, joined into one tapestry
+type Cluster struct {
+	nodes []*Node       // The live nodes, in the order they were started
+	opts  []StartOption // The options every node is started with
+	mutex sync.Mutex
+}
+
+// StartCluster starts n nodes with random IDs, each joining through the first, like MakeTapestries.
+// Every node is started with opts. If a node fails to start, the nodes already started are killed.
+func StartCluster(n int, opts ...StartOption) (*Cluster, error) {
+	ids := make([]string, n)
+	for i := range ids {
+		ids[i] = RandomID().String()
+	}
+	nodes, err := makeTapestries(true, ids, opts...)
+	if err != nil {
+		killQuietly(nodes...)
+		return nil, err
+	}
+	return &Cluster{nodes: nodes, opts: opts}, nil
+}
+
+// Nodes returns the live nodes of the cluster, in the order they were started
+func (cluster *Cluster) Nodes() []*Node {
+	cluster.mutex.Lock()
+	defer cluster.mutex.Unlock()
+	return append([]*Node(nil), cluster.nodes...)
+}
+
+// Add starts another node with a random ID, joining through the oldest live node of the cluster
+func (cluster *Cluster) Add() (*Node, error) {
+	cluster.mutex.Lock()
+	defer cluster.mutex.Unlock()
+
+	connectTo := ""
+	if len(cluster.nodes) > 0 {
+		connectTo = cluster.nodes[0].Node.Address
+	}
+	node, err := Start(RandomID(), 0, connectTo, cluster.opts...)
+	if err != nil {
+		return nil, err
+	}
+	registerCachedTapestry(node)
+	cluster.nodes = append(cluster.nodes, node)
+	return node, nil
+}
+
+// Kill crashes the node whose ID starts with prefix, without notifying the other nodes. The prefix
+// is matched regardless of case, and must match exactly one live node.
+func (cluster *Cluster) Kill(prefix string) (*Node, error) {
+	cluster.mutex.Lock()
+	defer cluster.mutex.Unlock()
+
+	prefix = strings.ToUpper(prefix)
+	match := -1
+	for i, node := range cluster.nodes {
+		if !strings.HasPrefix(node.ID(), prefix) {
+			continue
+		}
+		if match >= 0 {
+			return nil, fmt.Errorf("%v matches more than one node", prefix)
+		}
+		match = i
+	}
+	if match < 0 {
+		return nil, fmt.Errorf("no node matches %v", prefix)
+	}
+	node := cluster.nodes[match]
+	cluster.nodes = append(cluster.nodes[:match], cluster.nodes[match+1:]...)
+	killQuietly(node)
+	return node, nil
+}
+
+// Down kills every node of the cluster
+func (cluster *Cluster) Down() {
+	cluster.mutex.Lock()
+	defer cluster.mutex.Unlock()
+
+	killQuietly(cluster.nodes...)
+	cluster.nodes = nil
+}
+
+// Kill nodes like KillTapestries, without printing
+func killQuietly(nodes ...*Node) {
+	unregisterCachedTapestry(nodes...)
+	for _, node := range nodes {
+		node.Kill()
+	}
+}
@@ -0,0 +1,116 @@
+/*
+ *  Brown University, CS138, Spring 2022
+ *
+ *  Purpose: Defines the eviction policies that choose which blobs a full
+ *  BlobStore drops to make room for new ones.
+ */
+
+package pkg
+
+import (
+	"container/list"
+	"fmt"
+	"strings"
+)
+
+// EvictionPolicy chooses which blob to evict when a BlobStore reaches its quota.
+// The BlobStore calls every method while holding its lock, so policies need no locking of their own.
+type EvictionPolicy interface {
+	Touch(key string)                            // The blob was stored or read
+	Remove(key string)                           // The blob was removed from the store
+	Victim(exclude string) (key string, ok bool) // The next blob to evict, other than exclude; false to reject the new blob instead
+}
+
+// ParseEvictionPolicy returns the policy with the given name: "lru", "lfu" or "reject"
+func ParseEvictionPolicy(name string) (EvictionPolicy, error) {
+	switch strings.ToLower(name) {
+	case "lru":
+		return NewLRUPolicy(), nil
+	case "lfu":
+		return NewLFUPolicy(), nil
+	case "reject":
+		return RejectPolicy{}, nil
+	}
+	return nil, fmt.Errorf("unknown eviction policy %q, expected lru, lfu or reject", name)
+}
+
+// LRUPolicy evicts the least recently stored or read blob
+type LRUPolicy struct {
+	order    *list.List // Front is most recently used
+	elements map[string]*list.Element
+}
+
+// NewLRUPolicy creates an empty LRU policy
+func NewLRUPolicy() *LRUPolicy {
+	return &LRUPolicy{order: list.New(), elements: make(map[string]*list.Element)}
+}
+
+func (p *LRUPolicy) Touch(key string) {
+	if element, exists := p.elements[key]; exists {
+		p.order.MoveToFront(element)
+		return
+	}
+	p.elements[key] = p.order.PushFront(key)
+}
+
+func (p *LRUPolicy) Remove(key string) {
+	if element, exists := p.elements[key]; exists {
+		p.order.Remove(element)
+		delete(p.elements, key)
+	}
+}
+
+func (p *LRUPolicy) Victim(exclude string) (string, bool) {
+	for element := p.order.Back(); element != nil; element = element.Prev() {
+		if key := element.Value.(string); key != exclude {
+			return key, true
+		}
+	}
+	return "", false
+}
+
+// LFUPolicy evicts the least frequently stored or read blob, and of those the least recently used
+type LFUPolicy struct {
+	counts   map[string]int
+	lastUsed map[string]uint64
+	clock    uint64 // Counts uses, to break ties between equally frequent blobs
+}
+
+// NewLFUPolicy creates an empty LFU policy
+func NewLFUPolicy() *LFUPolicy {
+	return &LFUPolicy{counts: make(map[string]int), lastUsed: make(map[string]uint64)}
+}
+
+func (p *LFUPolicy) Touch(key string) {
+	p.clock++
+	p.counts[key]++
+	p.lastUsed[key] = p.clock
+}
+
+func (p *LFUPolicy) Remove(key string) {
+	delete(p.counts, key)
+	delete(p.lastUsed, key)
+}
+
+// Victim scans every blob, which is fine for the number of blobs a single node holds
+func (p *LFUPolicy) Victim(exclude string) (victim string, ok bool) {
+	for key, count := range p.counts {
+		if key == exclude {
+			continue
+		}
+		if !ok || count < p.counts[victim] || (count == p.counts[victim] && p.lastUsed[key] < p.lastUsed[victim]) {
+			victim, ok = key, true
+		}
+	}
+	return victim, ok
+}
+
+// RejectPolicy never evicts, so a full store rejects new blobs
+type RejectPolicy struct{}
+
+func (RejectPolicy) Touch(key string)  {}
+func (RejectPolicy) Remove(key string) {}
+
+func (RejectPolicy) Victim(exclude string) (string, bool) {
+	return "", false
+}
@@ -0,0 +1,239 @@
+/*
+ *  Brown University, CS138, Spring 2022
+ *
+ *  Purpose: Exposes Store, Get, Remove and Lookup on a Tapestry node over
+ *  HTTP/JSON for clients that cannot speak the gRPC protocol.
+ */
+
+package pkg
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MAXBODYSIZE is the largest request body, in bytes, that the gateways accept by default. See WithMaxBodySize.
+const MAXBODYSIZE = 64 << 20
+
+// TTLHEADER is the request header giving the time to live of a stored object, as a duration such as "30m"
+const TTLHEADER = "Tapestry-TTL"
+
+// NewHTTPHandler returns a handler serving the HTTP gateway for the local node:
+//
+// - PUT    /objects/{key}   stores the request body under key, expiring after the TTLHEADER if given
+// - GET    /objects/{key}   fetches the value of key from one of its replicas
+// - HEAD   /objects/{key}   fetches the metadata of key from one of its replicas
+// - DELETE /objects/{key}   stops storing and advertising key on the local node
+// - GET    /locations/{key} lists the replicas advertising key as JSON
+//
+// The ETag of an object is its quoted version. A PUT with an If-Match header holding the ETag of the
+// newest version, or with If-None-Match: *, only stores if the key has not changed. Bodies over the maximum body size of the node (see
+// WithMaxBodySize) are rejected with 413. Errors are returned as a JSON object with an "error" field.
+func NewHTTPHandler(local *Node) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/objects/", local.handleObject)
+	mux.HandleFunc("/locations/", local.handleLocations)
+	return mux
+}
+
+// Start serving a gateway on the given address, in the background. The server is closed when the node exits.
+func (local *Node) serveHTTP(addr string, name string, handler http.Handler) error {
+	lis, err := listen(addr)
+	if err != nil {
+		return err
+	}
+	server := &http.Server{Handler: handler}
+	local.httpServers = append(local.httpServers, server)
+	go server.Serve(lis)
+	Out.Printf("Serving %v on %v\n", name, lis.Addr())
+	return nil
+}
+
+func (local *Node) handleObject(w http.ResponseWriter, r *http.Request) {
+	key := strings.TrimPrefix(r.URL.Path, "/objects/")
+	if key == "" {
+		writeHTTPError(w, http.StatusBadRequest, errors.New("missing key"))
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPut:
+		value, err := local.readBody(w, r)
+		if err != nil {
+			writeHTTPError(w, bodyStatus(err), err)
+			return
+		}
+		opts := []StoreOption{WithContentType(r.Header.Get("Content-Type"))}
+		if header := r.Header.Get(TTLHEADER); header != "" {
+			ttl, err := time.ParseDuration(header)
+			if err != nil || ttl <= 0 {
+				writeHTTPError(w, http.StatusBadRequest, fmt.Errorf("invalid %v %q", TTLHEADER, header))
+				return
+			}
+			opts = append(opts, WithTTL(ttl))
+		}
+		config := newStoreConfig(opts)
+		if match := r.Header.Get("If-Match"); match != "" {
+			expected, err := ParseVersion(strings.Trim(match, "\""))
+			if err != nil {
+				writeHTTPError(w, http.StatusBadRequest, err)
+				return
+			}
+			config.conditional, config.expected = true, expected
+		} else if r.Header.Get("If-None-Match") == "*" {
+			config.conditional = true
+		}
+		version, err := local.store(key, value, config)
+		if err != nil {
+			writeHTTPError(w, httpStatus(err), err)
+			return
+		}
+		w.Header().Set("ETag", versionETag(version))
+		w.WriteHeader(http.StatusNoContent)
+
+	case http.MethodGet:
+		value, metadata, err := local.get(key)
+		if err != nil {
+			writeHTTPError(w, httpStatus(err), err)
+			return
+		}
+		metadata.Size = len(value)
+		writeMetadataHeaders(w, metadata)
+		w.Header().Set("ETag", versionETag(metadata.Version))
+		io.Copy(w, bytes.NewReader(value))
+
+	case http.MethodHead:
+		metadata, err := local.Stat(key)
+		if err != nil {
+			w.WriteHeader(httpStatus(err))
+			return
+		}
+		writeMetadataHeaders(w, metadata)
+		w.Header().Set("ETag", versionETag(metadata.Version))
+		w.WriteHeader(http.StatusOK)
+
+	case http.MethodDelete:
+		if !local.Remove(key) {
+			writeHTTPError(w, http.StatusNotFound, errors.New("this node is not storing "+key))
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		w.Header().Set("Allow", "GET, HEAD, PUT, DELETE")
+		writeHTTPError(w, http.StatusMethodNotAllowed, errors.New(r.Method+" is not supported"))
+	}
+}
+
+func (local *Node) handleLocations(w http.ResponseWriter, r *http.Request) {
+	key := strings.TrimPrefix(r.URL.Path, "/locations/")
+	if key == "" {
+		writeHTTPError(w, http.StatusBadRequest, errors.New("missing key"))
+		return
+	}
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", "GET")
+		writeHTTPError(w, http.StatusMethodNotAllowed, errors.New(r.Method+" is not supported"))
+		return
+	}
+
+	replicas, err := local.Lookup(key)
+	if err != nil {
+		writeHTTPError(w, http.StatusBadGateway, err)
+		return
+	}
+	if len(replicas) == 0 {
+		writeHTTPError(w, http.StatusNotFound, &NotFoundError{Key: key})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"key": key, "replicas": replicas})
+}
+
+// Read the request body, failing with a bodyTooLargeError once it exceeds the node's maximum body size
+func (local *Node) readBody(w http.ResponseWriter, r *http.Request) ([]byte, error) {
+	if r.ContentLength > local.maxBodySize {
+		return nil, &bodyTooLargeError{limit: local.maxBodySize}
+	}
+	var value bytes.Buffer
+	if _, err := io.Copy(&value, http.MaxBytesReader(w, r.Body, local.maxBodySize)); err != nil {
+		// MaxBytesReader has no error type of its own in this Go version
+		if int64(value.Len()) >= local.maxBodySize {
+			return nil, &bodyTooLargeError{limit: local.maxBodySize}
+		}
+		return nil, err
+	}
+	return value.Bytes(), nil
+}
+
+// Returned when a request body exceeds the node's maximum body size
+type bodyTooLargeError struct {
+	limit int64
+}
+
+func (e *bodyTooLargeError) Error() string {
+	return fmt.Sprintf("request body exceeds the limit of %v bytes", e.limit)
+}
+
+// Maps errors reading a request body to HTTP status codes
+func bodyStatus(err error) int {
+	var tooLarge *bodyTooLargeError
+	if errors.As(err, &tooLarge) {
+		return http.StatusRequestEntityTooLarge
+	}
+	return http.StatusBadRequest
+}
+
+// Describes a blob in the response headers
+func writeMetadataHeaders(w http.ResponseWriter, metadata Metadata) {
+	contentType := metadata.ContentType
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Length", strconv.Itoa(metadata.Size))
+	if !metadata.Modified.IsZero() {
+		w.Header().Set("Last-Modified", metadata.Modified.UTC().Format(http.TimeFormat))
+	}
+	if !metadata.Expires.IsZero() {
+		w.Header().Set("Expires", metadata.Expires.UTC().Format(http.TimeFormat))
+	}
+}
+
+// Maps errors returned by the node to HTTP status codes
+func httpStatus(err error) int {
+	var notFound *NotFoundError
+	if errors.As(err, &notFound) {
+		return http.StatusNotFound
+	}
+	var conflict *VersionConflictError
+	if errors.As(err, &conflict) {
+		return http.StatusPreconditionFailed
+	}
+	var full *StoreFullError
+	if errors.As(err, &full) {
+		return http.StatusInsufficientStorage
+	}
+	return http.StatusBadGateway
+}
+
+// The ETag of an object is its quoted version
+func versionETag(version Version) string {
+	return "\"" + version.String() + "\""
+}
+
+func writeHTTPError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
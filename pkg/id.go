@@ -163,6 +163,17 @@ func (digit Digit) String() string {
 	return fmt.Sprintf("%X", byte(digit))
 }
 
+// MarshalText encodes an ID as its hexstring, so IDs appear as strings in JSON
+func (id ID) MarshalText() ([]byte, error) {
+	return []byte(id.String()), nil
+}
+
+// UnmarshalText parses an ID from its hexstring
+func (id *ID) UnmarshalText(text []byte) (err error) {
+	*id, err = ParseID(string(text))
+	return err
+}
+
 func (id ID) bytes() []byte {
 	b := make([]byte, len(id))
 	for idx, d := range id {
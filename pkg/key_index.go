@@ -0,0 +1,66 @@
+/*
+ *  Brown University, CS138, Spring 2022
+ *
+ *  Purpose: Defines the KeyIndex type, which records a summary of the objects
+ *  written through an S3 gateway.
+ */
+
+package pkg
+
+import (
+	"sync"
+	"time"
+)
+
+// KeyIndex keeps a summary of each object written through an S3 gateway, so that listings can give
+// their size, modification time and MD5 ETag without contacting their replicas. Listings do not come
+// from the index, which only knows the objects written through one gateway: they list the keys of the
+// whole tapestry with ListKeys, and look each up in the index. Access to the index is protected by a mutex.
+type KeyIndex struct {
+	entries map[string]IndexEntry
+	mutex   sync.Mutex
+}
+
+// IndexEntry summarises an object in the index
+type IndexEntry struct {
+	Key      string
+	Size     int
+	ETag     string
+	Modified time.Time
+}
+
+// NewKeyIndex creates an empty key index
+func NewKeyIndex() *KeyIndex {
+	index := new(KeyIndex)
+	index.entries = make(map[string]IndexEntry)
+	return index
+}
+
+// Put adds or replaces the entry for a key
+func (index *KeyIndex) Put(entry IndexEntry) {
+	index.mutex.Lock()
+	defer index.mutex.Unlock()
+
+	index.entries[entry.Key] = entry
+}
+
+// Get the entry for a key
+func (index *KeyIndex) Get(key string) (IndexEntry, bool) {
+	index.mutex.Lock()
+	defer index.mutex.Unlock()
+
+	entry, exists := index.entries[key]
+	return entry, exists
+}
+
+// Delete the entry for a key. Returns true if the key was in the index.
+func (index *KeyIndex) Delete(key string) bool {
+	index.mutex.Lock()
+	defer index.mutex.Unlock()
+
+	if _, exists := index.entries[key]; !exists {
+		return false
+	}
+	delete(index.entries, key)
+	return true
+}
@@ -0,0 +1,147 @@
+/*
+ *  Brown University, CS138, Spring 2022
+ *
+ *  Purpose: Measures the round trip time to other nodes, so that replicas can
+ *  be ranked by proximity, and the latency of fetches, so that slow fetches
+ *  can be hedged.
+ */
+
+package pkg
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// RTTWEIGHT is the weight of each new sample in the moving average of a node's round trip time
+const RTTWEIGHT = 0.2
+
+// LATENCYSAMPLES is how many recent fetch latencies are kept to compute percentiles from
+const LATENCYSAMPLES = 100
+
+// MINLATENCYSAMPLES is how many fetch latencies must be measured before fetches are hedged
+const MINLATENCYSAMPLES = 10
+
+// RTTREFRESH is how long the round trip time to a node is trusted before the node is probed again
+const RTTREFRESH = 30 * time.Second
+
+// LatencyTracker keeps a moving average of the round trip time to each address, and a window
+// of recent fetch latencies. Each node has its own tracker, which only measures the calls the node
+// makes itself: the hellos it probes other nodes with, and the blobs it fetches.
+type LatencyTracker struct {
+	rtt      map[string]time.Duration
+	measured map[string]time.Time // When the round trip time to each address was last measured
+	probing  map[string]bool      // Addresses whose round trip time is being measured
+	fetches  []time.Duration      // A ring buffer of the last LATENCYSAMPLES fetch latencies
+	next     int                  // Where the next fetch latency goes in the ring buffer
+	mutex    sync.RWMutex
+}
+
+// NewLatencyTracker creates a tracker with no measurements
+func NewLatencyTracker() *LatencyTracker {
+	return &LatencyTracker{
+		rtt:      make(map[string]time.Duration),
+		measured: make(map[string]time.Time),
+		probing:  make(map[string]bool),
+	}
+}
+
+// Measure the round trip time to addr with a hello in the background, unless it was measured within
+// RTTREFRESH or is already being measured
+func (lt *LatencyTracker) probe(addr string, from RemoteNode) {
+	lt.mutex.Lock()
+	defer lt.mutex.Unlock()
+	if measured, known := lt.measured[addr]; (known && time.Since(measured) < RTTREFRESH) || lt.probing[addr] {
+		return
+	}
+	lt.probing[addr] = true
+	go func() {
+		start := time.Now()
+		if _, err := SayHelloRPC(addr, from); err == nil {
+			lt.Observe(addr, time.Since(start))
+		}
+		lt.mutex.Lock()
+		delete(lt.probing, addr)
+		lt.mutex.Unlock()
+	}()
+}
+
+// Observe a round trip time to addr
+func (lt *LatencyTracker) Observe(addr string, rtt time.Duration) {
+	lt.mutex.Lock()
+	defer lt.mutex.Unlock()
+	if average, exists := lt.rtt[addr]; exists {
+		lt.rtt[addr] = average + time.Duration(RTTWEIGHT*float64(rtt-average))
+	} else {
+		lt.rtt[addr] = rtt
+	}
+	lt.measured[addr] = time.Now()
+}
+
+// RTT returns the average round trip time to addr, or false if it has never been measured
+func (lt *LatencyTracker) RTT(addr string) (time.Duration, bool) {
+	lt.mutex.RLock()
+	defer lt.mutex.RUnlock()
+	rtt, exists := lt.rtt[addr]
+	return rtt, exists
+}
+
+// ObserveFetch records the latency of fetching a blob from a replica
+func (lt *LatencyTracker) ObserveFetch(latency time.Duration) {
+	lt.mutex.Lock()
+	defer lt.mutex.Unlock()
+	if len(lt.fetches) < LATENCYSAMPLES {
+		lt.fetches = append(lt.fetches, latency)
+	} else {
+		lt.fetches[lt.next] = latency
+	}
+	lt.next = (lt.next + 1) % LATENCYSAMPLES
+}
+
+// FetchPercentile returns the latency that the given fraction of recent fetches finished within,
+// such as 0.95, or false if fewer than MINLATENCYSAMPLES fetches have been measured
+func (lt *LatencyTracker) FetchPercentile(percentile float64) (time.Duration, bool) {
+	lt.mutex.RLock()
+	samples := append([]time.Duration(nil), lt.fetches...)
+	lt.mutex.RUnlock()
+	if len(samples) < MINLATENCYSAMPLES {
+		return 0, false
+	}
+	sort.Slice(samples, func(i, j int) bool {
+		return samples[i] < samples[j]
+	})
+	index := int(percentile * float64(len(samples)))
+	if index >= len(samples) {
+		index = len(samples) - 1
+	} else if index < 0 {
+		index = 0
+	}
+	return samples[index], true
+}
+
+// Rank sorts replicas from the point of view of local: newest version first, and of replicas
+// holding the same version, the local node first, then by increasing round trip time as measured by
+// this tracker. Nodes whose round trip time is unknown come last, in the order they were given.
+func (lt *LatencyTracker) Rank(local RemoteNode, replicas []Replica) {
+	lt.mutex.RLock()
+	defer lt.mutex.RUnlock()
+	distance := func(node RemoteNode) (time.Duration, bool) {
+		if node == local {
+			return 0, true
+		}
+		rtt, exists := lt.rtt[node.Address]
+		return rtt, exists
+	}
+	sort.SliceStable(replicas, func(i, j int) bool {
+		if replicas[i].Version != replicas[j].Version {
+			return replicas[i].Version > replicas[j].Version
+		}
+		first, knownFirst := distance(replicas[i].Node)
+		second, knownSecond := distance(replicas[j].Node)
+		if knownFirst != knownSecond {
+			return knownFirst
+		}
+		return first < second
+	})
+}
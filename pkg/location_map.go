@@ -7,79 +7,175 @@
 package pkg
 
 import (
+	"crypto/sha1"
+	"encoding/binary"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 )
 
 // LocationMap is struct containing objects being advertised to the tapestry.
-// Object mappings are stored in the root node. An object can be advertised by multiple nodes.
-// Objects time out after some amount of time if the advertising node is not heard from.
+// Object mappings are stored in the root node. An object can be advertised by multiple nodes,
+// each holding some version of the object.
+// Objects time out after some amount of time if the advertising node is not heard from, or when
+// the object itself expires.
 type LocationMap struct {
-	Data  map[string]map[RemoteNode]*time.Timer // Multimap: stores multiple nodes per key, and each node has a timeout
-	mutex sync.Mutex                            // To manage concurrent access to the location map
+	Data  map[string]map[RemoteNode]*Location // Multimap: stores multiple nodes per key, and each node has a version and timeout
+	mutex sync.Mutex                          // To manage concurrent access to the location map
+}
+
+// Location is the registration of one replica of a key
+type Location struct {
+	Version Version   // The version of the object held by the replica
+	Expires time.Time // When the object expires, or the zero time if it does not
+	Soft    bool      // The replica is a cached copy
+	timer   *time.Timer
+}
+
+// Replica is a node advertising a key, along with the version of the object it holds
+type Replica struct {
+	Node    RemoteNode `json:"node"`
+	Version Version    `json:"version"`
+	Expires time.Time  `json:"expires,omitempty"` // When the object expires, or the zero time if it does not
+	Soft    bool       `json:"soft,omitempty"`    // The replica is a cached copy with a short lease
 }
 
 // NewLocationMap creates a new objectstore.
 func NewLocationMap() *LocationMap {
 	m := new(LocationMap)
-	m.Data = make(map[string]map[RemoteNode]*time.Timer)
+	m.Data = make(map[string]map[RemoteNode]*Location)
 	return m
 }
 
-// Register registers the specified node as having advertised the key.
-// Times out after the specified duration.
-func (store *LocationMap) Register(key string, replica RemoteNode, timeout time.Duration) bool {
+// Register registers the specified replica as having advertised its version of the key.
+// Times out after the specified duration, or when the object expires if that is sooner.
+func (store *LocationMap) Register(key string, replica Replica, timeout time.Duration) bool {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	return store.register(key, replica, timeout)
+}
+
+// RegisterIf registers the specified replica like Register, but only if the newest version currently
+// registered for the key is expected. Returns the newest registered version before the call, and whether
+// the replica was registered.
+func (store *LocationMap) RegisterIf(key string, replica Replica, expected Version, timeout time.Duration) (Version, bool) {
 	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	newest := newestVersion(store.Data[key])
+	if newest != expected {
+		return newest, false
+	}
+	store.register(key, replica, timeout)
+	return newest, true
+}
+
+// Newest returns the newest version registered for the key, or zero if the key is not registered
+func (store *LocationMap) Newest(key string) Version {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	return newestVersion(store.Data[key])
+}
+
+// Adds or refreshes a registration. The mutex must be held.
+func (store *LocationMap) register(key string, replica Replica, timeout time.Duration) bool {
+	// An object that has already expired is not registered, and drops any earlier registration
+	if !replica.Expires.IsZero() {
+		remaining := time.Until(replica.Expires)
+		if remaining <= 0 {
+			store.remove(key, replica.Node)
+			return false
+		}
+		if remaining < timeout {
+			timeout = remaining
+		}
+	}
 
 	// Get the value set for the object
 	_, exists := store.Data[key]
 	if !exists {
-		store.Data[key] = make(map[RemoteNode]*time.Timer)
+		store.Data[key] = make(map[RemoteNode]*Location)
 	}
 
 	// Add the value to the value set
-	timer, exists := store.Data[key][replica]
+	location, exists := store.Data[key][replica.Node]
 	if !exists {
-		store.Data[key][replica] = store.newTimeout(key, replica, timeout)
+		store.Data[key][replica.Node] = &Location{replica.Version, replica.Expires, replica.Soft, store.newTimeout(key, replica.Node, timeout)}
 	} else {
-		timer.Reset(TIMEOUT)
+		location.Version = replica.Version
+		location.Expires = replica.Expires
+		location.Soft = replica.Soft
+		location.timer.Reset(timeout)
 	}
 
-	store.mutex.Unlock()
-
 	return !exists
 }
 
 // RegisterAll registers all of the provided nodes and keys.
-func (store *LocationMap) RegisterAll(replicamap map[string][]RemoteNode, timeout time.Duration) {
+func (store *LocationMap) RegisterAll(replicamap map[string][]Replica, timeout time.Duration) {
 	store.mutex.Lock()
 
 	for key, replicas := range replicamap {
-		_, exists := store.Data[key]
-		if !exists {
-			store.Data[key] = make(map[RemoteNode]*time.Timer)
-		}
 		for _, replica := range replicas {
-			store.Data[key][replica] = store.newTimeout(key, replica, timeout)
+			store.register(key, replica, timeout)
 		}
 	}
 
 	store.mutex.Unlock()
 }
 
+// RegisterMissing registers the replicas of the key held by nodes that are not registered for it yet.
+// Returns the replicas that were registered.
+func (store *LocationMap) RegisterMissing(key string, replicas []Replica, timeout time.Duration) (registered []Replica) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	for _, replica := range replicas {
+		if _, exists := store.Data[key][replica.Node]; !exists && store.register(key, replica, timeout) {
+			registered = append(registered, replica)
+		}
+	}
+	return registered
+}
+
 // Unregister unregisters the specified node for the specified key.
 // Returns false if the node was not registered for the key.
 func (store *LocationMap) Unregister(key string, replica RemoteNode) bool {
 	store.mutex.Lock()
 
-	_, existed := store.Data[key][replica]
-	delete(store.Data[key], replica)
+	existed := store.remove(key, replica)
 
 	store.mutex.Unlock()
 
 	return existed
 }
 
+// UnregisterVersion unregisters the specified replica for the specified key, but only if its node is
+// registered with the replica's version. Returns false if it was not.
+func (store *LocationMap) UnregisterVersion(key string, replica Replica) bool {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	location, exists := store.Data[key][replica.Node]
+	if !exists || location.Version != replica.Version {
+		return false
+	}
+	return store.remove(key, replica.Node)
+}
+
+// Removes a registration and stops its timer. The mutex must be held.
+func (store *LocationMap) remove(key string, replica RemoteNode) bool {
+	location, exists := store.Data[key][replica]
+	if exists {
+		location.timer.Stop()
+		delete(store.Data[key], replica)
+	}
+	return exists
+}
+
 // UnregisterAll unregisters all nodes that are registered for the provided key.
 // Returns all replicas that were advertising the key.
 func (store *LocationMap) UnregisterAll(key string) (replicas []RemoteNode) {
@@ -93,27 +189,158 @@ func (store *LocationMap) UnregisterAll(key string) (replicas []RemoteNode) {
 	return
 }
 
-// Get the nodes that are advertising a given key.
+// Take unregisters all nodes that are registered for the provided key, and returns their replicas.
+func (store *LocationMap) Take(key string) (replicas []Replica) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	replicas = replicaSlice(store.Data[key])
+	for _, replica := range replicas {
+		store.remove(key, replica.Node)
+	}
+	delete(store.Data, key)
+	return replicas
+}
+
+// Get the nodes that are advertising a given key, holders of the newest version first.
 func (store *LocationMap) Get(key string) (replicas []RemoteNode) {
+	for _, replica := range store.Versions(key) {
+		replicas = append(replicas, replica.Node)
+	}
+	return
+}
+
+// Versions returns the nodes that are advertising a given key along with the versions they hold,
+// newest first.
+func (store *LocationMap) Versions(key string) (replicas []Replica) {
 	store.mutex.Lock()
 
-	replicas = slice(store.Data[key])
+	replicas = replicaSlice(store.Data[key])
 
 	store.mutex.Unlock()
 
 	return
 }
 
+// LocationEntry is the registrations of one key in a location map
+type LocationEntry struct {
+	Key      string    `json:"key"`
+	Replicas []Replica `json:"replicas"`
+}
+
+// Entries returns the registrations of every key, sorted by key
+func (store *LocationMap) Entries() (entries []LocationEntry) {
+	store.mutex.Lock()
+
+	for key, values := range store.Data {
+		if len(values) > 0 {
+			entries = append(entries, LocationEntry{key, replicaSlice(values)})
+		}
+	}
+
+	store.mutex.Unlock()
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Key < entries[j].Key
+	})
+	return entries
+}
+
+// Keys returns up to limit keys in sorted order that start with prefix and sort after the given key.
+// Returns true if more matching keys remain after those returned, which is never the case if none
+// are returned.
+func (store *LocationMap) Keys(prefix string, after string, limit int) (keys []string, more bool) {
+	store.mutex.Lock()
+
+	keys = make([]string, 0)
+	for key, values := range store.Data {
+		if len(values) > 0 && strings.HasPrefix(key, prefix) && key > after {
+			keys = append(keys, key)
+		}
+	}
+
+	store.mutex.Unlock()
+
+	sort.Strings(keys)
+	if limit <= 0 {
+		return keys[:0], false
+	}
+	if len(keys) > limit {
+		return keys[:limit], true
+	}
+	return keys, false
+}
+
+// Summary digests the registrations of the keys whose hash starts with prefix, a string of hex digits,
+// split by the digit that follows the prefix. Each of the BASE digests is the XOR of the digests of
+// the keys under prefix and that digit, so the summary of a prefix is the XOR of the summaries of its
+// children, and two location maps holding the same registrations have the same summaries. A digest
+// is nil if there are no keys under it.
+func (store *LocationMap) Summary(prefix string) [][]byte {
+	digests := make([][]byte, BASE)
+
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	for key, values := range store.Data {
+		hash := Hash(key)
+		if len(values) == 0 || len(prefix) >= DIGITS || !strings.HasPrefix(hash.String(), prefix) {
+			continue
+		}
+		digit := hash[len(prefix)]
+		if digests[digit] == nil {
+			digests[digit] = make([]byte, sha1.Size)
+		}
+		for i, b := range digestRegistrations(key, values) {
+			digests[digit][i] ^= b
+		}
+	}
+	return digests
+}
+
+// KeysWithHashPrefix returns the keys whose hash starts with prefix, a string of hex digits, in sorted order
+func (store *LocationMap) KeysWithHashPrefix(prefix string) (keys []string) {
+	store.mutex.Lock()
+
+	for key, values := range store.Data {
+		if len(values) > 0 && strings.HasPrefix(Hash(key).String(), prefix) {
+			keys = append(keys, key)
+		}
+	}
+
+	store.mutex.Unlock()
+
+	sort.Strings(keys)
+	return keys
+}
+
+// Utility function to digest the registrations of a key, independently of their order
+func digestRegistrations(key string, valmap map[RemoteNode]*Location) []byte {
+	nodes := slice(valmap)
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].Address < nodes[j].Address
+	})
+	sha := sha1.New()
+	sha.Write([]byte(key))
+	for _, node := range nodes {
+		location := valmap[node]
+		sha.Write([]byte(node.ID.String() + node.Address))
+		binary.Write(sha, binary.BigEndian, uint64(location.Version))
+		binary.Write(sha, binary.BigEndian, location.Soft)
+	}
+	return sha.Sum(nil)
+}
+
 // GetTransferRegistrations removes and returns all objects that should be transferred to the remote node.
-func (store *LocationMap) GetTransferRegistrations(local RemoteNode, remote RemoteNode) map[string][]RemoteNode {
-	transfer := make(map[string][]RemoteNode)
+func (store *LocationMap) GetTransferRegistrations(local RemoteNode, remote RemoteNode) map[string][]Replica {
+	transfer := make(map[string][]Replica)
 
 	store.mutex.Lock()
 
 	for key, values := range store.Data {
 		// Compare the first digit after the prefix
 		if Hash(key).IsNewRoute(remote.ID, local.ID) {
-			transfer[key] = slice(values)
+			transfer[key] = replicaSlice(values)
 		}
 	}
 
@@ -133,11 +360,7 @@ func (store *LocationMap) newTimeout(key string, replica RemoteNode, timeout tim
 
 		store.mutex.Lock()
 
-		timer, exists := store.Data[key][replica]
-		if exists {
-			timer.Stop()
-			delete(store.Data[key], replica)
-		}
+		store.remove(key, replica)
 
 		store.mutex.Unlock()
 	}
@@ -146,9 +369,51 @@ func (store *LocationMap) newTimeout(key string, replica RemoteNode, timeout tim
 }
 
 // Utility function to get the keys of a map
-func slice(valmap map[RemoteNode]*time.Timer) (values []RemoteNode) {
+func slice(valmap map[RemoteNode]*Location) (values []RemoteNode) {
 	for value := range valmap {
 		values = append(values, value)
 	}
 	return
 }
+
+// Utility function to get the replicas in a map, newest version first and of those primary copies first
+func replicaSlice(valmap map[RemoteNode]*Location) (replicas []Replica) {
+	for node, location := range valmap {
+		replicas = append(replicas, Replica{node, location.Version, location.Expires, location.Soft})
+	}
+	sort.Slice(replicas, func(i, j int) bool {
+		if replicas[i].Version != replicas[j].Version {
+			return replicas[i].Version > replicas[j].Version
+		}
+		return !replicas[i].Soft && replicas[j].Soft
+	})
+	return
+}
+
+// Utility function to get the newest version in a map
+func newestVersion(valmap map[RemoteNode]*Location) (newest Version) {
+	for _, location := range valmap {
+		if location.Version > newest {
+			newest = location.Version
+		}
+	}
+	return
+}
+
+// The nodes holding replicas, newest version first, and of those primary copies before cached copies,
+// keeping the given order otherwise. Metadata is read from primary copies where possible, since
+// cached copies also carry their lease.
+func primariesFirst(replicas []Replica) []RemoteNode {
+	sorted := append([]Replica(nil), replicas...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Version != sorted[j].Version {
+			return sorted[i].Version > sorted[j].Version
+		}
+		return !sorted[i].Soft && sorted[j].Soft
+	})
+	nodes := make([]RemoteNode, len(sorted))
+	for i, replica := range sorted {
+		nodes[i] = replica.Node
+	}
+	return nodes
+}
@@ -14,6 +14,7 @@ import (
 	"log"
 	"os"
 	"strings"
+	"sync"
 
 	"google.golang.org/grpc/grpclog"
 )
@@ -39,13 +40,52 @@ func init() {
 	grpclog.SetLogger(log.New(ioutil.Discard, "", log.Ltime))
 }
 
+// LogLevels are the levels accepted by SetLogLevel, from the least to the most verbose
+var LogLevels = []string{"error", "info", "debug", "trace"}
+
+var logLevel = "info"
+var logLevelMutex sync.Mutex
+
 // SetDebug turns debug on or off
 func SetDebug(enabled bool) {
 	if enabled {
-		Debug.SetOutput(os.Stdout)
+		SetLogLevel("debug")
 	} else {
-		Debug.SetOutput(ioutil.Discard)
+		SetLogLevel("info")
+	}
+}
+
+// SetLogLevel sets which loggers write output: error only writes Error, info also writes Out, debug
+// also writes Debug and trace also writes Trace. Returns the previous level.
+func SetLogLevel(level string) (previous string, err error) {
+	verbosity := -1
+	for i, name := range LogLevels {
+		if name == level {
+			verbosity = i
+		}
+	}
+	if verbosity < 0 {
+		return "", fmt.Errorf("unknown log level %v, expected one of %v", level, strings.Join(LogLevels, ", "))
+	}
+
+	logLevelMutex.Lock()
+	defer logLevelMutex.Unlock()
+	for i, logger := range []*log.Logger{Out, Debug, Trace} {
+		if i < verbosity {
+			logger.SetOutput(os.Stdout)
+		} else {
+			logger.SetOutput(ioutil.Discard)
+		}
 	}
+	previous, logLevel = logLevel, level
+	return previous, nil
+}
+
+// LogLevel returns the level last set by SetLogLevel, info by default
+func LogLevel() string {
+	logLevelMutex.Lock()
+	defer logLevelMutex.Unlock()
+	return logLevel
 }
 
 // RoutingTableToString stringifies the routing table
@@ -75,7 +115,7 @@ func (local *Node) LocationMapToString() string {
 	var buffer bytes.Buffer
 	fmt.Fprintf(&buffer, "LocationMap for node %v\n", local.Node)
 	for key, values := range local.LocationsByKey.Data {
-		fmt.Fprintf(&buffer, " %v: %v\n", key, slice(values))
+		fmt.Fprintf(&buffer, " %v: %v\n", key, replicaSlice(values))
 	}
 
 	return buffer.String()
@@ -105,11 +145,14 @@ func (local *Node) PrintBackpointers() {
 	fmt.Printf(local.BackpointersToString())
 }
 
-// BlobStoreToString stringifies the blob store
+// BlobStoreToString stringifies the blob store, with the metadata of each blob
 func (local *Node) BlobStoreToString() string {
 	var buffer bytes.Buffer
-	for k := range local.blobstore.blobs {
-		fmt.Fprintln(&buffer, k)
+	for _, key := range local.blobstore.Keys() {
+		metadata, exists := local.blobstore.Stat(key)
+		if exists {
+			fmt.Fprintf(&buffer, "%v  %v\n", key, metadata)
+		}
 	}
 	return buffer.String()
 }
@@ -118,3 +161,12 @@ func (local *Node) BlobStoreToString() string {
 func (local *Node) PrintBlobStore() {
 	fmt.Printf(local.BlobStoreToString())
 }
+
+// PublishedKeysToString stringifies the publish status of each key the node is publishing
+func (local *Node) PublishedKeysToString() string {
+	var buffer bytes.Buffer
+	for _, status := range local.PublishedKeys() {
+		fmt.Fprintf(&buffer, "%v\n", status)
+	}
+	return buffer.String()
+}
@@ -0,0 +1,143 @@
+/*
+ *  Brown University, CS138, Spring 2022
+ *
+ *  Purpose: Defines functions that walk every node of a Tapestry mesh, such
+ *  as listing all of the keys advertised in the mesh.
+ */
+
+package pkg
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// KEYPAGESIZE is the number of keys returned by each page of a key listing
+const KEYPAGESIZE = 100
+
+// CRAWLCACHE is how long the later pages of a key listing reuse the nodes found by crawling the
+// mesh for its first page
+const CRAWLCACHE = time.Minute
+
+// CrawlMesh discovers every node reachable from start by walking routing tables.
+// Nodes that cannot be contacted are left out of the result.
+func CrawlMesh(start RemoteNode) ([]RemoteNode, error) {
+	visited := map[RemoteNode]bool{start: true}
+	queue := []RemoteNode{start}
+	nodes := make([]RemoteNode, 0)
+
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+
+		entries, err := node.GetRoutingTableRPC(start)
+		if err != nil {
+			Debug.Printf("Unable to crawl %v: %v\n", node, err)
+			continue
+		}
+		nodes = append(nodes, node)
+		for _, entry := range entries {
+			if !visited[entry.Node] {
+				visited[entry.Node] = true
+				queue = append(queue, entry.Node)
+			}
+		}
+	}
+
+	if len(nodes) == 0 {
+		return nil, fmt.Errorf("unable to crawl the tapestry from %v", start)
+	}
+	return nodes, nil
+}
+
+// Keeps the nodes found by the last crawl of the mesh, so that the later pages of a key listing do
+// not crawl it again. The zero value is an empty cache.
+type crawlCache struct {
+	start   RemoteNode
+	nodes   []RemoteNode
+	expires time.Time
+	mutex   sync.Mutex
+}
+
+// The nodes reachable from start. The first page of a listing crawls the mesh, and later pages
+// reuse its nodes for up to CRAWLCACHE, unless refresh is set.
+func (cache *crawlCache) crawl(start RemoteNode, firstPage bool, refresh bool) ([]RemoteNode, error) {
+	if cache == nil {
+		return CrawlMesh(start)
+	}
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+	if !firstPage && !refresh && cache.start == start && time.Now().Before(cache.expires) {
+		return cache.nodes, nil
+	}
+	nodes, err := CrawlMesh(start)
+	if err != nil {
+		return nil, err
+	}
+	cache.start, cache.nodes, cache.expires = start, nodes, time.Now().Add(CRAWLCACHE)
+	return nodes, nil
+}
+
+// ListKeys lists the keys advertised anywhere in the tapestry that start with prefix, in sorted order.
+// Every node in the mesh is asked for the keys it is root for, so results are deduplicated if two
+// nodes both believe they are root for a key.
+//
+// Pass an empty pageToken to get the first page, then the returned token to get the next. The mesh
+// is crawled for the first page, and later pages ask the same nodes, so nodes that join during a
+// listing may be missed. The returned token is empty when there are no more keys.
+func (local *Node) ListKeys(prefix string, pageToken string) (keys []string, nextPageToken string, err error) {
+	return listKeys(local.Node, local.crawls, prefix, pageToken)
+}
+
+// List one page of keys, starting the crawl of the mesh from the given node. If a node found by
+// an earlier crawl no longer answers, the mesh is crawled again.
+func listKeys(start RemoteNode, cache *crawlCache, prefix string, pageToken string) (keys []string, nextPageToken string, err error) {
+	nodes, err := cache.crawl(start, pageToken == "", false)
+	if err != nil {
+		return nil, "", err
+	}
+	keys, nextPageToken, err = listKeysOn(nodes, prefix, pageToken)
+	if err != nil && pageToken != "" {
+		if nodes, err = cache.crawl(start, false, true); err != nil {
+			return nil, "", err
+		}
+		keys, nextPageToken, err = listKeysOn(nodes, prefix, pageToken)
+	}
+	return keys, nextPageToken, err
+}
+
+// List one page of the keys the given nodes are root for
+func listKeysOn(nodes []RemoteNode, prefix string, pageToken string) (keys []string, nextPageToken string, err error) {
+
+	// Each node returns its first page of keys after the token, so the first page of the
+	// merged keys is complete
+	unique := make(map[string]bool)
+	more := false
+	for _, node := range nodes {
+		nodeKeys, nodeMore, err := node.ListKeysRPC(prefix, pageToken, KEYPAGESIZE)
+		if err != nil {
+			return nil, "", fmt.Errorf("unable to list keys on %v: %v", node, err)
+		}
+		for _, key := range nodeKeys {
+			unique[key] = true
+		}
+		more = more || nodeMore
+	}
+
+	keys = make([]string, 0, len(unique))
+	for key := range unique {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	if len(keys) > KEYPAGESIZE {
+		keys = keys[:KEYPAGESIZE]
+		more = true
+	}
+	if more {
+		nextPageToken = keys[len(keys)-1]
+	}
+	return keys, nextPageToken, nil
+}
@@ -7,45 +7,403 @@
 package pkg
 
 import (
+	"errors"
 	"fmt"
 	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
+// NotFoundError is returned when no replicas are advertising the requested key
+type NotFoundError struct {
+	Key string
+}
+
+func (e *NotFoundError) Error() string {
+	return fmt.Sprintf("No replicas returned for key %v", e.Key)
+}
+
+// StoreOption configures optional behaviour of Store
+type StoreOption func(*storeConfig)
+
+type storeConfig struct {
+	metadata    Metadata // Metadata to store with the blob
+	conditional bool     // Only store if the newest version of the key is expected
+	expected    Version
+}
+
+// WithContentType records the content type of the stored blob in its metadata
+func WithContentType(contentType string) StoreOption {
+	return func(c *storeConfig) {
+		c.metadata.ContentType = contentType
+	}
+}
+
+// WithTTL makes the stored blob expire after the given duration. Once it expires, the blob is deleted
+// from the node storing it, republishing stops and the root drops its location.
+func WithTTL(ttl time.Duration) StoreOption {
+	return func(c *storeConfig) {
+		if ttl > 0 {
+			c.metadata.Expires = time.Now().Add(ttl)
+		}
+	}
+}
+
+// WithTags records user-defined tags in the metadata of the stored blob
+func WithTags(tags map[string]string) StoreOption {
+	return func(c *storeConfig) {
+		c.metadata.Tags = tags
+	}
+}
+
+// Store the blob only if the newest version of the key is expected, for StoreIf on a remote node
+func ifVersion(expected Version) StoreOption {
+	return func(c *storeConfig) {
+		c.conditional = true
+		c.expected = expected
+	}
+}
+
+// Make the stored blob expire at the given time, for blobs moved to another node with their expiry
+func withExpiry(expires time.Time) StoreOption {
+	return func(c *storeConfig) {
+		c.metadata.Expires = expires
+	}
+}
+
+func newStoreConfig(opts []StoreOption) storeConfig {
+	config := storeConfig{}
+	for _, opt := range opts {
+		opt(&config)
+	}
+	return config
+}
+
 // Store a blob on the local node and publish the key to the tapestry.
-func (local *Node) Store(key string, value []byte) (err error) {
-	done, err := local.Publish(key)
+// The blob is given a version newer than any the root has registered for the key. If the root keeps
+// reporting a newer version for every retry, the blob is not stored and a VersionConflictError is returned.
+func (local *Node) Store(key string, value []byte, opts ...StoreOption) (err error) {
+	_, err = local.store(key, value, newStoreConfig(opts))
+	return err
+}
+
+// StoreIf stores a blob on the local node and publishes the key to the tapestry, but only if the newest
+// version of the key is expected; pass zero to only store a key that does not exist yet. The check and the
+// registration happen atomically on the root, so of several concurrent writers expecting the same version
+// only one succeeds. Returns the version of the stored blob, or a VersionConflictError.
+func (local *Node) StoreIf(key string, value []byte, expected Version, opts ...StoreOption) (Version, error) {
+	return local.store(key, value, newStoreConfig(append(opts, ifVersion(expected))))
+}
+
+// Register a new version of the key with the root, then keep the blob and republish it
+func (local *Node) store(key string, value []byte, config storeConfig) (version Version, err error) {
+	if local.Draining() {
+		return 0, &DrainingError{Key: key}
+	}
+	// Reject blobs that cannot fit before advertising them
+	if err = local.blobstore.Admits(key, len(value)); err != nil {
+		return 0, err
+	}
+
+	// Only versions the root reports are observed, never the expected version the caller gives, which
+	// the root has not vouched for
+	replica := Replica{Node: local.Node, Expires: config.metadata.Expires}
+	var root RemoteNode
+	var newest Version
+	for i := 0; i < local.retries; i++ {
+		replica.Version = local.clock.Now()
+		root, newest, err = local.attemptPublish(key, replica, config.conditional, config.expected)
+		if err != nil {
+			return 0, err
+		}
+		if newest < replica.Version {
+			break
+		}
+		// Another replica registered the same or a newer version, whose node's clock is ahead of ours
+		local.clock.Observe(newest)
+	}
+	if newest >= replica.Version {
+		// Lookups would never return our version first, so the write is refused rather than lost
+		local.unpublishVersion(key, replica.Version)
+		return 0, &VersionConflictError{Key: key, Expected: config.expected, Current: newest}
+	}
+
+	config.metadata.Version = replica.Version
+	if err = local.keep(key, value, config.metadata, replica, root); err != nil {
+		return 0, err
+	}
+	return replica.Version, nil
+}
+
+// Keep a blob that was just registered with root in the blob store, and republish it until it is removed
+func (local *Node) keep(key string, value []byte, metadata Metadata, replica Replica, root RemoteNode) error {
+	previous := local.republisher.get(key)
+	done := local.republish(key, replica, root)
+	evicted, err := local.blobstore.Put(key, value, metadata, done)
+	for _, evictedKey := range evicted {
+		go local.unpublish(evictedKey)
+	}
 	if err != nil {
+		// The store filled up since the blob was admitted. Only the version that failed is unpublished:
+		// a version stored before goes back to being republished, and is registered again at once.
+		if previous != nil && local.republisher.restore(key, done, previous) {
+			go local.attemptPublish(key, previous.replica, false, 0)
+		} else {
+			done <- true
+			go local.unpublishVersion(key, replica.Version)
+		}
 		return err
 	}
-	local.blobstore.Put(key, value, done)
 	return nil
 }
 
 // Get looks up a key in the tapestry then fetch the corresponding blob from the
 // remote blob store.
 func (local *Node) Get(key string) ([]byte, error) {
+	blob, _, err := local.get(key)
+	return blob, err
+}
+
+// Get a blob along with its metadata. Serves the blob from the local blob store if it holds
+// the newest version, and otherwise caches the fetched blob if caching is enabled.
+func (local *Node) get(key string) ([]byte, Metadata, error) {
 	// Lookup the key
-	replicas, err := local.Lookup(key)
+	replicas, err := local.LookupVersions(key)
+	if err != nil {
+		return nil, Metadata{}, err
+	}
+	return local.getFromReplicas(key, replicas)
+}
+
+// Get a blob along with its metadata from the replicas found by a lookup
+func (local *Node) getFromReplicas(key string, replicas []Replica) ([]byte, Metadata, error) {
+	if len(replicas) == 0 {
+		return nil, Metadata{}, &NotFoundError{Key: key}
+	}
+
+	if metadata, exists := local.blobstore.Stat(key); exists && metadata.Version >= replicas[0].Version {
+		if blob, exists := local.blobstore.Get(key); exists {
+			return blob, metadata, nil
+		}
+	}
+
+	nodes := make([]RemoteNode, 0, len(replicas))
+	for _, replica := range replicas {
+		nodes = append(nodes, replica.Node)
+	}
+	blob, metadata, err := local.fetchFromReplicas(key, nodes)
+	if err == nil && local.cacheLease > 0 {
+		go local.cache(key, blob, metadata)
+	}
+	return blob, metadata, err
+}
+
+// Keep a copy of a fetched blob and publish it as a soft replica whose lease lasts cacheLease,
+// or until the blob itself expires if that is sooner. Cached copies only replace older cached
+// copies, never primary copies, and are evicted like any other blob when the blob store is full.
+func (local *Node) cache(key string, blob []byte, metadata Metadata) {
+	if local.Draining() {
+		return
+	}
+	if existing, exists := local.blobstore.Stat(key); exists && (!existing.Cached || existing.Version >= metadata.Version) {
+		return
+	}
+	if err := local.blobstore.Admits(key, len(blob)); err != nil {
+		Debug.Printf("Not caching %v: %v\n", key, err)
+		return
+	}
+
+	// The blob keeps its own expiry time, so that its metadata reads the same as on primary copies
+	metadata.Lease = time.Now().Add(local.cacheLease)
+	metadata.Cached = true
+	replica := Replica{Node: local.Node, Version: metadata.Version, Expires: metadata.end(), Soft: true}
+	root, _, err := local.attemptPublish(key, replica, false, 0)
 	if err != nil {
-		return nil, err
+		Debug.Printf("Unable to publish cached copy of %v: %v\n", key, err)
+		return
+	}
+	if err := local.keep(key, blob, metadata, replica, root); err != nil {
+		Debug.Printf("Unable to cache %v: %v\n", key, err)
+	}
+}
+
+// Stat looks up a key in the tapestry then fetches the metadata of the corresponding blob
+// from one of its replicas, without transferring the blob itself.
+func (local *Node) Stat(key string) (Metadata, error) {
+	replicas, err := local.LookupVersions(key)
+	if err != nil {
+		return Metadata{}, err
 	}
 	if len(replicas) == 0 {
-		return nil, fmt.Errorf("No replicas returned for key %v", key)
+		return Metadata{}, &NotFoundError{Key: key}
+	}
+	return statFromReplicas(key, primariesFirst(replicas))
+}
+
+// Fetch the blob from the replicas in order. If hedging is enabled and the first replica has not
+// answered within the hedging percentile of recent fetch latencies, the blob is also requested
+// from the second replica, and whichever answers first is used.
+func (local *Node) fetchFromReplicas(key string, replicas []RemoteNode) ([]byte, Metadata, error) {
+	if local.hedge <= 0 || len(replicas) < 2 {
+		return fetchFromReplicasTimed(key, replicas, local.latencies)
+	}
+	delay, ok := local.latencies.FetchPercentile(local.hedge)
+	if !ok {
+		return fetchFromReplicasTimed(key, replicas, local.latencies)
+	}
+	return hedgedFetch(key, replicas, delay, local.latencies)
+}
+
+// The outcome of fetching a blob from one replica
+type fetchResult struct {
+	blob     []byte
+	metadata Metadata
+	err      error
+}
+
+// Fetch the blob from the first replica, and from the second as well if the first has not answered
+// after delay or has failed. Falls back to the remaining replicas in turn if both fail. The latency of
+// each successful fetch is recorded in lt.
+func hedgedFetch(key string, replicas []RemoteNode, delay time.Duration, lt *LatencyTracker) ([]byte, Metadata, error) {
+	results := make(chan fetchResult, 2) // Buffered so the slower fetch can finish after we return
+	fetch := func(replica RemoteNode) {
+		data, metadata, err := timedFetch(key, replica, lt)
+		result := fetchResult{metadata: metadata, err: err}
+		if data != nil {
+			result.blob = *data
+		}
+		results <- result
+	}
+
+	go fetch(replicas[0])
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	pending, hedged := 1, false
+	hedge := func() {
+		if !hedged {
+			Debug.Printf("Hedging fetch of %v with %v\n", key, replicas[1])
+			go fetch(replicas[1])
+			pending, hedged = pending+1, true
+		}
 	}
 
-	// Contact replicas
 	var errs []error
+	missing := 0
+	for pending > 0 {
+		select {
+		case <-timer.C:
+			hedge()
+		case result := <-results:
+			pending--
+			if result.err == nil {
+				return result.blob, result.metadata, nil
+			}
+			errs = append(errs, result.err)
+			if status.Code(result.err) == codes.NotFound {
+				missing++
+			}
+			hedge()
+		}
+	}
+
+	if rest := replicas[2:]; len(rest) > 0 {
+		blob, metadata, err := fetchFromReplicasTimed(key, rest, lt)
+		if err == nil {
+			return blob, metadata, nil
+		}
+		var notFound *NotFoundError
+		if errors.As(err, &notFound) {
+			missing += len(rest)
+		}
+		errs = append(errs, err)
+	}
+	if missing == len(replicas) {
+		return nil, Metadata{}, &NotFoundError{Key: key}
+	}
+	return nil, Metadata{}, fmt.Errorf("Error contacting replicas, %v: %v", replicas, errs)
+}
+
+// Contact each replica in turn until one returns the blob
+func fetchFromReplicas(key string, replicas []RemoteNode) (blob []byte, metadata Metadata, err error) {
+	return fetchFromReplicasTimed(key, replicas, nil)
+}
+
+// Like fetchFromReplicas, recording the latency of the successful fetch in lt if it is not nil
+func fetchFromReplicasTimed(key string, replicas []RemoteNode, lt *LatencyTracker) (blob []byte, metadata Metadata, err error) {
+	err = tryReplicas(key, replicas, func(replica RemoteNode) error {
+		data, dataMetadata, err := timedFetch(key, replica, lt)
+		if data != nil {
+			blob, metadata = *data, dataMetadata
+		}
+		return err
+	})
+	return blob, metadata, err
+}
+
+// Fetch the blob from replica, recording the latency of a successful fetch in lt if it is not nil
+func timedFetch(key string, replica RemoteNode, lt *LatencyTracker) (*[]byte, Metadata, error) {
+	start := time.Now()
+	data, metadata, err := replica.BlobStoreFetchRPC(key)
+	if err == nil && lt != nil {
+		lt.ObserveFetch(time.Since(start))
+	}
+	return data, metadata, err
+}
+
+// Contact each replica in turn until one returns the metadata of the blob
+func statFromReplicas(key string, replicas []RemoteNode) (metadata Metadata, err error) {
+	err = tryReplicas(key, replicas, func(replica RemoteNode) (err error) {
+		metadata, err = replica.BlobStoreStatRPC(key)
+		return err
+	})
+	return metadata, err
+}
+
+// Invoke call on each replica in turn until it succeeds on one. If every replica reports that it
+// no longer stores the key, for example because it was removed, returns a NotFoundError.
+func tryReplicas(key string, replicas []RemoteNode, call func(replica RemoteNode) error) error {
+	var errs []error
+	missing := 0
 	for _, replica := range replicas {
-		blob, err := replica.BlobStoreFetchRPC(key)
-		if err != nil {
-			errs = append(errs, err)
+		err := call(replica)
+		if err == nil {
+			return nil
 		}
-		if blob != nil {
-			return *blob, nil
+		errs = append(errs, err)
+		if status.Code(err) == codes.NotFound {
+			missing++
 		}
 	}
 
-	return nil, fmt.Errorf("Error contacting replicas, %v: %v", replicas, errs)
+	if missing == len(replicas) {
+		return &NotFoundError{Key: key}
+	}
+	return fmt.Errorf("Error contacting replicas, %v: %v", replicas, errs)
+}
+
+// Unregister the local node from the root of a key it no longer stores, rather than wait for
+// the registration to time out
+func (local *Node) unpublish(key string) {
+	root, err := local.FindRootOnRemoteNode(local.Node, Hash(key))
+	if err == nil {
+		err = root.UnregisterRPC(key, local.Node)
+	}
+	if err != nil {
+		Debug.Printf("Unable to unregister %v: %v\n", key, err)
+	}
+}
+
+// Unregister the local node from the root of a key, but only if the root still has the given version
+// registered for it, so that a version the local node registered since is kept
+func (local *Node) unpublishVersion(key string, version Version) {
+	root, err := local.FindRootOnRemoteNode(local.Node, Hash(key))
+	if err == nil {
+		err = root.UnregisterVersionRPC(key, Replica{Node: local.Node, Version: version})
+	}
+	if err != nil {
+		Debug.Printf("Unable to unregister version %v of %v: %v\n", version, key, err)
+	}
 }
 
 // Remove the blob from the local blob store and stop advertising
@@ -74,48 +432,64 @@ func (local *Node) Remove(key string) bool {
 // - Keep trying to republish regardless of how the last attempt went
 func (local *Node) Publish(key string) (cancel chan bool, err error) {
 	// TODO: students should implement this
-	err = local.AttemptPublish(key)
+	replica := local.storedReplica(key)
+	root, _, err := local.attemptPublish(key, replica, false, 0)
 	if err != nil {
 		return
 	}
-	cancel = make(chan bool)
-	go func() {
-		ticker := time.NewTimer(REPUBLISH)
-		for i := 0; i < RETRIES; i++ {
-			select {
-			case <-ticker.C:
-				err = local.AttemptPublish(key)
-				if err != nil {
-					return
-				}
-			case <-cancel:
-				return
-			}
-		}
-	}()
-
-	return
+	return local.republish(key, replica, root), nil
 }
 
+// AttemptPublish registers the blob stored under key with its root once, retrying up to RETRIES times.
+//
+// Deprecated: Store and Publish register the blob and keep republishing it.
 func (local *Node) AttemptPublish(key string) (err error) {
+	_, _, err = local.attemptPublish(key, local.storedReplica(key), false, 0)
+	return err
+}
+
+// The replica of the blob stored locally under key, as registered with its root
+func (local *Node) storedReplica(key string) Replica {
+	metadata, _ := local.blobstore.Stat(key)
+	return Replica{Node: local.Node, Version: metadata.Version, Expires: metadata.end(), Soft: metadata.Cached}
+}
+
+// Republish the replica, which was just registered with root, in the background until cancelled,
+// through the node's republish scheduler. Returns a channel for cancelling the publish.
+func (local *Node) republish(key string, replica Replica, root RemoteNode) (cancel chan bool) {
+	return local.republisher.add(key, replica, root)
+}
+
+// Register the replica of the key with its root, retrying up to RETRIES times (see WithRetries).
+// If conditional, the root only registers the replica if the newest version it has is expected.
+// Returns the root, and the newest version the root had registered before.
+func (local *Node) attemptPublish(key string, replica Replica, conditional bool, expected Version) (root RemoteNode, newest Version, err error) {
 	counter := 0
-	for counter < RETRIES {
-		root, err := local.FindRootOnRemoteNode(local.Node, Hash(key))
+	for counter < local.retries {
+		root, err = local.FindRootOnRemoteNode(local.Node, Hash(key))
 		if err != nil {
 			counter++
 			continue
 		}
-		isRoot, err := root.RegisterRPC(key, local.Node)
+		var isRoot, registered bool
+		if conditional {
+			isRoot, registered, newest, err = root.RegisterIfRPC(key, replica, expected)
+		} else {
+			isRoot, newest, err = root.RegisterRPC(key, replica)
+			registered = true
+		}
 		if err != nil {
 			local.RemoveBadNodes([]RemoteNode{root})
 			counter++
 		} else if !isRoot {
 			counter++
+		} else if !registered {
+			return root, newest, &VersionConflictError{Key: key, Expected: expected, Current: newest}
 		} else {
-			return nil
+			return root, newest, nil
 		}
 	}
-	return fmt.Errorf("publish %v after %v failures", key, counter)
+	return RemoteNode{}, 0, fmt.Errorf("publish %v after %v failures", key, counter)
 }
 
 // Lookup look up the Tapestry nodes that are storing the blob for the specified key.
@@ -123,31 +497,75 @@ func (local *Node) AttemptPublish(key string) (err error) {
 // - Find the root node for the key
 // - Fetch the replicas (nodes storing the blob) from the root's location map
 // - Attempt up to RETRIES times
+//
+// Replicas holding the newest version of the blob come first, nearest first.
 func (local *Node) Lookup(key string) (nodes []RemoteNode, err error) {
 	// TODO: students should implement this
+	replicas, err := local.LookupVersions(key)
+	for _, replica := range replicas {
+		nodes = append(nodes, replica.Node)
+	}
+	return nodes, err
+}
+
+// LookupVersions looks up the Tapestry nodes that are storing the blob for the specified key,
+// along with the version of the blob each holds. Replicas are ranked newest first, and of those
+// holding the same version, nearest first by measured round trip time. If the root is unreachable,
+// the surrogate root that takes over from it is asked instead.
+func (local *Node) LookupVersions(key string) (replicas []Replica, err error) {
 	root, err := local.FindRootOnRemoteNode(local.Node, Hash(key))
 	if err != nil {
-		return nodes, fmt.Errorf("find error in Lookup: %v", err)
+		return replicas, fmt.Errorf("find error in Lookup: %v", err)
 	}
 	done, replicas, err := root.FetchRPC(key)
 	if err != nil {
-		for i := 0; !done && i < RETRIES-1; i++ {
+		for i := 0; !done && i < local.retries-1; i++ {
 			done, replicas, err = root.FetchRPC(key)
 		}
 	}
+	if err != nil {
+		// The root is unreachable, so ask the surrogate root that takes over from it, which holds a
+		// mirror of its registrations
+		local.RemoveBadNodes([]RemoteNode{root})
+		if backup, findErr := local.FindRootOnRemoteNode(local.Node, Hash(key)); findErr == nil && backup != root {
+			Debug.Printf("Root %v of %v is unreachable, falling back to %v\n", root, key, backup)
+			_, replicas, err = backup.FetchRPC(key)
+		}
+	}
+	if len(replicas) > 0 {
+		local.clock.Observe(replicas[0].Version)
+	}
+	local.latencies.Rank(local.Node, replicas)
+	local.probe(replicas)
 	return replicas, err
 }
 
+// Measure the round trip time to replicas that have not been measured recently, in the background,
+// so that later lookups can rank them
+func (local *Node) probe(replicas []Replica) {
+	for _, replica := range replicas {
+		if replica.Node != local.Node {
+			local.latencies.probe(replica.Node.Address, local.Node)
+		}
+	}
+}
+
 // FindRoot returns the root for id by recursive RPC calls on the next hop found in our routing table
 // 		- find the next hop from our routing table
 // 		- call FindRoot on nextHop
 // 		- if failed, add nextHop to toRemove, remove them from local routing table, retry
 func (local *Node) FindRoot(id ID, level int32) (root RemoteNode, toRemove *NodeSet, err error) {
 	// TODO: students should implement this
+	root, toRemove, _, err = local.findRoot(id, level)
+	return root, toRemove, err
+}
+
+// Like FindRoot, also returning how many hops were taken from the local node to reach the root
+func (local *Node) findRoot(id ID, level int32) (root RemoteNode, toRemove *NodeSet, hops int, err error) {
 	toRemove = NewNodeSet()
 	for {
 		if level >= DIGITS {
-			return local.Table.local, toRemove, nil
+			return local.Table.local, toRemove, 0, nil
 		}
 		// search level
 		node := local.Table.FindNextHop(id, level)
@@ -157,7 +575,7 @@ func (local *Node) FindRoot(id ID, level int32) (root RemoteNode, toRemove *Node
 			continue
 		}
 
-		root, addToRemove, err := node.FindRootRPC(id, level+1)
+		root, addToRemove, remoteHops, err := node.findRootRPC(id, level+1)
 		if err != nil {
 			toRemove.Add(node)
 			local.Table.Remove(node)
@@ -165,32 +583,85 @@ func (local *Node) FindRoot(id ID, level int32) (root RemoteNode, toRemove *Node
 		}
 		toRemove.AddAll(addToRemove.Nodes())
 		local.RemoveBadNodes(toRemove.Nodes())
-		return root, toRemove, nil
+		return root, toRemove, remoteHops + 1, nil
 	}
 }
 
+// Route finds the root of key from the local node, and returns how many hops it took to reach it,
+// which is zero if the local node is the root
+func (local *Node) Route(key string) (root RemoteNode, hops int, err error) {
+	root, _, hops, err = local.findRoot(Hash(key), 0)
+	return root, hops, err
+}
+
 // Register The replica that stores some data with key is registering themselves to us as an advertiser of the key.
 // - Check that we are the root node for the key, set `isRoot`
 // - Add the node to the location map (local.locationsByKey.Register)
 // 		- local.locationsByKey.Register kicks off a timer to remove the node if it's not advertised again
 // 		  after TIMEOUT
-func (local *Node) Register(key string, replica RemoteNode) (isRoot bool) {
+// - Return the newest version that was registered for the key before this one
+func (local *Node) Register(key string, replica Replica) (isRoot bool, newest Version) {
 	// TODO: students should implement this
 	root, _, err := local.FindRoot(Hash(key), 0)
 	if err != nil {
 		fmt.Printf("Find error in Register: %v\n", err)
-		return isRoot
+		return isRoot, newest
+	}
+	if root == local.Node {
+		isRoot = true
+		local.promote(key)
+		newest = local.LocationsByKey.Newest(key)
+		local.LocationsByKey.Register(key, replica, local.timeout)
+		local.mirrorer.add(key, replica, false)
+		local.clock.Observe(replica.Version)
+	}
+	return isRoot, newest
+}
+
+// RegisterIf registers the replica like Register, but only if the newest version registered for the key
+// is expected. Returns whether the replica was registered, and the newest version registered before.
+func (local *Node) RegisterIf(key string, replica Replica, expected Version) (isRoot bool, registered bool, newest Version) {
+	root, _, err := local.FindRoot(Hash(key), 0)
+	if err != nil {
+		fmt.Printf("Find error in RegisterIf: %v\n", err)
+		return isRoot, registered, newest
 	}
 	if root == local.Node {
 		isRoot = true
-		local.LocationsByKey.Register(key, replica, TIMEOUT)
+		local.promote(key)
+		newest, registered = local.LocationsByKey.RegisterIf(key, replica, expected, local.timeout)
+		if registered {
+			local.mirrorer.add(key, replica, false)
+		}
+		local.clock.Observe(replica.Version)
+	}
+	return isRoot, registered, newest
+}
+
+// Unregister removes the replica from the local location map, when it stops storing the key
+func (local *Node) Unregister(key string, replica RemoteNode) bool {
+	local.BackupLocations.Unregister(key, replica)
+	if !local.LocationsByKey.Unregister(key, replica) {
+		return false
 	}
-	return isRoot
+	local.mirrorer.add(key, Replica{Node: replica}, true)
+	return true
+}
+
+// UnregisterVersion removes the replica from the local location map like Unregister, but only if the
+// version registered for its node is the replica's
+func (local *Node) UnregisterVersion(key string, replica Replica) bool {
+	local.BackupLocations.UnregisterVersion(key, replica)
+	if !local.LocationsByKey.UnregisterVersion(key, replica) {
+		return false
+	}
+	local.mirrorer.add(key, replica, true)
+	return true
 }
 
 // Fetch checks that we are the root node for the requested key and
 // return all nodes that are registered in the local location map for this key
-func (local *Node) Fetch(key string) (isRoot bool, replicas []RemoteNode) {
+func (local *Node) Fetch(key string) (isRoot bool, replicas []Replica) {
 	// TODO: students should implement this
 	root, _, err := local.FindRoot(Hash(key), 0)
 	if err != nil {
@@ -199,7 +670,8 @@ func (local *Node) Fetch(key string) (isRoot bool, replicas []RemoteNode) {
 	}
 	if root == local.Node {
 		isRoot = true
-		replicas = local.LocationsByKey.Get(key)
+		local.promote(key)
+		replicas = local.LocationsByKey.Versions(key)
 	}
 	return isRoot, replicas
 
@@ -207,10 +679,15 @@ func (local *Node) Fetch(key string) (isRoot bool, replicas []RemoteNode) {
 
 // Transfer registers all of the provided objects in the local location map. (local.locationsByKey.RegisterAll)
 // If appropriate, add the from node to our local routing table
-func (local *Node) Transfer(from RemoteNode, replicaMap map[string][]RemoteNode) (err error) {
+func (local *Node) Transfer(from RemoteNode, replicaMap map[string][]Replica) (err error) {
 	// TODO: students should implement this
 	if len(replicaMap) > 0 {
-		local.LocationsByKey.RegisterAll(replicaMap, TIMEOUT)
+		local.LocationsByKey.RegisterAll(replicaMap, local.timeout)
+		for key, replicas := range replicaMap {
+			for _, replica := range replicas {
+				local.mirrorer.add(key, replica, false)
+			}
+		}
 	}
 	err = local.AddRoute(from)
 	return err
@@ -1,16 +1,260 @@
 /*
  *  Brown University, CS138, Spring 2022
  *
- *  Purpose: Defines functions for a node leaving the Tapestry mesh, and
- *  transferring its stored locations to a new node.
+ *  Purpose: Defines functions for a node leaving the Tapestry mesh, either
+ *  at once or by draining, and transferring its stored locations to a new node.
  */
 
 package pkg
 
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+// DrainingError is returned when a blob is stored on a node that is draining
+type DrainingError struct {
+	Key string
+}
+
+func (e *DrainingError) Error() string {
+	return fmt.Sprintf("node is draining, cannot store %v", e.Key)
+}
+
+// DrainError is returned by Drain when the node stopped without handing off everything it held, or
+// without every backpointer acknowledging that it left. Unwraps to a BatchError of the keys that
+// could not be handed off, if any.
+type DrainError struct {
+	Errors   map[string]error // The error of each key whose blob or registrations could not be handed off
+	Acked    int              // How many backpointers acknowledged that the node is leaving
+	Notified int              // How many backpointers were notified
+}
+
+func (e *DrainError) Error() string {
+	var problems []string
+	if e.Acked < e.Notified {
+		problems = append(problems, fmt.Sprintf("%v of %v backpointers did not acknowledge the leave", e.Notified-e.Acked, e.Notified))
+	}
+	if len(e.Errors) > 0 {
+		problems = append(problems, batchError(e.Errors).Error())
+	}
+	return "drain incomplete: " + strings.Join(problems, "; ")
+}
+
+func (e *DrainError) Unwrap() error {
+	return batchError(e.Errors)
+}
+
+// DRAINTIMEOUT is how long Drain waits for the calls in flight on the node to complete, and then for
+// its server to stop gracefully
+const DRAINTIMEOUT = 10 * time.Second
+
+// Drain gracefully takes the node out of the tapestry, so that requests that reach it meanwhile do
+// not fail.
+//
+// - Stop accepting new blobs, whether stored on the node or cached, and stop republishing
+// - Hand off each blob to the node that becomes its root, unless that node holds a newer version
+// - Notify our backpointers that we are leaving, giving each a replacement, and wait for them to acknowledge
+// - Wait for the FindRoot and Fetch calls in flight on the node to complete
+// - Hand off the registrations of the keys we are root of to their new roots
+// - Stop the server, once the calls it is still serving complete
+//
+// The node stops even if some blobs or registrations could not be handed off, or some backpointers
+// did not acknowledge; these are returned in a DrainError.
+func (local *Node) Drain() error {
+	if !atomic.CompareAndSwapInt32(&local.draining, 0, 1) {
+		return fmt.Errorf("%v is already draining", local)
+	}
+	Out.Printf("Draining %v\n", local)
+	local.republisher.close()
+	local.reconciler.close()
+
+	errs := local.handOffBlobs()
+	acked, total := local.notifyBackpointers()
+	Out.Printf("%v of %v backpointers acknowledged that %v is leaving\n", acked, total, local)
+	if !local.waitIdle(DRAINTIMEOUT) {
+		Error.Printf("Calls were still in flight on %v after %v\n", local, DRAINTIMEOUT)
+	}
+	for key, err := range local.handOffRoots() {
+		errs[key] = err
+	}
+
+	local.mirrorer.close()
+	stopped := make(chan bool)
+	go func() {
+		local.server.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(DRAINTIMEOUT):
+		local.server.Stop()
+	}
+	local.markStopped()
+	local.blobstore.DeleteAll()
+	if local.adminServer != nil {
+		go local.adminServer.GracefulStop()
+	}
+	for _, server := range local.httpServers {
+		go server.Shutdown(context.Background())
+	}
+	Out.Printf("Drained %v\n", local)
+	if len(errs) > 0 || acked < total {
+		return &DrainError{Errors: errs, Acked: acked, Notified: total}
+	}
+	return nil
+}
+
+// Draining returns true once Drain has been called
+func (local *Node) Draining() bool {
+	return atomic.LoadInt32(&local.draining) == 1
+}
+
+// Counts a call in flight on the local node, until the returned function is called
+func (local *Node) track() func() {
+	atomic.AddInt64(&local.inflight, 1)
+	return func() {
+		atomic.AddInt64(&local.inflight, -1)
+	}
+}
+
+// Wait until no calls are in flight on the local node. Returns false if some still are after timeout.
+func (local *Node) waitIdle(timeout time.Duration) bool {
+	deadline := time.Now().Add(timeout)
+	for atomic.LoadInt64(&local.inflight) > 0 {
+		if time.Now().After(deadline) {
+			return false
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return true
+}
+
+// Store each blob on the first of its backup roots that accepts it, on the condition that the blob
+// has not been overwritten since, then unregister the local copy. The candidates are the configured
+// number of backup roots and the surrogate root after them. Cached and expired blobs are dropped.
+// Returns the error of each blob that could not be handed off.
+func (local *Node) handOffBlobs() map[string]error {
+	candidates := 1
+	if local.mirrorer.backups > 0 {
+		candidates += local.mirrorer.backups
+	}
+	errs := make(map[string]error)
+	var mutex sync.Mutex
+	blobs := local.blobstore.List()
+	forEach(len(blobs), func(i int) {
+		blob := blobs[i]
+		if blob.Metadata.Cached || blob.Metadata.Expired() {
+			return
+		}
+		value, ok := local.blobstore.Get(blob.Key)
+		if !ok {
+			return
+		}
+		opts := []StoreOption{
+			WithContentType(blob.Metadata.ContentType),
+			WithTags(blob.Metadata.Tags),
+			withExpiry(blob.Metadata.Expires),
+			ifVersion(blob.Metadata.Version),
+		}
+		err := fmt.Errorf("no node to hand off to")
+		for _, node := range local.backupRoots(blob.Key, candidates) {
+			_, err = node.TapestryStoreRPC(blob.Key, value, opts...)
+			var conflict *VersionConflictError
+			if errors.As(err, &conflict) {
+				// A newer version is stored elsewhere, so the local copy is stale
+				err = nil
+			}
+			if err == nil {
+				Debug.Printf("Handed off %v to %v\n", blob.Key, node)
+				break
+			}
+		}
+		if err != nil {
+			mutex.Lock()
+			errs[blob.Key] = err
+			mutex.Unlock()
+			return
+		}
+		local.unpublish(blob.Key)
+	})
+	return errs
+}
+
+// Register the replicas of each key the local node is root of with the key's new root. The first
+// backup root of each key finds the new root, since the local node has left its routing table.
+// Returns the error of each key that could not be handed off.
+func (local *Node) handOffRoots() map[string]error {
+	type batch struct {
+		keys     []string
+		replicas []Replica
+	}
+	errs := make(map[string]error)
+	batches := make(map[RemoteNode]*batch)
+	for _, entry := range local.LocationsByKey.Entries() {
+		candidates := local.backupRoots(entry.Key, 1)
+		if len(candidates) == 0 {
+			errs[entry.Key] = fmt.Errorf("no node to hand off to")
+			continue
+		}
+		root, _, err := candidates[0].FindRootRPC(Hash(entry.Key), 0)
+		if err != nil {
+			errs[entry.Key] = err
+			continue
+		}
+		if batches[root] == nil {
+			batches[root] = &batch{}
+		}
+		for _, replica := range entry.Replicas {
+			if replica.Node == local.Node {
+				// The local copy was not handed off, and leaves with the node
+				continue
+			}
+			batches[root].keys = append(batches[root].keys, entry.Key)
+			batches[root].replicas = append(batches[root].replicas, replica)
+		}
+	}
+
+	for root, b := range batches {
+		for start := 0; start < len(b.keys); start += BATCHSIZE {
+			end := start + BATCHSIZE
+			if end > len(b.keys) {
+				end = len(b.keys)
+			}
+			isRoot, _, err := root.RegisterManyRPC(b.keys[start:end], b.replicas[start:end])
+			for i, key := range b.keys[start:end] {
+				if err != nil {
+					errs[key] = err
+				} else if !isRoot[i] {
+					errs[key] = fmt.Errorf("%v is not the root of %v", root, key)
+				}
+			}
+		}
+		Debug.Printf("Handed off the registrations of %v keys to %v\n", len(b.keys), root)
+	}
+	return errs
+}
+
 // Kill this node without gracefully leaving the tapestry.
 func (local *Node) Kill() {
+	local.republisher.close()
+	local.mirrorer.close()
+	local.reconciler.close()
 	local.blobstore.DeleteAll()
 	local.server.Stop()
+	local.markStopped()
+	if local.adminServer != nil {
+		local.adminServer.Stop()
+	}
+	for _, server := range local.httpServers {
+		server.Close()
+	}
 }
 
 // Leave gracefully exits the Tapestry mesh.
@@ -19,15 +263,53 @@ func (local *Node) Kill() {
 // - If possible, give each backpointer a suitable alternative node from our routing table
 func (local *Node) Leave() (err error) {
 	// TODO: students should implement this
+	local.notifyBackpointers()
+
+	local.republisher.close()
+	local.mirrorer.close()
+	local.reconciler.close()
+	local.blobstore.DeleteAll()
+	go func() {
+		local.server.GracefulStop()
+		local.markStopped()
+	}()
+	if local.adminServer != nil {
+		go local.adminServer.GracefulStop()
+	}
+	for _, server := range local.httpServers {
+		go server.Shutdown(context.Background())
+	}
+	return err
+}
+
+// Stopped returns a channel that is closed once the server of the node has stopped, after Kill,
+// Leave or Drain. Leave stops the server in the background, once the calls it is serving complete.
+func (local *Node) Stopped() <-chan bool {
+	return local.stopped
+}
+
+// Mark the server of the node as stopped
+func (local *Node) markStopped() {
+	local.stopOnce.Do(func() {
+		close(local.stopped)
+	})
+}
+
+// Notify the nodes in our backpointers that we are leaving, level by level, each with a replacement
+// from our routing table if there is one. Backpointers that cannot be reached are removed. Returns
+// how many backpointers acknowledged, out of how many were notified.
+func (local *Node) notifyBackpointers() (acked int, total int) {
 	var replacement *RemoteNode
 	for i := DIGITS - 1; i >= 0; i-- {
 		backpointers := local.Backpointers.Get(i)
 		// notify backpointers
 		for _, node := range backpointers {
-
+			total++
 			err := node.NotifyLeaveRPC(local.Node, replacement)
 			if err != nil {
 				local.RemoveBadNodes([]RemoteNode{node})
+			} else {
+				acked++
 			}
 		}
 		// find replacement
@@ -38,10 +320,7 @@ func (local *Node) Leave() (err error) {
 			replacement = nil
 		}
 	}
-
-	local.blobstore.DeleteAll()
-	go local.server.GracefulStop()
-	return err
+	return acked, total
 }
 
 // NotifyLeave occurs when another node is informing us of a graceful exit.
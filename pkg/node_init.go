@@ -9,10 +9,11 @@
 package pkg
 
 import (
+	"errors"
 	"fmt"
-	"net"
-	"os"
+	"net/http"
 	"sort"
+	"sync"
 	"time"
 
 	"google.golang.org/grpc"
@@ -39,21 +40,52 @@ const REPUBLISH = 10 * time.Second
 // TIMEOUT is object timeout interval for nodes storing objects.
 const TIMEOUT = 25 * time.Second
 
+// JOINBACKOFF is the delay before the first retry of a failed join. It doubles after every failed attempt.
+const JOINBACKOFF = 500 * time.Millisecond
+
+// MAXJOINBACKOFF caps the delay between join attempts.
+const MAXJOINBACKOFF = 10 * time.Second
+
 // Node is the main struct for the local Tapestry node. Methods can be invoked locally on this struct.
 type Node struct {
 	UnsafeTapestryRPCServer
-	Node           RemoteNode    // The ID and address of this node
-	Table          *RoutingTable // The routing table
-	Backpointers   *Backpointers // Backpointers to keep track of other nodes that point to us
-	LocationsByKey *LocationMap  // Stores keys for which this node is the root
-	blobstore      *BlobStore    // Stores blobs on the local node
-	server         *grpc.Server
+	Node            RemoteNode      // The ID and address of this node
+	Table           *RoutingTable   // The routing table
+	Backpointers    *Backpointers   // Backpointers to keep track of other nodes that point to us
+	LocationsByKey  *LocationMap    // Stores keys for which this node is the root
+	BackupLocations *LocationMap    // Mirrors the registrations of keys for which this node is a backup root
+	blobstore       *BlobStore      // Stores blobs on the local node
+	clock           *Clock          // Versions the blobs stored on the local node
+	cacheLease      time.Duration   // How long fetched blobs are cached for, or zero to not cache them
+	hedge           float64         // The percentile of fetch latency after which fetches are hedged, or zero
+	retries         int             // How many times publishing and fetching are attempted, RETRIES by default
+	timeout         time.Duration   // How long registrations last without being republished, TIMEOUT by default
+	maxBodySize     int64           // The largest request body the gateways accept, MAXBODYSIZE by default
+	crawls          *crawlCache     // The nodes found by the last crawl of the mesh, for key listings
+	latencies       *LatencyTracker // The round trip times to other nodes and the latencies of fetches
+	republisher     *republisher    // Keeps the blobs stored on the local node registered with their roots
+	mirrorer        *mirrorer       // Mirrors the registrations made with this node to its backup roots
+	reconciler      *reconciler     // Moves registrations this node should not hold to their roots
+	draining        int32           // Set to 1 by Drain, after which no new blobs are accepted
+	inflight        int64           // How many FindRoot and Fetch calls are being served, for Drain to wait on
+	server          *grpc.Server
+	stopped         chan bool      // Closed once the server has stopped
+	stopOnce        sync.Once      // Closes stopped once
+	adminServer     *grpc.Server   // Serves the admin service, if enabled
+	adminAddr       string         // The address the admin service is bound to
+	httpServers     []*http.Server // Serve the optional HTTP and S3 gateways
 }
 
 func (local *Node) String() string {
 	return fmt.Sprintf("Tapestry Node %v at %v", local.Node.ID, local.Node.Address)
 }
 
+// Latencies returns the tracker of the round trip times and fetch latencies this node has measured,
+// which it ranks replicas and hedges fetches by
+func (local *Node) Latencies() *LatencyTracker {
+	return local.latencies
+}
+
 // ID returns the tapestry node's ID in string format
 func (local *Node) ID() string {
 	return local.Node.ID.String()
@@ -65,7 +97,7 @@ func (local *Node) Addr() string {
 }
 
 // Called in tapestry initialization to create a tapestry node struct
-func newTapestryNode(node RemoteNode) *Node {
+func newTapestryNode(node RemoteNode, config startConfig) *Node {
 	serverOptions := []grpc.ServerOption{}
 	n := new(Node)
 
@@ -73,56 +105,316 @@ func newTapestryNode(node RemoteNode) *Node {
 	n.Table = NewRoutingTable(node)
 	n.Backpointers = NewBackpointers(node)
 	n.LocationsByKey = NewLocationMap()
-	n.blobstore = NewBlobStore()
+	n.BackupLocations = NewLocationMap()
+	n.blobstore = NewBlobStoreWithQuota(config.maxBytes, config.maxObjects, config.eviction)
+	n.clock = NewClock()
+	n.cacheLease = config.cacheLease
+	n.hedge = config.hedge
+	n.retries = config.retries
+	if n.retries <= 0 {
+		n.retries = RETRIES
+	}
+	n.timeout = config.timeout
+	if n.timeout <= 0 {
+		n.timeout = TIMEOUT
+	}
+	n.maxBodySize = config.maxBodySize
+	if n.maxBodySize <= 0 {
+		n.maxBodySize = MAXBODYSIZE
+	}
+	n.crawls = new(crawlCache)
+	n.latencies = NewLatencyTracker()
+	n.republisher = newRepublisher(n, config.republish, config.onPublishFailure)
+	n.mirrorer = newMirrorer(n, config.backupRoots)
+	n.reconciler = newReconciler(n, config.antiEntropy)
 	n.server = grpc.NewServer(serverOptions...)
+	n.stopped = make(chan bool)
 
 	return n
 }
 
+// IDCollisionError is returned when a node attempts to join the tapestry with an ID that
+// already belongs to another node in the mesh.
+type IDCollisionError struct {
+	ID       ID         // The contested ID
+	Existing RemoteNode // The node that already holds the ID
+}
+
+func (e *IDCollisionError) Error() string {
+	return fmt.Sprintf("node ID %v is already in use by %v", e.ID, e.Existing.Address)
+}
+
+// StartOption configures optional behaviour of a node created by Start
+type StartOption func(*startConfig)
+
+type startConfig struct {
+	seeds            []SeedResolver        // Where to find existing nodes to join through
+	joinAttempts     int                   // How many times to try the whole seed list before giving up
+	joinBackoff      time.Duration         // The delay before the first retry
+	listen           string                // The address to bind to, overriding the port
+	advertise        string                // The address other nodes should use to reach us
+	http             string                // The address to serve the HTTP gateway on, if any
+	maxBodySize      int64                 // The largest request body the gateways accept, MAXBODYSIZE by default
+	s3               string                // The address to serve the S3 gateway on, if any
+	s3Bucket         string                // The name of the bucket served by the S3 gateway
+	maxBytes         int64                 // The byte quota of the blob store, or zero for none
+	maxObjects       int                   // The blob quota of the blob store, or zero for none
+	eviction         EvictionPolicy        // Picks blobs to evict when the blob store is full
+	cacheLease       time.Duration         // How long fetched blobs are cached for, or zero to not cache them
+	hedge            float64               // The percentile of fetch latency after which fetches are hedged, or zero
+	retries          int                   // How many times publishing and fetching are attempted, RETRIES by default
+	timeout          time.Duration         // How long registrations last without being republished, TIMEOUT by default
+	republish        time.Duration         // How often blobs are republished, REPUBLISH by default
+	onPublishFailure PublishFailureHandler // Called when a blob cannot be republished, if set
+	backupRoots      int                   // How many backup roots registrations are mirrored to
+	antiEntropy      time.Duration         // How often the location map is reconciled, ANTIENTROPY by default
+	admin            string                // The address to serve the admin service on, if any
+	adminToken       string                // The token admin clients must present
+	adminCert        string                // The TLS certificate file of the admin service, if it uses TLS
+	adminKey         string                // The TLS key file of the admin service
+}
+
+// WithListen sets the address to bind to, such as "0.0.0.0:4000", "[::1]:0" or
+// "unix:/var/run/tapestry.sock". When set, the port passed to Start is ignored.
+func WithListen(addr string) StartOption {
+	return func(c *startConfig) {
+		c.listen = addr
+	}
+}
+
+// WithAdvertise sets the address announced to other nodes, for nodes behind NAT or in containers
+// whose bind address is not reachable. If addr has no port, the port we are bound to is used.
+func WithAdvertise(addr string) StartOption {
+	return func(c *startConfig) {
+		c.advertise = addr
+	}
+}
+
+// WithSeeds adds a list of existing node addresses to join through. Seeds are tried in order.
+func WithSeeds(addrs ...string) StartOption {
+	return WithSeedResolver(StaticSeeds(addrs))
+}
+
+// WithSeedFile adds the addresses listed in a file to the seeds to join through
+func WithSeedFile(path string) StartOption {
+	return WithSeedResolver(SeedFile(path))
+}
+
+// WithSeedResolver adds an arbitrary source of seeds, such as SRVSeeds
+func WithSeedResolver(resolver SeedResolver) StartOption {
+	return func(c *startConfig) {
+		c.seeds = append(c.seeds, resolver)
+	}
+}
+
+// WithJoinRetry sets how many passes over the seeds are made before the join fails, and the
+// delay before the first retry. The delay doubles after each failed pass, up to MAXJOINBACKOFF.
+func WithJoinRetry(attempts int, backoff time.Duration) StartOption {
+	return func(c *startConfig) {
+		c.joinAttempts = attempts
+		c.joinBackoff = backoff
+	}
+}
+
+// WithHTTP serves the HTTP gateway (see NewHTTPHandler) on the given address once the node has started
+func WithHTTP(addr string) StartOption {
+	return func(c *startConfig) {
+		c.http = addr
+	}
+}
+
+// WithMaxBodySize sets the largest value, in bytes, that the gateways accept in a request body,
+// MAXBODYSIZE by default. Larger bodies are rejected with 413 Request Entity Too Large.
+func WithMaxBodySize(size int64) StartOption {
+	return func(c *startConfig) {
+		c.maxBodySize = size
+	}
+}
+
+// WithS3 serves an S3-compatible gateway (see S3Gateway) for the named bucket on the given address
+func WithS3(addr string, bucket string) StartOption {
+	return func(c *startConfig) {
+		c.s3 = addr
+		c.s3Bucket = bucket
+	}
+}
+
+// WithBlobQuota limits the blob store to maxBytes bytes in at most maxObjects blobs; zero means no limit.
+// When full, blobs are evicted by the policy set with WithEvictionPolicy, LRU by default.
+func WithBlobQuota(maxBytes int64, maxObjects int) StartOption {
+	return func(c *startConfig) {
+		c.maxBytes = maxBytes
+		c.maxObjects = maxObjects
+	}
+}
+
+// WithEvictionPolicy sets how the blob store makes room once it reaches its quota, such as
+// NewLFUPolicy() or RejectPolicy{} to make Store fail with a StoreFullError instead of evicting
+func WithEvictionPolicy(policy EvictionPolicy) StartOption {
+	return func(c *startConfig) {
+		c.eviction = policy
+	}
+}
+
+// WithCache makes Get keep a copy of each blob it fetches from another node for the given lease,
+// and publish it as a soft replica so that nearby nodes can fetch the blob from us. Cached copies
+// expire after the lease regardless of the primary copies, and count towards the blob quota.
+func WithCache(lease time.Duration) StartOption {
+	return func(c *startConfig) {
+		c.cacheLease = lease
+	}
+}
+
+// WithHedging makes Get send a second request to the next nearest replica when the first has
+// not answered within the given percentile of recent fetch latencies, such as 0.95, and use
+// whichever answers first. Fetches are not hedged until MINLATENCYSAMPLES have been measured.
+func WithHedging(percentile float64) StartOption {
+	return func(c *startConfig) {
+		c.hedge = percentile
+	}
+}
+
+// WithRetries sets how many times the node attempts to publish or fetch a key before giving up,
+// RETRIES by default
+func WithRetries(retries int) StartOption {
+	return func(c *startConfig) {
+		c.retries = retries
+	}
+}
+
+// WithRegistrationTimeout sets how long the node keeps the registrations it receives as root without
+// them being republished, TIMEOUT by default. It must be well over the republish interval of every
+// node in the tapestry.
+func WithRegistrationTimeout(timeout time.Duration) StartOption {
+	return func(c *startConfig) {
+		c.timeout = timeout
+	}
+}
+
+// WithRepublishInterval sets how often the blobs stored on the node are registered again with their
+// roots, REPUBLISH by default. It must be well under the registration timeout of the roots (see
+// WithRegistrationTimeout), after which they drop registrations.
+func WithRepublishInterval(interval time.Duration) StartOption {
+	return func(c *startConfig) {
+		c.republish = interval
+	}
+}
+
+// WithPublishFailureHandler sets a function to call whenever a blob stored on the node cannot be
+// registered with its root at a republish. See also PublishedKeys.
+func WithPublishFailureHandler(handler PublishFailureHandler) StartOption {
+	return func(c *startConfig) {
+		c.onPublishFailure = handler
+	}
+}
+
+// WithBackupRoots sets how many surrogate roots the node mirrors the registrations it receives as
+// root to, BACKUPROOTS by default. Zero turns mirroring off.
+func WithBackupRoots(k int) StartOption {
+	return func(c *startConfig) {
+		c.backupRoots = k
+	}
+}
+
+// WithAntiEntropyInterval sets how often the node reconciles its location map with its neighbours,
+// ANTIENTROPY by default. See Reconcile.
+func WithAntiEntropyInterval(interval time.Duration) StartOption {
+	return func(c *startConfig) {
+		c.antiEntropy = interval
+	}
+}
+
+// WithAdmin serves the admin service on addr, separately from the peer protocol. Admin clients
+// must present token, which must not be empty. Unless addr is a loopback address, the service
+// must also be given a certificate with WithAdminTLS. See DialAdmin.
+func WithAdmin(addr string, token string) StartOption {
+	return func(c *startConfig) {
+		c.admin = addr
+		c.adminToken = token
+	}
+}
+
+// WithAdminTLS serves the admin service over TLS, with the given certificate and key files
+func WithAdminTLS(certFile string, keyFile string) StartOption {
+	return func(c *startConfig) {
+		c.adminCert = certFile
+		c.adminKey = keyFile
+	}
+}
+
 // Start a node with the specified ID.
-func Start(id ID, port int, connectTo string) (tapestry *Node, err error) {
-	// Create the RPC server
-	lis, err := net.Listen("tcp", fmt.Sprintf(":%v", port))
-	if err != nil {
-		return nil, err
+//
+// If connectTo is not empty, or seeds are provided as options, the node joins an existing tapestry
+// through the first seed that responds. connectTo is tried before any other seeds.
+func Start(id ID, port int, connectTo string, opts ...StartOption) (tapestry *Node, err error) {
+	config := startConfig{joinAttempts: RETRIES, joinBackoff: JOINBACKOFF, backupRoots: BACKUPROOTS}
+	if connectTo != "" {
+		config.seeds = append(config.seeds, StaticSeeds{connectTo})
+	}
+	for _, opt := range opts {
+		opt(&config)
 	}
 
-	// Get the hostname of this machine
-	name, err := os.Hostname()
-	if err != nil {
-		return nil, fmt.Errorf("Unable to get hostname of local machine to start Tapestry node. Reason: %v", err)
+	if config.listen == "" {
+		config.listen = fmt.Sprintf(":%v", port)
 	}
 
-	// Get the port we are bound to
-	_, actualport, err := net.SplitHostPort(lis.Addr().String()) //fmt.Sprintf("%v:%v", name, port)
+	// Create the RPC server
+	lis, err := listen(config.listen)
 	if err != nil {
 		return nil, err
 	}
 
-	// The actual address of this node. NOTE: If gRPC calls fail with deadline exceeded errors, this could be that it
-	// is unable to resolve the computer's hostname to the local IP address. Try uncommenting the below line if this
-	// happens to you (please do not check this change into your Git repo).
-	// name = "127.0.0.1"
-	address := fmt.Sprintf("%s:%s", name, actualport)
+	// The actual address of this node. NOTE: If gRPC calls fail with deadline exceeded errors, this could be that
+	// other nodes are unable to resolve this computer's hostname. Use WithAdvertise to announce an IP instead.
+	address, err := advertiseAddress(lis, config.advertise)
+	if err != nil {
+		lis.Close()
+		return nil, err
+	}
 
 	// Create the local node
-	tapestry = newTapestryNode(RemoteNode{ID: id, Address: address})
+	tapestry = newTapestryNode(RemoteNode{ID: id, Address: address}, config)
 	fmt.Printf("Created tapestry node %v\n", tapestry)
 	Trace.Printf("Created tapestry node")
 
 	RegisterTapestryRPCServer(tapestry.server, tapestry)
 	fmt.Printf("Registered RPC Server\n")
 	go tapestry.server.Serve(lis)
+	go tapestry.republisher.run()
+	go tapestry.mirrorer.run()
+	go tapestry.reconciler.run()
 
-	// If specified, connect to the provided address
-	if connectTo != "" {
-		// Get the node we're joining
-		node, err := SayHelloRPC(connectTo, tapestry.Node)
+	// If specified, connect to one of the provided seeds
+	if len(config.seeds) > 0 {
+		err = tapestry.joinSeeds(config)
+		if err != nil {
+			tapestry.republisher.close()
+			tapestry.mirrorer.close()
+			tapestry.reconciler.close()
+			tapestry.server.Stop()
+			return nil, err
+		}
+	}
+
+	if config.http != "" {
+		err = tapestry.serveHTTP(config.http, "HTTP gateway", NewHTTPHandler(tapestry))
 		if err != nil {
-			return nil, fmt.Errorf("Error joining existing tapestry node %v, reason: %v", address, err)
+			tapestry.Kill()
+			return nil, err
 		}
-		err = tapestry.Join(node)
+	}
+	if config.s3 != "" {
+		err = tapestry.serveHTTP(config.s3, "S3 gateway", NewS3Gateway(tapestry, config.s3Bucket))
 		if err != nil {
+			tapestry.Kill()
+			return nil, err
+		}
+	}
+	if config.admin != "" {
+		err = tapestry.serveAdmin(config)
+		if err != nil {
+			tapestry.Kill()
 			return nil, err
 		}
 	}
@@ -130,6 +422,55 @@ func Start(id ID, port int, connectTo string) (tapestry *Node, err error) {
 	return tapestry, nil
 }
 
+// Try to join through each seed in turn, backing off between passes over the seed list.
+// Gives up early if the join is rejected because our ID is taken, since retrying cannot help.
+func (local *Node) joinSeeds(config startConfig) (err error) {
+	backoff := config.joinBackoff
+	for attempt := 0; attempt < config.joinAttempts; attempt++ {
+		if attempt > 0 {
+			Debug.Printf("Join attempt %v failed, retrying in %v\n", attempt, backoff)
+			time.Sleep(backoff)
+			backoff *= 2
+			if backoff > MAXJOINBACKOFF {
+				backoff = MAXJOINBACKOFF
+			}
+		}
+
+		seeds, resolveErr := resolveSeeds(config.seeds)
+		if resolveErr != nil {
+			err = resolveErr
+			continue
+		}
+		if len(seeds) == 0 {
+			err = fmt.Errorf("no seeds available to join through")
+			continue
+		}
+		for _, seed := range seeds {
+			if seed == local.Node.Address {
+				continue
+			}
+			// Get the node we're joining
+			node, helloErr := SayHelloRPC(seed, local.Node)
+			if helloErr != nil {
+				err = fmt.Errorf("Error joining existing tapestry node %v, reason: %v", seed, helloErr)
+				continue
+			}
+			if node == local.Node {
+				continue
+			}
+			err = local.Join(node)
+			if err == nil {
+				return nil
+			}
+			var collision *IDCollisionError
+			if errors.As(err, &collision) {
+				return err
+			}
+		}
+	}
+	return err
+}
+
 // Join is invoked when starting the local node, if we are connecting to an existing Tapestry.
 //
 // - Find the root for our node's ID
@@ -147,7 +488,7 @@ func (local *Node) Join(otherNode RemoteNode) (err error) {
 	// Add ourselves to our root by invoking AddNode on the remote node
 	neighbors, err := root.AddNodeRPC(local.Node)
 	if err != nil {
-		return fmt.Errorf("error adding ourselves to root node %v, reason: %v", root, err)
+		return fmt.Errorf("error adding ourselves to root node %v, reason: %w", root, err)
 	}
 
 	// Add the neighbors to our local routing table.
@@ -209,9 +550,14 @@ func RemoveDuplicates(input []RemoteNode) []RemoteNode {
 
 // AddNode adds node to the tapestry
 //
+// - Reject the node with an IDCollisionError if we already own its ID
 // - Begin the acknowledged multicast
 // - Return the neighborset from the multicast
 func (local *Node) AddNode(node RemoteNode) (neighborset []RemoteNode, err error) {
+	// We are the root for the new node's ID, so if another node already holds that ID it is us
+	if node.ID == local.Node.ID && node.Address != local.Node.Address {
+		return nil, &IDCollisionError{ID: node.ID, Existing: local.Node}
+	}
 	return local.AddNodeMulticast(node, SharedPrefixLength(node.ID, local.Node.ID))
 }
 
@@ -267,7 +613,7 @@ func (local *Node) TransferRelevantObjects(newNode RemoteNode) {
 		if err != nil {
 			// remove the new node, reinsert the transferred data
 			local.RemoveBadNodes([]RemoteNode{newNode})
-			local.LocationsByKey.RegisterAll(objects, TIMEOUT)
+			local.LocationsByKey.RegisterAll(objects, local.timeout)
 		}
 	}
 
@@ -0,0 +1,394 @@
+/*
+ *  Brown University, CS138, Spring 2022
+ *
+ *  Purpose: Republishes every replica the local node advertises from a single
+ *  scheduler. Due replicas are grouped by their root and registered in
+ *  batches, at times spread across the republish interval, and roots that
+ *  fail are backed off.
+ */
+
+package pkg
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+)
+
+// REPUBLISHTICKS is how many times per republish interval the scheduler looks for due replicas
+const REPUBLISHTICKS = 20
+
+// REPUBLISHJITTER is the fraction of the republish interval by which each republish is randomly moved
+const REPUBLISHJITTER = 0.1
+
+// REPUBLISHBACKOFF is how long the scheduler stops registering with a root after it first fails.
+// It doubles after every further failure, up to the republish interval.
+const REPUBLISHBACKOFF = 500 * time.Millisecond
+
+// PublishStatus describes how a key advertised by the local node is being published
+type PublishStatus struct {
+	Key            string      `json:"key"`
+	Version        Version     `json:"version"`
+	Soft           bool        `json:"soft,omitempty"`      // The local node holds a cached copy
+	Root           *RemoteNode `json:"root,omitempty"`      // The root the key was last registered with, nil if unknown
+	LastRegistered time.Time   `json:"lastRegistered"`      // When the key was last registered with its root
+	Failures       int         `json:"failures"`            // How many registrations in a row have failed
+	LastError      string      `json:"lastError,omitempty"` // Why the last registration failed, if it did
+	NextAttempt    time.Time   `json:"nextAttempt"`         // When the key will next be registered
+}
+
+func (s PublishStatus) String() string {
+	root := "unknown"
+	if s.Root != nil {
+		root = s.Root.Address
+	}
+	text := fmt.Sprintf("%v  version %v  root %v  registered %v  next %v", s.Key, s.Version, root,
+		s.LastRegistered.Format(time.RFC3339), s.NextAttempt.Format(time.RFC3339))
+	if s.Soft {
+		text += "  cached"
+	}
+	if s.Failures > 0 {
+		text += fmt.Sprintf("  %v failures: %v", s.Failures, s.LastError)
+	}
+	return text
+}
+
+// PublishFailureHandler is called when a key cannot be registered with its root at a republish.
+// It is called from the republish scheduler, so it should return quickly.
+type PublishFailureHandler func(status PublishStatus, err error)
+
+// A replica advertised by the local node
+type publication struct {
+	replica        Replica
+	cancel         chan bool  // Receives a value once the replica should no longer be advertised
+	root           RemoteNode // The root the replica was last registered with
+	rooted         bool       // Whether root is known
+	due            time.Time  // When the replica should next be registered
+	lastRegistered time.Time
+	failures       int // Consecutive failed registrations
+	lastError      error
+}
+
+// The status of a publication of key. The lock must be held.
+func (p *publication) status(key string) PublishStatus {
+	status := PublishStatus{
+		Key:            key,
+		Version:        p.replica.Version,
+		Soft:           p.replica.Soft,
+		LastRegistered: p.lastRegistered,
+		Failures:       p.failures,
+		NextAttempt:    p.due,
+	}
+	if p.rooted {
+		root := p.root
+		status.Root = &root
+	}
+	if p.lastError != nil {
+		status.LastError = p.lastError.Error()
+	}
+	return status
+}
+
+// A failed registration, reported to the failure handler once the lock is released
+type publishFailure struct {
+	status PublishStatus
+	err    error
+}
+
+// Tracks the failures of a root
+type rootBackoff struct {
+	failures int
+	until    time.Time // No registrations are sent to the root before this time
+}
+
+// republisher keeps the replicas advertised by the local node registered with their roots
+type republisher struct {
+	local        *Node
+	interval     time.Duration
+	onFailure    PublishFailureHandler // Called for each failed registration, if set
+	publications map[string]*publication
+	backoffs     map[RemoteNode]*rootBackoff
+	random       *rand.Rand
+	stop         chan bool
+	stopOnce     sync.Once
+	mutex        sync.Mutex
+}
+
+func newRepublisher(local *Node, interval time.Duration, onFailure PublishFailureHandler) *republisher {
+	if interval <= 0 {
+		interval = REPUBLISH
+	}
+	return &republisher{
+		local:        local,
+		interval:     interval,
+		onFailure:    onFailure,
+		publications: make(map[string]*publication),
+		backoffs:     make(map[RemoteNode]*rootBackoff),
+		random:       rand.New(rand.NewSource(time.Now().UnixNano())),
+		stop:         make(chan bool),
+	}
+}
+
+// Look for due replicas every tick until stopped
+func (r *republisher) run() {
+	ticker := time.NewTicker(r.interval / REPUBLISHTICKS)
+	defer ticker.Stop()
+	for {
+		select {
+		case now := <-ticker.C:
+			r.tick(now)
+		case <-r.stop:
+			return
+		}
+	}
+}
+
+// Stop republishing. The registrations of the replicas time out at their roots.
+func (r *republisher) close() {
+	r.stopOnce.Do(func() {
+		close(r.stop)
+	})
+}
+
+// Republish a replica of key that was just registered with root, replacing any replica of the key
+// already being republished. Returns a channel for cancelling the republish, which never blocks.
+// The first republish happens at a random point in the interval, to spread out keys published together.
+func (r *republisher) add(key string, replica Replica, root RemoteNode) (cancel chan bool) {
+	cancel = make(chan bool, 1)
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	now := time.Now()
+	r.publications[key] = &publication{
+		replica:        replica,
+		cancel:         cancel,
+		root:           root,
+		rooted:         true,
+		due:            now.Add(time.Duration(r.random.Int63n(int64(r.interval)))),
+		lastRegistered: now,
+	}
+	return cancel
+}
+
+// The publication of key, or nil if key is not being republished
+func (r *republisher) get(key string) *publication {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.publications[key]
+}
+
+// Put back the publication of key that add replaced, if the publication add returned cancel for is
+// still current and the one it replaced has not been cancelled. Returns whether it was put back.
+func (r *republisher) restore(key string, cancel chan bool, previous *publication) bool {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if current, exists := r.publications[key]; !exists || current.cancel != cancel {
+		return false
+	}
+	select {
+	case <-previous.cancel:
+		return false
+	default:
+	}
+	r.publications[key] = previous
+	return true
+}
+
+// The status of every key being republished, sorted by key
+func (r *republisher) statuses() []PublishStatus {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	statuses := make([]PublishStatus, 0, len(r.publications))
+	for key, p := range r.publications {
+		select {
+		case <-p.cancel:
+			// Cancelled since the last tick
+			r.remove(key, p)
+			continue
+		default:
+		}
+		statuses = append(statuses, p.status(key))
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Key < statuses[j].Key
+	})
+	return statuses
+}
+
+// Record a failed registration of key. The lock must be held.
+func (r *republisher) fail(key string, p *publication, err error, failures *[]publishFailure) {
+	p.failures++
+	p.lastError = err
+	if r.onFailure != nil {
+		*failures = append(*failures, publishFailure{p.status(key), err})
+	}
+}
+
+// Report failed registrations to the failure handler. The lock must not be held.
+func (r *republisher) report(failures []publishFailure) {
+	for _, failure := range failures {
+		r.onFailure(failure.status, failure.err)
+	}
+}
+
+// When to next republish a replica registered at now
+func (r *republisher) next(now time.Time) time.Time {
+	jitter := (2*r.random.Float64() - 1) * REPUBLISHJITTER * float64(r.interval)
+	return now.Add(r.interval + time.Duration(jitter))
+}
+
+// Remove a publication if it is still the current one for its key. The lock must be held.
+func (r *republisher) remove(key string, p *publication) {
+	if r.publications[key] == p {
+		delete(r.publications, key)
+	}
+}
+
+// Whether a root is being backed off. The lock must be held.
+func (r *republisher) backingOff(root RemoteNode, now time.Time) (bool, time.Time) {
+	backoff, exists := r.backoffs[root]
+	if !exists || !now.Before(backoff.until) {
+		return false, time.Time{}
+	}
+	return true, backoff.until
+}
+
+// Register the due replicas with their roots, one batch per root
+func (r *republisher) tick(now time.Time) {
+	byRoot := make(map[RemoteNode][]string)
+	var unrooted []string
+	due := make(map[string]*publication)
+
+	r.mutex.Lock()
+	for root, backoff := range r.backoffs {
+		if now.After(backoff.until.Add(r.interval)) {
+			delete(r.backoffs, root)
+		}
+	}
+	for key, p := range r.publications {
+		select {
+		case <-p.cancel:
+			r.remove(key, p)
+			continue
+		default:
+		}
+		if !p.replica.Expires.IsZero() && !now.Before(p.replica.Expires) {
+			r.remove(key, p)
+			continue
+		}
+		if now.Before(p.due) {
+			continue
+		}
+		if p.rooted {
+			if waiting, until := r.backingOff(p.root, now); waiting {
+				p.due = until
+				continue
+			}
+			byRoot[p.root] = append(byRoot[p.root], key)
+		} else {
+			unrooted = append(unrooted, key)
+		}
+		due[key] = p
+	}
+	r.mutex.Unlock()
+	if len(due) == 0 {
+		return
+	}
+
+	// Find the roots of replicas whose root is unknown
+	batches, unrouted := r.local.groupByRoot(unrooted)
+	for root, keys := range byRoot {
+		for _, chunk := range chunks(keys) {
+			batches = append(batches, rootBatch{root, chunk})
+		}
+	}
+	var failures []publishFailure
+	r.mutex.Lock()
+	for _, key := range unrouted {
+		due[key].due = now.Add(REPUBLISHBACKOFF)
+		r.fail(key, due[key], fmt.Errorf("unable to find the root of %v", key), &failures)
+	}
+	r.mutex.Unlock()
+	r.report(failures)
+
+	forEach(len(batches), func(i int) {
+		r.register(batches[i], due, now)
+	})
+}
+
+// Register a batch of due replicas with their root, and reschedule them
+func (r *republisher) register(batch rootBatch, due map[string]*publication, now time.Time) {
+	r.mutex.Lock()
+	waiting, until := r.backingOff(batch.root, now)
+	if waiting {
+		for _, key := range batch.keys {
+			due[key].due = until
+		}
+	}
+	replicas := make([]Replica, len(batch.keys))
+	for i, key := range batch.keys {
+		replicas[i] = due[key].replica
+	}
+	r.mutex.Unlock()
+	if waiting {
+		return
+	}
+
+	// As with Publish, a republish only fails once the root has failed RETRIES times in a row
+	var isRoot []bool
+	var err error
+	for attempt := 0; attempt < r.local.retries; attempt++ {
+		if isRoot, _, err = batch.root.RegisterManyRPC(batch.keys, replicas); err == nil {
+			break
+		}
+	}
+
+	var failures []publishFailure
+	defer func() {
+		r.report(failures)
+	}()
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if err != nil {
+		backoff, exists := r.backoffs[batch.root]
+		if !exists {
+			backoff = &rootBackoff{}
+			r.backoffs[batch.root] = backoff
+		}
+		backoff.failures++
+		delay := REPUBLISHBACKOFF << uint(backoff.failures-1)
+		if delay > r.interval || delay <= 0 {
+			delay = r.interval
+		}
+		backoff.until = now.Add(delay)
+		Debug.Printf("Unable to republish %v keys to %v, backing off for %v: %v\n", len(batch.keys), batch.root, delay, err)
+
+		// Find the root again once the backoff is over, in case it has changed
+		for _, key := range batch.keys {
+			due[key].rooted = false
+			due[key].due = backoff.until
+			r.fail(key, due[key], err, &failures)
+		}
+		return
+	}
+
+	delete(r.backoffs, batch.root)
+	for i, key := range batch.keys {
+		p := due[key]
+		if isRoot[i] {
+			p.root, p.rooted = batch.root, true
+			p.due = r.next(now)
+			p.lastRegistered = now
+			p.failures, p.lastError = 0, nil
+		} else {
+			// The root has changed, so find it again at the next tick
+			p.rooted = false
+		}
+	}
+}
+
+// PublishedKeys returns the status of every key the local node is publishing, sorted by key
+func (local *Node) PublishedKeys() []PublishStatus {
+	return local.republisher.statuses()
+}
@@ -22,6 +22,13 @@ type RoutingTable struct {
 	mutex sync.Mutex                 // To manage concurrent access to the routing table (could also have a per-level mutex)
 }
 
+// RoutingEntry is a single node in a slot of the routing table
+type RoutingEntry struct {
+	Level int        `json:"level"`
+	Digit Digit      `json:"digit"`
+	Node  RemoteNode `json:"node"`
+}
+
 // NewRoutingTable creates and returns a new routing table, placing the local node at the
 // appropriate slot in each level of the table.
 func NewRoutingTable(me RemoteNode) *RoutingTable {
@@ -158,8 +165,8 @@ func (t *RoutingTable) GetLevel(level int) (nodes []RemoteNode) {
 // FindClosestNode find the closest node in a slot compared with id
 func FindClosestNode(id ID, slot []RemoteNode) *RemoteNode {
 	result := &slot[0]
-	for i, node := range slot {
-		if id.Closer(node.ID, (*result).ID) {
+	for i := 1; i < len(slot); i++ {
+		if id.Closer(slot[i].ID, (*result).ID) {
 			result = &slot[i]
 		}
 	}
@@ -185,7 +192,7 @@ func (t *RoutingTable) FindNextHop(id ID, level int32) RemoteNode {
 			// we already have node in this slot
 			if len(slot) != 0 {
 				candidate := FindClosestNode(id, slot)
-				if candidate.ID.String() != t.local.ID.String() {
+				if candidate.ID != t.local.ID {
 					return *candidate
 				} else {
 					break
@@ -197,3 +204,20 @@ func (t *RoutingTable) FindNextHop(id ID, level int32) RemoteNode {
 	}
 	return t.local
 }
+
+// Entries returns every node in the routing table, including the local node, ordered by level and slot.
+func (t *RoutingTable) Entries() (entries []RoutingEntry) {
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	entries = make([]RoutingEntry, 0)
+	for i, row := range t.Rows {
+		for j, slot := range row {
+			for _, node := range slot {
+				entries = append(entries, RoutingEntry{Level: i, Digit: Digit(j), Node: node})
+			}
+		}
+	}
+	return
+}
@@ -0,0 +1,338 @@
+/*
+ *  Brown University, CS138, Spring 2022
+ *
+ *  Purpose: Exposes a Tapestry node as a single bucket of an S3-compatible
+ *  object store, so existing S3 clients can read and write objects.
+ */
+
+package pkg
+
+import (
+	"bytes"
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// S3MAXKEYS is the default and maximum number of keys returned by one ListObjectsV2 call
+const S3MAXKEYS = 1000
+
+// s3TimeFormat is the timestamp format used in S3 XML responses
+const s3TimeFormat = "2006-01-02T15:04:05.000Z"
+
+// s3MetaPrefix is the prefix of headers carrying user-defined object metadata
+const s3MetaPrefix = "x-amz-meta-"
+
+// S3Gateway serves PutObject, GetObject, HeadObject, DeleteObject and ListObjectsV2 for a single
+// bucket, mapping object keys directly onto Tapestry keys. Only path-style requests
+// (http://host/bucket/key) are supported, and request signatures are not checked.
+//
+// Listing returns the keys advertised anywhere in the tapestry (see ListKeys), however they were
+// stored. Objects written through the gateway are also recorded in its key index, which keeps their
+// MD5 ETags; other objects are given their quoted version as ETag.
+type S3Gateway struct {
+	local  *Node
+	bucket string
+	index  *KeyIndex
+}
+
+// NewS3Gateway returns a handler serving the given bucket from the local node
+func NewS3Gateway(local *Node, bucket string) *S3Gateway {
+	return &S3Gateway{local: local, bucket: bucket, index: NewKeyIndex()}
+}
+
+// Index returns the key index of the objects written through the gateway
+func (s3 *S3Gateway) Index() *KeyIndex {
+	return s3.index
+}
+
+func (s3 *S3Gateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/")
+	if path == "" {
+		s3.listBuckets(w, r)
+		return
+	}
+
+	bucket, key := path, ""
+	if i := strings.Index(path, "/"); i >= 0 {
+		bucket, key = path[:i], path[i+1:]
+	}
+	if bucket != s3.bucket {
+		writeS3Error(w, http.StatusNotFound, "NoSuchBucket", "The specified bucket does not exist", bucket)
+		return
+	}
+
+	switch {
+	case key == "" && r.Method == http.MethodGet:
+		s3.listObjects(w, r)
+	case key == "" && r.Method == http.MethodHead:
+		w.WriteHeader(http.StatusOK)
+	case key == "":
+		writeS3Error(w, http.StatusMethodNotAllowed, "MethodNotAllowed", "The specified method is not allowed against this resource", bucket)
+	case r.Method == http.MethodPut:
+		s3.putObject(w, r, key)
+	case r.Method == http.MethodGet, r.Method == http.MethodHead:
+		s3.getObject(w, r, key)
+	case r.Method == http.MethodDelete:
+		s3.deleteObject(w, r, key)
+	default:
+		writeS3Error(w, http.StatusMethodNotAllowed, "MethodNotAllowed", "The specified method is not allowed against this resource", key)
+	}
+}
+
+func (s3 *S3Gateway) putObject(w http.ResponseWriter, r *http.Request, key string) {
+	value, err := s3.local.readBody(w, r)
+	if err != nil {
+		if bodyStatus(err) == http.StatusRequestEntityTooLarge {
+			writeS3Error(w, http.StatusRequestEntityTooLarge, "EntityTooLarge", err.Error(), key)
+		} else {
+			writeS3Error(w, http.StatusBadRequest, "IncompleteBody", err.Error(), key)
+		}
+		return
+	}
+	// User-defined metadata is kept as tags on the blob
+	tags := make(map[string]string)
+	for header, values := range r.Header {
+		if name := strings.ToLower(header); strings.HasPrefix(name, s3MetaPrefix) {
+			tags[strings.TrimPrefix(name, s3MetaPrefix)] = values[0]
+		}
+	}
+	err = s3.local.Store(key, value, WithContentType(r.Header.Get("Content-Type")), WithTags(tags))
+	if err != nil {
+		writeS3Error(w, http.StatusServiceUnavailable, "ServiceUnavailable", err.Error(), key)
+		return
+	}
+
+	entry := IndexEntry{Key: key, Size: len(value), ETag: etag(value), Modified: time.Now().UTC()}
+	s3.index.Put(entry)
+	w.Header().Set("ETag", entry.ETag)
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s3 *S3Gateway) getObject(w http.ResponseWriter, r *http.Request, key string) {
+	var value []byte
+	var metadata Metadata
+	var err error
+	if r.Method == http.MethodHead {
+		metadata, err = s3.local.Stat(key)
+	} else {
+		value, metadata, err = s3.local.get(key)
+		metadata.Size = len(value)
+	}
+	if err != nil {
+		var notFound *NotFoundError
+		if errors.As(err, &notFound) {
+			writeS3Error(w, http.StatusNotFound, "NoSuchKey", "The specified key does not exist.", key)
+		} else {
+			writeS3Error(w, http.StatusServiceUnavailable, "ServiceUnavailable", err.Error(), key)
+		}
+		return
+	}
+
+	writeMetadataHeaders(w, metadata)
+	for tag, tagValue := range metadata.Tags {
+		w.Header().Set(s3MetaPrefix+tag, tagValue)
+	}
+	if entry, exists := s3.index.Get(key); exists {
+		w.Header().Set("ETag", entry.ETag)
+	} else if value != nil {
+		w.Header().Set("ETag", etag(value))
+	} else {
+		w.Header().Set("ETag", versionETag(metadata.Version))
+	}
+	w.WriteHeader(http.StatusOK)
+	if r.Method == http.MethodGet {
+		io.Copy(w, bytes.NewReader(value))
+	}
+}
+
+func (s3 *S3Gateway) deleteObject(w http.ResponseWriter, r *http.Request, key string) {
+	// S3 deletes succeed whether or not the key existed
+	s3.local.Remove(key)
+	s3.index.Delete(key)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+type s3Bucket struct {
+	Name         string
+	CreationDate string
+}
+
+type s3ListBucketsResult struct {
+	XMLName xml.Name   `xml:"http://s3.amazonaws.com/doc/2006-03-01/ ListAllMyBucketsResult"`
+	Owner   string     `xml:"Owner>ID"`
+	Buckets []s3Bucket `xml:"Buckets>Bucket"`
+}
+
+func (s3 *S3Gateway) listBuckets(w http.ResponseWriter, r *http.Request) {
+	writeXML(w, http.StatusOK, s3ListBucketsResult{
+		Owner:   s3.local.ID(),
+		Buckets: []s3Bucket{{Name: s3.bucket, CreationDate: time.Unix(0, 0).UTC().Format(s3TimeFormat)}},
+	})
+}
+
+type s3Object struct {
+	Key          string
+	LastModified string
+	ETag         string
+	Size         int
+	StorageClass string
+}
+
+type s3CommonPrefix struct {
+	Prefix string
+}
+
+type s3ListObjectsResult struct {
+	XMLName               xml.Name         `xml:"http://s3.amazonaws.com/doc/2006-03-01/ ListBucketResult"`
+	Name                  string           `xml:"Name"`
+	Prefix                string           `xml:"Prefix"`
+	Delimiter             string           `xml:"Delimiter,omitempty"`
+	StartAfter            string           `xml:"StartAfter,omitempty"`
+	ContinuationToken     string           `xml:"ContinuationToken,omitempty"`
+	NextContinuationToken string           `xml:"NextContinuationToken,omitempty"`
+	KeyCount              int              `xml:"KeyCount"`
+	MaxKeys               int              `xml:"MaxKeys"`
+	IsTruncated           bool             `xml:"IsTruncated"`
+	Contents              []s3Object       `xml:"Contents"`
+	CommonPrefixes        []s3CommonPrefix `xml:"CommonPrefixes"`
+}
+
+// ListObjectsV2. Keys sharing a prefix up to the delimiter are rolled up into common prefixes,
+// and the continuation token is the last key or common prefix returned.
+func (s3 *S3Gateway) listObjects(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	if query.Get("list-type") != "2" {
+		writeS3Error(w, http.StatusNotImplemented, "NotImplemented", "Only ListObjectsV2 is supported", s3.bucket)
+		return
+	}
+
+	result := s3ListObjectsResult{
+		Name:              s3.bucket,
+		Prefix:            query.Get("prefix"),
+		Delimiter:         query.Get("delimiter"),
+		StartAfter:        query.Get("start-after"),
+		ContinuationToken: query.Get("continuation-token"),
+		MaxKeys:           S3MAXKEYS,
+	}
+	if maxKeys := query.Get("max-keys"); maxKeys != "" {
+		max, err := strconv.Atoi(maxKeys)
+		if err != nil || max < 0 {
+			writeS3Error(w, http.StatusBadRequest, "InvalidArgument", "max-keys must be a non-negative integer", s3.bucket)
+			return
+		}
+		if max < S3MAXKEYS {
+			result.MaxKeys = max
+		}
+	}
+
+	after := result.StartAfter
+	if result.ContinuationToken != "" {
+		token, err := base64.StdEncoding.DecodeString(result.ContinuationToken)
+		if err != nil {
+			writeS3Error(w, http.StatusBadRequest, "InvalidArgument", "The continuation token provided is incorrect", s3.bucket)
+			return
+		}
+		after = string(token)
+	}
+
+	// Keys come from the whole tapestry, a page at a time, until the result is full. A result that
+	// holds no keys is never truncated, as it would have no key to continue after.
+	last := ""
+	token := after
+	for result.MaxKeys > 0 && !result.IsTruncated {
+		keys, next, err := s3.local.ListKeys(result.Prefix, token)
+		if err != nil {
+			writeS3Error(w, http.StatusServiceUnavailable, "ServiceUnavailable", err.Error(), s3.bucket)
+			return
+		}
+		for _, key := range keys {
+			commonPrefix := ""
+			if result.Delimiter != "" {
+				rest := strings.TrimPrefix(key, result.Prefix)
+				if i := strings.Index(rest, result.Delimiter); i >= 0 {
+					commonPrefix = result.Prefix + rest[:i+len(result.Delimiter)]
+				}
+			}
+			// Keys under a common prefix that has already been returned are skipped
+			if commonPrefix != "" && (commonPrefix == last || strings.HasPrefix(after, commonPrefix)) {
+				continue
+			}
+			if result.KeyCount == result.MaxKeys {
+				result.IsTruncated = true
+				result.NextContinuationToken = base64.StdEncoding.EncodeToString([]byte(last))
+				break
+			}
+
+			if commonPrefix != "" {
+				result.CommonPrefixes = append(result.CommonPrefixes, s3CommonPrefix{Prefix: commonPrefix})
+				last = commonPrefix
+			} else {
+				result.Contents = append(result.Contents, s3Object{Key: key, StorageClass: "STANDARD"})
+				last = key
+			}
+			result.KeyCount++
+		}
+		if next == "" {
+			break
+		}
+		token = next
+	}
+	s3.describe(result.Contents)
+
+	writeXML(w, http.StatusOK, result)
+}
+
+// Fill in the size, modification time and ETag of listed objects, from the key index for objects
+// written through the gateway, and otherwise from their metadata
+func (s3 *S3Gateway) describe(objects []s3Object) {
+	forEach(len(objects), func(i int) {
+		object := &objects[i]
+		if entry, exists := s3.index.Get(object.Key); exists {
+			object.Size = entry.Size
+			object.LastModified = entry.Modified.Format(s3TimeFormat)
+			object.ETag = entry.ETag
+			return
+		}
+		metadata, err := s3.local.Stat(object.Key)
+		if err != nil {
+			Debug.Printf("Unable to describe %v for a listing: %v\n", object.Key, err)
+			return
+		}
+		object.Size = metadata.Size
+		object.LastModified = metadata.Modified.UTC().Format(s3TimeFormat)
+		object.ETag = versionETag(metadata.Version)
+	})
+}
+
+type s3Error struct {
+	XMLName  xml.Name `xml:"Error"`
+	Code     string   `xml:"Code"`
+	Message  string   `xml:"Message"`
+	Resource string   `xml:"Resource"`
+}
+
+func writeS3Error(w http.ResponseWriter, status int, code string, message string, resource string) {
+	writeXML(w, status, s3Error{Code: code, Message: message, Resource: resource})
+}
+
+func writeXML(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(status)
+	io.WriteString(w, xml.Header)
+	xml.NewEncoder(w).Encode(body)
+}
+
+// S3 ETags of single-part uploads are the quoted hex MD5 of the object
+func etag(value []byte) string {
+	sum := md5.Sum(value)
+	return fmt.Sprintf("\"%v\"", hex.EncodeToString(sum[:]))
+}
@@ -0,0 +1,106 @@
+/*
+ *  Brown University, CS138, Spring 2022
+ *
+ *  Purpose: Defines the sources of bootstrap seeds that a new node can use to
+ *  join an existing Tapestry mesh.
+ */
+
+package pkg
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/context"
+)
+
+// SeedResolver produces the addresses of existing nodes that a new node can join through.
+// Seeds are resolved again on every join attempt, so changes made between attempts are picked up.
+type SeedResolver interface {
+	Seeds() ([]string, error)
+}
+
+// StaticSeeds is a fixed list of seed addresses
+type StaticSeeds []string
+
+// Seeds returns the list of addresses, trimmed of spaces, skipping blank ones
+func (s StaticSeeds) Seeds() ([]string, error) {
+	seeds := make([]string, 0, len(s))
+	for _, seed := range s {
+		if seed = strings.TrimSpace(seed); seed != "" {
+			seeds = append(seeds, seed)
+		}
+	}
+	return seeds, nil
+}
+
+// SeedFile is the path of a file listing one seed address per line.
+// Blank lines and lines starting with # are ignored.
+type SeedFile string
+
+// Seeds reads the seed addresses from the file
+func (f SeedFile) Seeds() ([]string, error) {
+	file, err := os.Open(string(f))
+	if err != nil {
+		return nil, fmt.Errorf("unable to read seed file %v: %v", f, err)
+	}
+	defer file.Close()
+
+	seeds := make([]string, 0)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line != "" && !strings.HasPrefix(line, "#") {
+			seeds = append(seeds, line)
+		}
+	}
+	return seeds, scanner.Err()
+}
+
+// SRVSeeds resolves seed addresses from DNS SRV records, ordered by priority and weight.
+// If Service and Proto are empty, Name is looked up directly, e.g. "_tapestry._tcp.example.com".
+type SRVSeeds struct {
+	Service  string
+	Proto    string
+	Name     string
+	Resolver *net.Resolver // The resolver to use; nil uses the system resolver. Set Dial to query a local stub.
+}
+
+// Seeds looks up the SRV records and returns their targets as host:port addresses
+func (s SRVSeeds) Seeds() ([]string, error) {
+	resolver := s.Resolver
+	if resolver == nil {
+		resolver = net.DefaultResolver
+	}
+	_, records, err := resolver.LookupSRV(context.Background(), s.Service, s.Proto, s.Name)
+	if err != nil {
+		return nil, fmt.Errorf("unable to resolve seeds from SRV record %v: %v", s.Name, err)
+	}
+	seeds := make([]string, 0, len(records))
+	for _, record := range records {
+		host := strings.TrimSuffix(record.Target, ".")
+		seeds = append(seeds, net.JoinHostPort(host, strconv.Itoa(int(record.Port))))
+	}
+	return seeds, nil
+}
+
+// Resolve all of the seed sources in order, skipping sources that fail as long as one succeeds
+func resolveSeeds(resolvers []SeedResolver) (seeds []string, err error) {
+	var errs []error
+	for _, resolver := range resolvers {
+		addrs, err := resolver.Seeds()
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		seeds = append(seeds, addrs...)
+	}
+	if len(seeds) == 0 && len(errs) > 0 {
+		return nil, fmt.Errorf("no seeds could be resolved: %v", errs)
+	}
+	return seeds, nil
+}
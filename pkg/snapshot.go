@@ -0,0 +1,140 @@
+/*
+ *  Brown University, CS138, Spring 2022
+ *
+ *  Purpose: Takes machine-readable snapshots of the state of a node, and
+ *  crawls the mesh to build a graph of every node and routing link, which
+ *  can be written as JSON or as a Graphviz DOT graph.
+ */
+
+package pkg
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+)
+
+// NodeState is a snapshot of the routing state of a node. It encodes to JSON as is.
+type NodeState struct {
+	Node            RemoteNode         `json:"node"`
+	RoutingTable    []RoutingEntry     `json:"routingTable"`
+	Backpointers    []BackpointerEntry `json:"backpointers"`
+	Locations       []LocationEntry    `json:"locations,omitempty"`       // The keys the node is root of
+	BackupLocations []LocationEntry    `json:"backupLocations,omitempty"` // The keys the node is a backup root of
+}
+
+// Snapshot returns the routing table and backpointers of the local node, along with its location
+// maps if locations is true. Everything is sorted, so that snapshots can be compared over time.
+func (local *Node) Snapshot(locations bool) NodeState {
+	state := NodeState{
+		Node:         local.Node,
+		RoutingTable: local.Table.Entries(),
+		Backpointers: local.Backpointers.Entries(),
+	}
+	if locations {
+		state.Locations = local.LocationsByKey.Entries()
+		state.BackupLocations = local.BackupLocations.Entries()
+	}
+	return state
+}
+
+// Topology is a graph of the nodes of a mesh, linked by their routing tables
+type Topology struct {
+	Nodes       []NodeState    `json:"nodes"`
+	Links       []TopologyLink `json:"links"`
+	Unreachable []RemoteNode   `json:"unreachable,omitempty"` // Nodes that were linked to but did not answer
+}
+
+// TopologyLink is an entry for another node in the routing table of a node
+type TopologyLink struct {
+	From  ID    `json:"from"`
+	To    ID    `json:"to"`
+	Level int   `json:"level"`
+	Digit Digit `json:"digit"`
+}
+
+// CrawlTopology fetches the state of every node reachable from the node at addr, following the
+// routing tables and backpointers of the nodes it reaches. Location maps are included if locations
+// is true. Nodes and links are sorted by ID.
+func CrawlTopology(addr string, locations bool) (topology Topology, err error) {
+	start, err := SayHelloRPC(addr, RemoteNode{})
+	if err != nil {
+		return topology, err
+	}
+	seen := map[RemoteNode]bool{start: true}
+	queue := []RemoteNode{start}
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+		state, err := node.GetStateRPC(locations)
+		if err != nil {
+			Debug.Printf("Unable to get the state of %v: %v\n", node, err)
+			topology.Unreachable = append(topology.Unreachable, node)
+			continue
+		}
+		topology.Nodes = append(topology.Nodes, state)
+
+		neighbors := make([]RemoteNode, 0, len(state.RoutingTable)+len(state.Backpointers))
+		for _, entry := range state.RoutingTable {
+			if entry.Node != state.Node {
+				topology.Links = append(topology.Links, TopologyLink{state.Node.ID, entry.Node.ID, entry.Level, entry.Digit})
+			}
+			neighbors = append(neighbors, entry.Node)
+		}
+		for _, entry := range state.Backpointers {
+			neighbors = append(neighbors, entry.Node)
+		}
+		for _, neighbor := range neighbors {
+			if !seen[neighbor] {
+				seen[neighbor] = true
+				queue = append(queue, neighbor)
+			}
+		}
+	}
+
+	sort.Slice(topology.Nodes, func(i, j int) bool {
+		return topology.Nodes[i].Node.ID.String() < topology.Nodes[j].Node.ID.String()
+	})
+	sort.Slice(topology.Unreachable, func(i, j int) bool {
+		return topology.Unreachable[i].ID.String() < topology.Unreachable[j].ID.String()
+	})
+	sort.Slice(topology.Links, func(i, j int) bool {
+		first, second := topology.Links[i], topology.Links[j]
+		if first.From != second.From {
+			return first.From.String() < second.From.String()
+		}
+		if first.Level != second.Level {
+			return first.Level < second.Level
+		}
+		if first.Digit != second.Digit {
+			return first.Digit < second.Digit
+		}
+		return first.To.String() < second.To.String()
+	})
+	return topology, nil
+}
+
+// DOT writes the topology as a Graphviz graph. Each node is labelled with the start of its ID and
+// its address, and each link with the level and digit of its routing table slot. Unreachable nodes
+// are dashed.
+func (topology Topology) DOT() string {
+	var buffer bytes.Buffer
+	fmt.Fprintf(&buffer, "digraph tapestry {\n")
+	fmt.Fprintf(&buffer, "  node [shape=box];\n")
+	for _, state := range topology.Nodes {
+		fmt.Fprintf(&buffer, "  %q [label=%q];\n", state.Node.ID.String(), dotLabel(state.Node))
+	}
+	for _, node := range topology.Unreachable {
+		fmt.Fprintf(&buffer, "  %q [label=%q, style=dashed];\n", node.ID.String(), dotLabel(node))
+	}
+	for _, link := range topology.Links {
+		fmt.Fprintf(&buffer, "  %q -> %q [label=\"%v:%v\"];\n", link.From.String(), link.To.String(), link.Level, link.Digit)
+	}
+	fmt.Fprintf(&buffer, "}\n")
+	return buffer.String()
+}
+
+// The label of a node in a DOT graph
+func dotLabel(node RemoteNode) string {
+	return fmt.Sprintf("%v\n%v", node.ID.String()[:8], node.Address)
+}
@@ -134,8 +134,11 @@ type DataBlob struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Data []byte `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
-	Key  string `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	Data        []byte       `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	Key         string       `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	Metadata    *MetadataMsg `protobuf:"bytes,3,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	Conditional bool         `protobuf:"varint,4,opt,name=conditional,proto3" json:"conditional,omitempty"` // Only store if the newest version of the key is expected
+	Expected    uint64       `protobuf:"varint,5,opt,name=expected,proto3" json:"expected,omitempty"`
 }
 
 func (x *DataBlob) Reset() {
@@ -184,16 +187,41 @@ func (x *DataBlob) GetKey() string {
 	return ""
 }
 
-type Key struct {
+func (x *DataBlob) GetMetadata() *MetadataMsg {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *DataBlob) GetConditional() bool {
+	if x != nil {
+		return x.Conditional
+	}
+	return false
+}
+
+func (x *DataBlob) GetExpected() uint64 {
+	if x != nil {
+		return x.Expected
+	}
+	return 0
+}
+
+type StoreReply struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Key string `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Version  uint64 `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`   // The version of the stored blob
+	Conflict bool   `protobuf:"varint,2,opt,name=conflict,proto3" json:"conflict,omitempty"` // A conditional store found another version
+	Current  uint64 `protobuf:"varint,3,opt,name=current,proto3" json:"current,omitempty"`   // The newest version found on a conflict
+	Full     bool   `protobuf:"varint,4,opt,name=full,proto3" json:"full,omitempty"`         // The blob store was full, in replies to a batch
+	Error    string `protobuf:"bytes,5,opt,name=error,proto3" json:"error,omitempty"`        // Any other failure, in replies to a batch
 }
 
-func (x *Key) Reset() {
-	*x = Key{}
+func (x *StoreReply) Reset() {
+	*x = StoreReply{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_pkg_tapestry_rpc_proto_msgTypes[3]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -201,13 +229,13 @@ func (x *Key) Reset() {
 	}
 }
 
-func (x *Key) String() string {
+func (x *StoreReply) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Key) ProtoMessage() {}
+func (*StoreReply) ProtoMessage() {}
 
-func (x *Key) ProtoReflect() protoreflect.Message {
+func (x *StoreReply) ProtoReflect() protoreflect.Message {
 	mi := &file_pkg_tapestry_rpc_proto_msgTypes[3]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -219,29 +247,56 @@ func (x *Key) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Key.ProtoReflect.Descriptor instead.
-func (*Key) Descriptor() ([]byte, []int) {
+// Deprecated: Use StoreReply.ProtoReflect.Descriptor instead.
+func (*StoreReply) Descriptor() ([]byte, []int) {
 	return file_pkg_tapestry_rpc_proto_rawDescGZIP(), []int{3}
 }
 
-func (x *Key) GetKey() string {
+func (x *StoreReply) GetVersion() uint64 {
 	if x != nil {
-		return x.Key
+		return x.Version
+	}
+	return 0
+}
+
+func (x *StoreReply) GetConflict() bool {
+	if x != nil {
+		return x.Conflict
+	}
+	return false
+}
+
+func (x *StoreReply) GetCurrent() uint64 {
+	if x != nil {
+		return x.Current
+	}
+	return 0
+}
+
+func (x *StoreReply) GetFull() bool {
+	if x != nil {
+		return x.Full
+	}
+	return false
+}
+
+func (x *StoreReply) GetError() string {
+	if x != nil {
+		return x.Error
 	}
 	return ""
 }
 
-type NodeMsg struct {
+type DataBlobs struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Address string `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
-	Id      string `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	Blobs []*DataBlob `protobuf:"bytes,1,rep,name=blobs,proto3" json:"blobs,omitempty"`
 }
 
-func (x *NodeMsg) Reset() {
-	*x = NodeMsg{}
+func (x *DataBlobs) Reset() {
+	*x = DataBlobs{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_pkg_tapestry_rpc_proto_msgTypes[4]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -249,13 +304,13 @@ func (x *NodeMsg) Reset() {
 	}
 }
 
-func (x *NodeMsg) String() string {
+func (x *DataBlobs) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*NodeMsg) ProtoMessage() {}
+func (*DataBlobs) ProtoMessage() {}
 
-func (x *NodeMsg) ProtoReflect() protoreflect.Message {
+func (x *DataBlobs) ProtoReflect() protoreflect.Message {
 	mi := &file_pkg_tapestry_rpc_proto_msgTypes[4]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -267,36 +322,28 @@ func (x *NodeMsg) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use NodeMsg.ProtoReflect.Descriptor instead.
-func (*NodeMsg) Descriptor() ([]byte, []int) {
+// Deprecated: Use DataBlobs.ProtoReflect.Descriptor instead.
+func (*DataBlobs) Descriptor() ([]byte, []int) {
 	return file_pkg_tapestry_rpc_proto_rawDescGZIP(), []int{4}
 }
 
-func (x *NodeMsg) GetAddress() string {
-	if x != nil {
-		return x.Address
-	}
-	return ""
-}
-
-func (x *NodeMsg) GetId() string {
+func (x *DataBlobs) GetBlobs() []*DataBlob {
 	if x != nil {
-		return x.Id
+		return x.Blobs
 	}
-	return ""
+	return nil
 }
 
-type RootMsg struct {
+type StoreReplies struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Next     *NodeMsg   `protobuf:"bytes,1,opt,name=next,proto3" json:"next,omitempty"`
-	ToRemove []*NodeMsg `protobuf:"bytes,2,rep,name=toRemove,proto3" json:"toRemove,omitempty"`
+	Replies []*StoreReply `protobuf:"bytes,1,rep,name=replies,proto3" json:"replies,omitempty"` // In the order of the blobs
 }
 
-func (x *RootMsg) Reset() {
-	*x = RootMsg{}
+func (x *StoreReplies) Reset() {
+	*x = StoreReplies{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_pkg_tapestry_rpc_proto_msgTypes[5]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -304,13 +351,13 @@ func (x *RootMsg) Reset() {
 	}
 }
 
-func (x *RootMsg) String() string {
+func (x *StoreReplies) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RootMsg) ProtoMessage() {}
+func (*StoreReplies) ProtoMessage() {}
 
-func (x *RootMsg) ProtoReflect() protoreflect.Message {
+func (x *StoreReplies) ProtoReflect() protoreflect.Message {
 	mi := &file_pkg_tapestry_rpc_proto_msgTypes[5]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -322,36 +369,36 @@ func (x *RootMsg) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RootMsg.ProtoReflect.Descriptor instead.
-func (*RootMsg) Descriptor() ([]byte, []int) {
+// Deprecated: Use StoreReplies.ProtoReflect.Descriptor instead.
+func (*StoreReplies) Descriptor() ([]byte, []int) {
 	return file_pkg_tapestry_rpc_proto_rawDescGZIP(), []int{5}
 }
 
-func (x *RootMsg) GetNext() *NodeMsg {
-	if x != nil {
-		return x.Next
-	}
-	return nil
-}
-
-func (x *RootMsg) GetToRemove() []*NodeMsg {
+func (x *StoreReplies) GetReplies() []*StoreReply {
 	if x != nil {
-		return x.ToRemove
+		return x.Replies
 	}
 	return nil
 }
 
-type Registration struct {
+type MetadataMsg struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	FromNode *NodeMsg `protobuf:"bytes,1,opt,name=fromNode,proto3" json:"fromNode,omitempty"`
-	Key      string   `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	Size        int64             `protobuf:"varint,1,opt,name=size,proto3" json:"size,omitempty"`
+	ContentType string            `protobuf:"bytes,2,opt,name=contentType,proto3" json:"contentType,omitempty"`
+	Created     int64             `protobuf:"varint,3,opt,name=created,proto3" json:"created,omitempty"`   // Unix time in nanoseconds
+	Modified    int64             `protobuf:"varint,4,opt,name=modified,proto3" json:"modified,omitempty"` // Unix time in nanoseconds
+	Tags        map[string]string `protobuf:"bytes,5,rep,name=tags,proto3" json:"tags,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Version     uint64            `protobuf:"varint,6,opt,name=version,proto3" json:"version,omitempty"`
+	Expires     int64             `protobuf:"varint,7,opt,name=expires,proto3" json:"expires,omitempty"` // Unix time in nanoseconds, or zero if the blob does not expire
+	Cached      bool              `protobuf:"varint,8,opt,name=cached,proto3" json:"cached,omitempty"`   // The blob is a cached copy of a blob stored elsewhere
+	Lease       int64             `protobuf:"varint,9,opt,name=lease,proto3" json:"lease,omitempty"`     // Unix time in nanoseconds when a cached copy is dropped, or zero
 }
 
-func (x *Registration) Reset() {
-	*x = Registration{}
+func (x *MetadataMsg) Reset() {
+	*x = MetadataMsg{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_pkg_tapestry_rpc_proto_msgTypes[6]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -359,13 +406,13 @@ func (x *Registration) Reset() {
 	}
 }
 
-func (x *Registration) String() string {
+func (x *MetadataMsg) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Registration) ProtoMessage() {}
+func (*MetadataMsg) ProtoMessage() {}
 
-func (x *Registration) ProtoReflect() protoreflect.Message {
+func (x *MetadataMsg) ProtoReflect() protoreflect.Message {
 	mi := &file_pkg_tapestry_rpc_proto_msgTypes[6]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -377,36 +424,84 @@ func (x *Registration) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Registration.ProtoReflect.Descriptor instead.
-func (*Registration) Descriptor() ([]byte, []int) {
+// Deprecated: Use MetadataMsg.ProtoReflect.Descriptor instead.
+func (*MetadataMsg) Descriptor() ([]byte, []int) {
 	return file_pkg_tapestry_rpc_proto_rawDescGZIP(), []int{6}
 }
 
-func (x *Registration) GetFromNode() *NodeMsg {
+func (x *MetadataMsg) GetSize() int64 {
 	if x != nil {
-		return x.FromNode
+		return x.Size
 	}
-	return nil
+	return 0
 }
 
-func (x *Registration) GetKey() string {
+func (x *MetadataMsg) GetContentType() string {
 	if x != nil {
-		return x.Key
+		return x.ContentType
 	}
 	return ""
 }
 
-type FetchedLocations struct {
+func (x *MetadataMsg) GetCreated() int64 {
+	if x != nil {
+		return x.Created
+	}
+	return 0
+}
+
+func (x *MetadataMsg) GetModified() int64 {
+	if x != nil {
+		return x.Modified
+	}
+	return 0
+}
+
+func (x *MetadataMsg) GetTags() map[string]string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *MetadataMsg) GetVersion() uint64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *MetadataMsg) GetExpires() int64 {
+	if x != nil {
+		return x.Expires
+	}
+	return 0
+}
+
+func (x *MetadataMsg) GetCached() bool {
+	if x != nil {
+		return x.Cached
+	}
+	return false
+}
+
+func (x *MetadataMsg) GetLease() int64 {
+	if x != nil {
+		return x.Lease
+	}
+	return 0
+}
+
+type Key struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	IsRoot bool       `protobuf:"varint,1,opt,name=isRoot,proto3" json:"isRoot,omitempty"`
-	Values []*NodeMsg `protobuf:"bytes,3,rep,name=values,proto3" json:"values,omitempty"`
+	Key string `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
 }
 
-func (x *FetchedLocations) Reset() {
-	*x = FetchedLocations{}
+func (x *Key) Reset() {
+	*x = Key{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_pkg_tapestry_rpc_proto_msgTypes[7]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -414,13 +509,13 @@ func (x *FetchedLocations) Reset() {
 	}
 }
 
-func (x *FetchedLocations) String() string {
+func (x *Key) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*FetchedLocations) ProtoMessage() {}
+func (*Key) ProtoMessage() {}
 
-func (x *FetchedLocations) ProtoReflect() protoreflect.Message {
+func (x *Key) ProtoReflect() protoreflect.Message {
 	mi := &file_pkg_tapestry_rpc_proto_msgTypes[7]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -432,35 +527,28 @@ func (x *FetchedLocations) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use FetchedLocations.ProtoReflect.Descriptor instead.
-func (*FetchedLocations) Descriptor() ([]byte, []int) {
+// Deprecated: Use Key.ProtoReflect.Descriptor instead.
+func (*Key) Descriptor() ([]byte, []int) {
 	return file_pkg_tapestry_rpc_proto_rawDescGZIP(), []int{7}
 }
 
-func (x *FetchedLocations) GetIsRoot() bool {
-	if x != nil {
-		return x.IsRoot
-	}
-	return false
-}
-
-func (x *FetchedLocations) GetValues() []*NodeMsg {
+func (x *Key) GetKey() string {
 	if x != nil {
-		return x.Values
+		return x.Key
 	}
-	return nil
+	return ""
 }
 
-type Neighbors struct {
+type Keys struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Neighbors []*NodeMsg `protobuf:"bytes,1,rep,name=neighbors,proto3" json:"neighbors,omitempty"`
+	Keys []string `protobuf:"bytes,1,rep,name=keys,proto3" json:"keys,omitempty"`
 }
 
-func (x *Neighbors) Reset() {
-	*x = Neighbors{}
+func (x *Keys) Reset() {
+	*x = Keys{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_pkg_tapestry_rpc_proto_msgTypes[8]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -468,13 +556,13 @@ func (x *Neighbors) Reset() {
 	}
 }
 
-func (x *Neighbors) String() string {
+func (x *Keys) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Neighbors) ProtoMessage() {}
+func (*Keys) ProtoMessage() {}
 
-func (x *Neighbors) ProtoReflect() protoreflect.Message {
+func (x *Keys) ProtoReflect() protoreflect.Message {
 	mi := &file_pkg_tapestry_rpc_proto_msgTypes[8]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -486,29 +574,29 @@ func (x *Neighbors) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Neighbors.ProtoReflect.Descriptor instead.
-func (*Neighbors) Descriptor() ([]byte, []int) {
+// Deprecated: Use Keys.ProtoReflect.Descriptor instead.
+func (*Keys) Descriptor() ([]byte, []int) {
 	return file_pkg_tapestry_rpc_proto_rawDescGZIP(), []int{8}
 }
 
-func (x *Neighbors) GetNeighbors() []*NodeMsg {
+func (x *Keys) GetKeys() []string {
 	if x != nil {
-		return x.Neighbors
+		return x.Keys
 	}
 	return nil
 }
 
-type MulticastRequest struct {
+type NodeMsg struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	NewNode *NodeMsg `protobuf:"bytes,1,opt,name=newNode,proto3" json:"newNode,omitempty"`
-	Level   int32    `protobuf:"varint,2,opt,name=level,proto3" json:"level,omitempty"`
+	Address string `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	Id      string `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
 }
 
-func (x *MulticastRequest) Reset() {
-	*x = MulticastRequest{}
+func (x *NodeMsg) Reset() {
+	*x = NodeMsg{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_pkg_tapestry_rpc_proto_msgTypes[9]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -516,13 +604,13 @@ func (x *MulticastRequest) Reset() {
 	}
 }
 
-func (x *MulticastRequest) String() string {
+func (x *NodeMsg) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*MulticastRequest) ProtoMessage() {}
+func (*NodeMsg) ProtoMessage() {}
 
-func (x *MulticastRequest) ProtoReflect() protoreflect.Message {
+func (x *NodeMsg) ProtoReflect() protoreflect.Message {
 	mi := &file_pkg_tapestry_rpc_proto_msgTypes[9]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -534,36 +622,37 @@ func (x *MulticastRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use MulticastRequest.ProtoReflect.Descriptor instead.
-func (*MulticastRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use NodeMsg.ProtoReflect.Descriptor instead.
+func (*NodeMsg) Descriptor() ([]byte, []int) {
 	return file_pkg_tapestry_rpc_proto_rawDescGZIP(), []int{9}
 }
 
-func (x *MulticastRequest) GetNewNode() *NodeMsg {
+func (x *NodeMsg) GetAddress() string {
 	if x != nil {
-		return x.NewNode
+		return x.Address
 	}
-	return nil
+	return ""
 }
 
-func (x *MulticastRequest) GetLevel() int32 {
+func (x *NodeMsg) GetId() string {
 	if x != nil {
-		return x.Level
+		return x.Id
 	}
-	return 0
+	return ""
 }
 
-type TransferData struct {
+type RootMsg struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	From *NodeMsg              `protobuf:"bytes,1,opt,name=from,proto3" json:"from,omitempty"`
-	Data map[string]*Neighbors `protobuf:"bytes,2,rep,name=data,proto3" json:"data,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Next     *NodeMsg   `protobuf:"bytes,1,opt,name=next,proto3" json:"next,omitempty"`
+	ToRemove []*NodeMsg `protobuf:"bytes,2,rep,name=toRemove,proto3" json:"toRemove,omitempty"`
+	Hops     int32      `protobuf:"varint,3,opt,name=hops,proto3" json:"hops,omitempty"`
 }
 
-func (x *TransferData) Reset() {
-	*x = TransferData{}
+func (x *RootMsg) Reset() {
+	*x = RootMsg{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_pkg_tapestry_rpc_proto_msgTypes[10]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -571,13 +660,13 @@ func (x *TransferData) Reset() {
 	}
 }
 
-func (x *TransferData) String() string {
+func (x *RootMsg) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*TransferData) ProtoMessage() {}
+func (*RootMsg) ProtoMessage() {}
 
-func (x *TransferData) ProtoReflect() protoreflect.Message {
+func (x *RootMsg) ProtoReflect() protoreflect.Message {
 	mi := &file_pkg_tapestry_rpc_proto_msgTypes[10]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -589,36 +678,48 @@ func (x *TransferData) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use TransferData.ProtoReflect.Descriptor instead.
-func (*TransferData) Descriptor() ([]byte, []int) {
+// Deprecated: Use RootMsg.ProtoReflect.Descriptor instead.
+func (*RootMsg) Descriptor() ([]byte, []int) {
 	return file_pkg_tapestry_rpc_proto_rawDescGZIP(), []int{10}
 }
 
-func (x *TransferData) GetFrom() *NodeMsg {
+func (x *RootMsg) GetNext() *NodeMsg {
 	if x != nil {
-		return x.From
+		return x.Next
 	}
 	return nil
 }
 
-func (x *TransferData) GetData() map[string]*Neighbors {
+func (x *RootMsg) GetToRemove() []*NodeMsg {
 	if x != nil {
-		return x.Data
+		return x.ToRemove
 	}
 	return nil
 }
 
-type BackpointerRequest struct {
+func (x *RootMsg) GetHops() int32 {
+	if x != nil {
+		return x.Hops
+	}
+	return 0
+}
+
+type Registration struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	From  *NodeMsg `protobuf:"bytes,1,opt,name=from,proto3" json:"from,omitempty"`
-	Level int32    `protobuf:"varint,2,opt,name=level,proto3" json:"level,omitempty"`
+	FromNode    *NodeMsg `protobuf:"bytes,1,opt,name=fromNode,proto3" json:"fromNode,omitempty"`
+	Key         string   `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	Version     uint64   `protobuf:"varint,3,opt,name=version,proto3" json:"version,omitempty"`
+	Conditional bool     `protobuf:"varint,4,opt,name=conditional,proto3" json:"conditional,omitempty"` // Only register if the newest registered version is expected
+	Expected    uint64   `protobuf:"varint,5,opt,name=expected,proto3" json:"expected,omitempty"`
+	Expires     int64    `protobuf:"varint,6,opt,name=expires,proto3" json:"expires,omitempty"` // Unix time in nanoseconds, or zero if the blob does not expire
+	Soft        bool     `protobuf:"varint,7,opt,name=soft,proto3" json:"soft,omitempty"`       // The replica is a cached copy
 }
 
-func (x *BackpointerRequest) Reset() {
-	*x = BackpointerRequest{}
+func (x *Registration) Reset() {
+	*x = Registration{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_pkg_tapestry_rpc_proto_msgTypes[11]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -626,13 +727,13 @@ func (x *BackpointerRequest) Reset() {
 	}
 }
 
-func (x *BackpointerRequest) String() string {
+func (x *Registration) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*BackpointerRequest) ProtoMessage() {}
+func (*Registration) ProtoMessage() {}
 
-func (x *BackpointerRequest) ProtoReflect() protoreflect.Message {
+func (x *Registration) ProtoReflect() protoreflect.Message {
 	mi := &file_pkg_tapestry_rpc_proto_msgTypes[11]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -644,51 +745,1905 @@ func (x *BackpointerRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use BackpointerRequest.ProtoReflect.Descriptor instead.
-func (*BackpointerRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use Registration.ProtoReflect.Descriptor instead.
+func (*Registration) Descriptor() ([]byte, []int) {
 	return file_pkg_tapestry_rpc_proto_rawDescGZIP(), []int{11}
 }
 
-func (x *BackpointerRequest) GetFrom() *NodeMsg {
+func (x *Registration) GetFromNode() *NodeMsg {
+	if x != nil {
+		return x.FromNode
+	}
+	return nil
+}
+
+func (x *Registration) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *Registration) GetVersion() uint64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *Registration) GetConditional() bool {
+	if x != nil {
+		return x.Conditional
+	}
+	return false
+}
+
+func (x *Registration) GetExpected() uint64 {
+	if x != nil {
+		return x.Expected
+	}
+	return 0
+}
+
+func (x *Registration) GetExpires() int64 {
+	if x != nil {
+		return x.Expires
+	}
+	return 0
+}
+
+func (x *Registration) GetSoft() bool {
+	if x != nil {
+		return x.Soft
+	}
+	return false
+}
+
+type RegistrationReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	IsRoot     bool   `protobuf:"varint,1,opt,name=isRoot,proto3" json:"isRoot,omitempty"`
+	Registered bool   `protobuf:"varint,2,opt,name=registered,proto3" json:"registered,omitempty"` // False if a conditional registration found another version
+	Newest     uint64 `protobuf:"varint,3,opt,name=newest,proto3" json:"newest,omitempty"`         // The newest version registered before this registration
+}
+
+func (x *RegistrationReply) Reset() {
+	*x = RegistrationReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_tapestry_rpc_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RegistrationReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegistrationReply) ProtoMessage() {}
+
+func (x *RegistrationReply) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_tapestry_rpc_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegistrationReply.ProtoReflect.Descriptor instead.
+func (*RegistrationReply) Descriptor() ([]byte, []int) {
+	return file_pkg_tapestry_rpc_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *RegistrationReply) GetIsRoot() bool {
+	if x != nil {
+		return x.IsRoot
+	}
+	return false
+}
+
+func (x *RegistrationReply) GetRegistered() bool {
+	if x != nil {
+		return x.Registered
+	}
+	return false
+}
+
+func (x *RegistrationReply) GetNewest() uint64 {
+	if x != nil {
+		return x.Newest
+	}
+	return 0
+}
+
+type Registrations struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Registrations []*Registration `protobuf:"bytes,1,rep,name=registrations,proto3" json:"registrations,omitempty"`
+}
+
+func (x *Registrations) Reset() {
+	*x = Registrations{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_tapestry_rpc_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Registrations) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Registrations) ProtoMessage() {}
+
+func (x *Registrations) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_tapestry_rpc_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Registrations.ProtoReflect.Descriptor instead.
+func (*Registrations) Descriptor() ([]byte, []int) {
+	return file_pkg_tapestry_rpc_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *Registrations) GetRegistrations() []*Registration {
+	if x != nil {
+		return x.Registrations
+	}
+	return nil
+}
+
+type RegistrationReplies struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Replies []*RegistrationReply `protobuf:"bytes,1,rep,name=replies,proto3" json:"replies,omitempty"` // In the order of the registrations
+}
+
+func (x *RegistrationReplies) Reset() {
+	*x = RegistrationReplies{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_tapestry_rpc_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RegistrationReplies) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegistrationReplies) ProtoMessage() {}
+
+func (x *RegistrationReplies) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_tapestry_rpc_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegistrationReplies.ProtoReflect.Descriptor instead.
+func (*RegistrationReplies) Descriptor() ([]byte, []int) {
+	return file_pkg_tapestry_rpc_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *RegistrationReplies) GetReplies() []*RegistrationReply {
+	if x != nil {
+		return x.Replies
+	}
+	return nil
+}
+
+type ReplicaMsg struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Node    *NodeMsg `protobuf:"bytes,1,opt,name=node,proto3" json:"node,omitempty"`
+	Version uint64   `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`
+	Expires int64    `protobuf:"varint,3,opt,name=expires,proto3" json:"expires,omitempty"` // Unix time in nanoseconds, or zero if the blob does not expire
+	Soft    bool     `protobuf:"varint,4,opt,name=soft,proto3" json:"soft,omitempty"`       // The replica is a cached copy
+}
+
+func (x *ReplicaMsg) Reset() {
+	*x = ReplicaMsg{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_tapestry_rpc_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ReplicaMsg) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReplicaMsg) ProtoMessage() {}
+
+func (x *ReplicaMsg) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_tapestry_rpc_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReplicaMsg.ProtoReflect.Descriptor instead.
+func (*ReplicaMsg) Descriptor() ([]byte, []int) {
+	return file_pkg_tapestry_rpc_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *ReplicaMsg) GetNode() *NodeMsg {
+	if x != nil {
+		return x.Node
+	}
+	return nil
+}
+
+func (x *ReplicaMsg) GetVersion() uint64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *ReplicaMsg) GetExpires() int64 {
+	if x != nil {
+		return x.Expires
+	}
+	return 0
+}
+
+func (x *ReplicaMsg) GetSoft() bool {
+	if x != nil {
+		return x.Soft
+	}
+	return false
+}
+
+type Replicas struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Replicas []*ReplicaMsg `protobuf:"bytes,1,rep,name=replicas,proto3" json:"replicas,omitempty"`
+}
+
+func (x *Replicas) Reset() {
+	*x = Replicas{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_tapestry_rpc_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Replicas) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Replicas) ProtoMessage() {}
+
+func (x *Replicas) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_tapestry_rpc_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Replicas.ProtoReflect.Descriptor instead.
+func (*Replicas) Descriptor() ([]byte, []int) {
+	return file_pkg_tapestry_rpc_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *Replicas) GetReplicas() []*ReplicaMsg {
+	if x != nil {
+		return x.Replicas
+	}
+	return nil
+}
+
+type ReplicasByKey struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Replicas map[string]*Replicas `protobuf:"bytes,1,rep,name=replicas,proto3" json:"replicas,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Errors   map[string]string    `protobuf:"bytes,2,rep,name=errors,proto3" json:"errors,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"` // Keys that could not be looked up
+}
+
+func (x *ReplicasByKey) Reset() {
+	*x = ReplicasByKey{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_tapestry_rpc_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ReplicasByKey) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReplicasByKey) ProtoMessage() {}
+
+func (x *ReplicasByKey) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_tapestry_rpc_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReplicasByKey.ProtoReflect.Descriptor instead.
+func (*ReplicasByKey) Descriptor() ([]byte, []int) {
+	return file_pkg_tapestry_rpc_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *ReplicasByKey) GetReplicas() map[string]*Replicas {
+	if x != nil {
+		return x.Replicas
+	}
+	return nil
+}
+
+func (x *ReplicasByKey) GetErrors() map[string]string {
+	if x != nil {
+		return x.Errors
+	}
+	return nil
+}
+
+type FetchedLocations struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	IsRoot   bool          `protobuf:"varint,1,opt,name=isRoot,proto3" json:"isRoot,omitempty"`
+	Replicas []*ReplicaMsg `protobuf:"bytes,4,rep,name=replicas,proto3" json:"replicas,omitempty"`
+}
+
+func (x *FetchedLocations) Reset() {
+	*x = FetchedLocations{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_tapestry_rpc_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FetchedLocations) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FetchedLocations) ProtoMessage() {}
+
+func (x *FetchedLocations) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_tapestry_rpc_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FetchedLocations.ProtoReflect.Descriptor instead.
+func (*FetchedLocations) Descriptor() ([]byte, []int) {
+	return file_pkg_tapestry_rpc_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *FetchedLocations) GetIsRoot() bool {
+	if x != nil {
+		return x.IsRoot
+	}
+	return false
+}
+
+func (x *FetchedLocations) GetReplicas() []*ReplicaMsg {
+	if x != nil {
+		return x.Replicas
+	}
+	return nil
+}
+
+type FetchedLocationsList struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Locations []*FetchedLocations `protobuf:"bytes,1,rep,name=locations,proto3" json:"locations,omitempty"` // In the order of the keys
+}
+
+func (x *FetchedLocationsList) Reset() {
+	*x = FetchedLocationsList{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_tapestry_rpc_proto_msgTypes[19]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FetchedLocationsList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FetchedLocationsList) ProtoMessage() {}
+
+func (x *FetchedLocationsList) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_tapestry_rpc_proto_msgTypes[19]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FetchedLocationsList.ProtoReflect.Descriptor instead.
+func (*FetchedLocationsList) Descriptor() ([]byte, []int) {
+	return file_pkg_tapestry_rpc_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *FetchedLocationsList) GetLocations() []*FetchedLocations {
+	if x != nil {
+		return x.Locations
+	}
+	return nil
+}
+
+type Neighbors struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Neighbors []*NodeMsg `protobuf:"bytes,1,rep,name=neighbors,proto3" json:"neighbors,omitempty"`
+}
+
+func (x *Neighbors) Reset() {
+	*x = Neighbors{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_tapestry_rpc_proto_msgTypes[20]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Neighbors) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Neighbors) ProtoMessage() {}
+
+func (x *Neighbors) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_tapestry_rpc_proto_msgTypes[20]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Neighbors.ProtoReflect.Descriptor instead.
+func (*Neighbors) Descriptor() ([]byte, []int) {
+	return file_pkg_tapestry_rpc_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *Neighbors) GetNeighbors() []*NodeMsg {
+	if x != nil {
+		return x.Neighbors
+	}
+	return nil
+}
+
+type MulticastRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	NewNode *NodeMsg `protobuf:"bytes,1,opt,name=newNode,proto3" json:"newNode,omitempty"`
+	Level   int32    `protobuf:"varint,2,opt,name=level,proto3" json:"level,omitempty"`
+}
+
+func (x *MulticastRequest) Reset() {
+	*x = MulticastRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_tapestry_rpc_proto_msgTypes[21]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MulticastRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MulticastRequest) ProtoMessage() {}
+
+func (x *MulticastRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_tapestry_rpc_proto_msgTypes[21]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MulticastRequest.ProtoReflect.Descriptor instead.
+func (*MulticastRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_tapestry_rpc_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *MulticastRequest) GetNewNode() *NodeMsg {
+	if x != nil {
+		return x.NewNode
+	}
+	return nil
+}
+
+func (x *MulticastRequest) GetLevel() int32 {
+	if x != nil {
+		return x.Level
+	}
+	return 0
+}
+
+type TransferData struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	From *NodeMsg             `protobuf:"bytes,1,opt,name=from,proto3" json:"from,omitempty"`
+	Data map[string]*Replicas `protobuf:"bytes,2,rep,name=data,proto3" json:"data,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+func (x *TransferData) Reset() {
+	*x = TransferData{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_tapestry_rpc_proto_msgTypes[22]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TransferData) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TransferData) ProtoMessage() {}
+
+func (x *TransferData) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_tapestry_rpc_proto_msgTypes[22]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TransferData.ProtoReflect.Descriptor instead.
+func (*TransferData) Descriptor() ([]byte, []int) {
+	return file_pkg_tapestry_rpc_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *TransferData) GetFrom() *NodeMsg {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *TransferData) GetData() map[string]*Replicas {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+type MirrorEntry struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Key     string      `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Replica *ReplicaMsg `protobuf:"bytes,2,opt,name=replica,proto3" json:"replica,omitempty"`
+	Removed bool        `protobuf:"varint,3,opt,name=removed,proto3" json:"removed,omitempty"` // The replica was unregistered rather than registered
+}
+
+func (x *MirrorEntry) Reset() {
+	*x = MirrorEntry{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_tapestry_rpc_proto_msgTypes[23]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MirrorEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MirrorEntry) ProtoMessage() {}
+
+func (x *MirrorEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_tapestry_rpc_proto_msgTypes[23]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MirrorEntry.ProtoReflect.Descriptor instead.
+func (*MirrorEntry) Descriptor() ([]byte, []int) {
+	return file_pkg_tapestry_rpc_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *MirrorEntry) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *MirrorEntry) GetReplica() *ReplicaMsg {
+	if x != nil {
+		return x.Replica
+	}
+	return nil
+}
+
+func (x *MirrorEntry) GetRemoved() bool {
+	if x != nil {
+		return x.Removed
+	}
+	return false
+}
+
+type MirrorData struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	From    *NodeMsg       `protobuf:"bytes,1,opt,name=from,proto3" json:"from,omitempty"` // The root the registrations were made with
+	Entries []*MirrorEntry `protobuf:"bytes,2,rep,name=entries,proto3" json:"entries,omitempty"`
+}
+
+func (x *MirrorData) Reset() {
+	*x = MirrorData{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_tapestry_rpc_proto_msgTypes[24]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MirrorData) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MirrorData) ProtoMessage() {}
+
+func (x *MirrorData) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_tapestry_rpc_proto_msgTypes[24]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MirrorData.ProtoReflect.Descriptor instead.
+func (*MirrorData) Descriptor() ([]byte, []int) {
+	return file_pkg_tapestry_rpc_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *MirrorData) GetFrom() *NodeMsg {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *MirrorData) GetEntries() []*MirrorEntry {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+type SummaryRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Prefix string `protobuf:"bytes,1,opt,name=prefix,proto3" json:"prefix,omitempty"` // A prefix of key hashes, as hex digits
+}
+
+func (x *SummaryRequest) Reset() {
+	*x = SummaryRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_tapestry_rpc_proto_msgTypes[25]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SummaryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SummaryRequest) ProtoMessage() {}
+
+func (x *SummaryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_tapestry_rpc_proto_msgTypes[25]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SummaryRequest.ProtoReflect.Descriptor instead.
+func (*SummaryRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_tapestry_rpc_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *SummaryRequest) GetPrefix() string {
+	if x != nil {
+		return x.Prefix
+	}
+	return ""
+}
+
+type Summary struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Digests [][]byte `protobuf:"bytes,1,rep,name=digests,proto3" json:"digests,omitempty"` // The digest of the registrations under the prefix followed by each digit
+}
+
+func (x *Summary) Reset() {
+	*x = Summary{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_tapestry_rpc_proto_msgTypes[26]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Summary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Summary) ProtoMessage() {}
+
+func (x *Summary) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_tapestry_rpc_proto_msgTypes[26]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Summary.ProtoReflect.Descriptor instead.
+func (*Summary) Descriptor() ([]byte, []int) {
+	return file_pkg_tapestry_rpc_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *Summary) GetDigests() [][]byte {
+	if x != nil {
+		return x.Digests
+	}
+	return nil
+}
+
+type BackpointerRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	From  *NodeMsg `protobuf:"bytes,1,opt,name=from,proto3" json:"from,omitempty"`
+	Level int32    `protobuf:"varint,2,opt,name=level,proto3" json:"level,omitempty"`
+}
+
+func (x *BackpointerRequest) Reset() {
+	*x = BackpointerRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_tapestry_rpc_proto_msgTypes[27]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BackpointerRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BackpointerRequest) ProtoMessage() {}
+
+func (x *BackpointerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_tapestry_rpc_proto_msgTypes[27]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BackpointerRequest.ProtoReflect.Descriptor instead.
+func (*BackpointerRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_tapestry_rpc_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *BackpointerRequest) GetFrom() *NodeMsg {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *BackpointerRequest) GetLevel() int32 {
+	if x != nil {
+		return x.Level
+	}
+	return 0
+}
+
+type LeaveNotification struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	From        *NodeMsg `protobuf:"bytes,1,opt,name=from,proto3" json:"from,omitempty"`
+	Replacement *NodeMsg `protobuf:"bytes,2,opt,name=replacement,proto3" json:"replacement,omitempty"`
+}
+
+func (x *LeaveNotification) Reset() {
+	*x = LeaveNotification{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_tapestry_rpc_proto_msgTypes[28]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LeaveNotification) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LeaveNotification) ProtoMessage() {}
+
+func (x *LeaveNotification) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_tapestry_rpc_proto_msgTypes[28]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LeaveNotification.ProtoReflect.Descriptor instead.
+func (*LeaveNotification) Descriptor() ([]byte, []int) {
+	return file_pkg_tapestry_rpc_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *LeaveNotification) GetFrom() *NodeMsg {
 	if x != nil {
 		return x.From
 	}
 	return nil
 }
 
-func (x *BackpointerRequest) GetLevel() int32 {
+func (x *LeaveNotification) GetReplacement() *NodeMsg {
+	if x != nil {
+		return x.Replacement
+	}
+	return nil
+}
+
+type RoutingEntryMsg struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Level int32    `protobuf:"varint,1,opt,name=level,proto3" json:"level,omitempty"`
+	Digit int32    `protobuf:"varint,2,opt,name=digit,proto3" json:"digit,omitempty"`
+	Node  *NodeMsg `protobuf:"bytes,3,opt,name=node,proto3" json:"node,omitempty"`
+}
+
+func (x *RoutingEntryMsg) Reset() {
+	*x = RoutingEntryMsg{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_tapestry_rpc_proto_msgTypes[29]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RoutingEntryMsg) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RoutingEntryMsg) ProtoMessage() {}
+
+func (x *RoutingEntryMsg) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_tapestry_rpc_proto_msgTypes[29]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RoutingEntryMsg.ProtoReflect.Descriptor instead.
+func (*RoutingEntryMsg) Descriptor() ([]byte, []int) {
+	return file_pkg_tapestry_rpc_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *RoutingEntryMsg) GetLevel() int32 {
+	if x != nil {
+		return x.Level
+	}
+	return 0
+}
+
+func (x *RoutingEntryMsg) GetDigit() int32 {
+	if x != nil {
+		return x.Digit
+	}
+	return 0
+}
+
+func (x *RoutingEntryMsg) GetNode() *NodeMsg {
+	if x != nil {
+		return x.Node
+	}
+	return nil
+}
+
+type RoutingTableMsg struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Node    *NodeMsg           `protobuf:"bytes,1,opt,name=node,proto3" json:"node,omitempty"`
+	Entries []*RoutingEntryMsg `protobuf:"bytes,2,rep,name=entries,proto3" json:"entries,omitempty"`
+}
+
+func (x *RoutingTableMsg) Reset() {
+	*x = RoutingTableMsg{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_tapestry_rpc_proto_msgTypes[30]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RoutingTableMsg) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RoutingTableMsg) ProtoMessage() {}
+
+func (x *RoutingTableMsg) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_tapestry_rpc_proto_msgTypes[30]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RoutingTableMsg.ProtoReflect.Descriptor instead.
+func (*RoutingTableMsg) Descriptor() ([]byte, []int) {
+	return file_pkg_tapestry_rpc_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *RoutingTableMsg) GetNode() *NodeMsg {
+	if x != nil {
+		return x.Node
+	}
+	return nil
+}
+
+func (x *RoutingTableMsg) GetEntries() []*RoutingEntryMsg {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+type StateRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Locations bool `protobuf:"varint,1,opt,name=locations,proto3" json:"locations,omitempty"` // Include the location maps
+}
+
+func (x *StateRequest) Reset() {
+	*x = StateRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_tapestry_rpc_proto_msgTypes[31]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StateRequest) ProtoMessage() {}
+
+func (x *StateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_tapestry_rpc_proto_msgTypes[31]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StateRequest.ProtoReflect.Descriptor instead.
+func (*StateRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_tapestry_rpc_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *StateRequest) GetLocations() bool {
+	if x != nil {
+		return x.Locations
+	}
+	return false
+}
+
+type BackpointerMsg struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Level int32    `protobuf:"varint,1,opt,name=level,proto3" json:"level,omitempty"`
+	Node  *NodeMsg `protobuf:"bytes,2,opt,name=node,proto3" json:"node,omitempty"`
+}
+
+func (x *BackpointerMsg) Reset() {
+	*x = BackpointerMsg{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_tapestry_rpc_proto_msgTypes[32]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BackpointerMsg) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BackpointerMsg) ProtoMessage() {}
+
+func (x *BackpointerMsg) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_tapestry_rpc_proto_msgTypes[32]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BackpointerMsg.ProtoReflect.Descriptor instead.
+func (*BackpointerMsg) Descriptor() ([]byte, []int) {
+	return file_pkg_tapestry_rpc_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *BackpointerMsg) GetLevel() int32 {
+	if x != nil {
+		return x.Level
+	}
+	return 0
+}
+
+func (x *BackpointerMsg) GetNode() *NodeMsg {
+	if x != nil {
+		return x.Node
+	}
+	return nil
+}
+
+type LocationEntryMsg struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Key      string        `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Replicas []*ReplicaMsg `protobuf:"bytes,2,rep,name=replicas,proto3" json:"replicas,omitempty"`
+}
+
+func (x *LocationEntryMsg) Reset() {
+	*x = LocationEntryMsg{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_tapestry_rpc_proto_msgTypes[33]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LocationEntryMsg) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LocationEntryMsg) ProtoMessage() {}
+
+func (x *LocationEntryMsg) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_tapestry_rpc_proto_msgTypes[33]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LocationEntryMsg.ProtoReflect.Descriptor instead.
+func (*LocationEntryMsg) Descriptor() ([]byte, []int) {
+	return file_pkg_tapestry_rpc_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *LocationEntryMsg) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *LocationEntryMsg) GetReplicas() []*ReplicaMsg {
+	if x != nil {
+		return x.Replicas
+	}
+	return nil
+}
+
+type NodeStateMsg struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Node            *NodeMsg            `protobuf:"bytes,1,opt,name=node,proto3" json:"node,omitempty"`
+	RoutingTable    []*RoutingEntryMsg  `protobuf:"bytes,2,rep,name=routingTable,proto3" json:"routingTable,omitempty"`
+	Backpointers    []*BackpointerMsg   `protobuf:"bytes,3,rep,name=backpointers,proto3" json:"backpointers,omitempty"`
+	Locations       []*LocationEntryMsg `protobuf:"bytes,4,rep,name=locations,proto3" json:"locations,omitempty"`
+	BackupLocations []*LocationEntryMsg `protobuf:"bytes,5,rep,name=backupLocations,proto3" json:"backupLocations,omitempty"`
+}
+
+func (x *NodeStateMsg) Reset() {
+	*x = NodeStateMsg{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_tapestry_rpc_proto_msgTypes[34]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *NodeStateMsg) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NodeStateMsg) ProtoMessage() {}
+
+func (x *NodeStateMsg) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_tapestry_rpc_proto_msgTypes[34]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NodeStateMsg.ProtoReflect.Descriptor instead.
+func (*NodeStateMsg) Descriptor() ([]byte, []int) {
+	return file_pkg_tapestry_rpc_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *NodeStateMsg) GetNode() *NodeMsg {
+	if x != nil {
+		return x.Node
+	}
+	return nil
+}
+
+func (x *NodeStateMsg) GetRoutingTable() []*RoutingEntryMsg {
+	if x != nil {
+		return x.RoutingTable
+	}
+	return nil
+}
+
+func (x *NodeStateMsg) GetBackpointers() []*BackpointerMsg {
+	if x != nil {
+		return x.Backpointers
+	}
+	return nil
+}
+
+func (x *NodeStateMsg) GetLocations() []*LocationEntryMsg {
+	if x != nil {
+		return x.Locations
+	}
+	return nil
+}
+
+func (x *NodeStateMsg) GetBackupLocations() []*LocationEntryMsg {
+	if x != nil {
+		return x.BackupLocations
+	}
+	return nil
+}
+
+type ListKeysRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Prefix    string `protobuf:"bytes,1,opt,name=prefix,proto3" json:"prefix,omitempty"`
+	PageToken string `protobuf:"bytes,2,opt,name=pageToken,proto3" json:"pageToken,omitempty"`
+	Limit     int32  `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+}
+
+func (x *ListKeysRequest) Reset() {
+	*x = ListKeysRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_tapestry_rpc_proto_msgTypes[35]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListKeysRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListKeysRequest) ProtoMessage() {}
+
+func (x *ListKeysRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_tapestry_rpc_proto_msgTypes[35]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListKeysRequest.ProtoReflect.Descriptor instead.
+func (*ListKeysRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_tapestry_rpc_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *ListKeysRequest) GetPrefix() string {
+	if x != nil {
+		return x.Prefix
+	}
+	return ""
+}
+
+func (x *ListKeysRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *ListKeysRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type KeyList struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Keys          []string `protobuf:"bytes,1,rep,name=keys,proto3" json:"keys,omitempty"`
+	NextPageToken string   `protobuf:"bytes,2,opt,name=nextPageToken,proto3" json:"nextPageToken,omitempty"`
+}
+
+func (x *KeyList) Reset() {
+	*x = KeyList{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_tapestry_rpc_proto_msgTypes[36]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *KeyList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KeyList) ProtoMessage() {}
+
+func (x *KeyList) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_tapestry_rpc_proto_msgTypes[36]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KeyList.ProtoReflect.Descriptor instead.
+func (*KeyList) Descriptor() ([]byte, []int) {
+	return file_pkg_tapestry_rpc_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *KeyList) GetKeys() []string {
+	if x != nil {
+		return x.Keys
+	}
+	return nil
+}
+
+func (x *KeyList) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+type PublishedKeysRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *PublishedKeysRequest) Reset() {
+	*x = PublishedKeysRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_tapestry_rpc_proto_msgTypes[37]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PublishedKeysRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PublishedKeysRequest) ProtoMessage() {}
+
+func (x *PublishedKeysRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_tapestry_rpc_proto_msgTypes[37]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PublishedKeysRequest.ProtoReflect.Descriptor instead.
+func (*PublishedKeysRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_tapestry_rpc_proto_rawDescGZIP(), []int{37}
+}
+
+type PublishStatusMsg struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Key            string   `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Version        uint64   `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`
+	Soft           bool     `protobuf:"varint,3,opt,name=soft,proto3" json:"soft,omitempty"`
+	Root           *NodeMsg `protobuf:"bytes,4,opt,name=root,proto3" json:"root,omitempty"`                      // Unset if the root is unknown
+	LastRegistered int64    `protobuf:"varint,5,opt,name=lastRegistered,proto3" json:"lastRegistered,omitempty"` // Unix time in nanoseconds
+	Failures       int32    `protobuf:"varint,6,opt,name=failures,proto3" json:"failures,omitempty"`
+	LastError      string   `protobuf:"bytes,7,opt,name=lastError,proto3" json:"lastError,omitempty"`
+	NextAttempt    int64    `protobuf:"varint,8,opt,name=nextAttempt,proto3" json:"nextAttempt,omitempty"` // Unix time in nanoseconds
+}
+
+func (x *PublishStatusMsg) Reset() {
+	*x = PublishStatusMsg{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_tapestry_rpc_proto_msgTypes[38]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PublishStatusMsg) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PublishStatusMsg) ProtoMessage() {}
+
+func (x *PublishStatusMsg) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_tapestry_rpc_proto_msgTypes[38]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PublishStatusMsg.ProtoReflect.Descriptor instead.
+func (*PublishStatusMsg) Descriptor() ([]byte, []int) {
+	return file_pkg_tapestry_rpc_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *PublishStatusMsg) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *PublishStatusMsg) GetVersion() uint64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *PublishStatusMsg) GetSoft() bool {
+	if x != nil {
+		return x.Soft
+	}
+	return false
+}
+
+func (x *PublishStatusMsg) GetRoot() *NodeMsg {
+	if x != nil {
+		return x.Root
+	}
+	return nil
+}
+
+func (x *PublishStatusMsg) GetLastRegistered() int64 {
+	if x != nil {
+		return x.LastRegistered
+	}
+	return 0
+}
+
+func (x *PublishStatusMsg) GetFailures() int32 {
+	if x != nil {
+		return x.Failures
+	}
+	return 0
+}
+
+func (x *PublishStatusMsg) GetLastError() string {
+	if x != nil {
+		return x.LastError
+	}
+	return ""
+}
+
+func (x *PublishStatusMsg) GetNextAttempt() int64 {
+	if x != nil {
+		return x.NextAttempt
+	}
+	return 0
+}
+
+type PublishStatuses struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Statuses []*PublishStatusMsg `protobuf:"bytes,1,rep,name=statuses,proto3" json:"statuses,omitempty"`
+}
+
+func (x *PublishStatuses) Reset() {
+	*x = PublishStatuses{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_tapestry_rpc_proto_msgTypes[39]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PublishStatuses) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PublishStatuses) ProtoMessage() {}
+
+func (x *PublishStatuses) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_tapestry_rpc_proto_msgTypes[39]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PublishStatuses.ProtoReflect.Descriptor instead.
+func (*PublishStatuses) Descriptor() ([]byte, []int) {
+	return file_pkg_tapestry_rpc_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *PublishStatuses) GetStatuses() []*PublishStatusMsg {
+	if x != nil {
+		return x.Statuses
+	}
+	return nil
+}
+
+type AdminRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *AdminRequest) Reset() {
+	*x = AdminRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_tapestry_rpc_proto_msgTypes[40]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AdminRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AdminRequest) ProtoMessage() {}
+
+func (x *AdminRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_tapestry_rpc_proto_msgTypes[40]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AdminRequest.ProtoReflect.Descriptor instead.
+func (*AdminRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_tapestry_rpc_proto_rawDescGZIP(), []int{40}
+}
+
+type AdminReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *AdminReply) Reset() {
+	*x = AdminReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_tapestry_rpc_proto_msgTypes[41]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AdminReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AdminReply) ProtoMessage() {}
+
+func (x *AdminReply) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_tapestry_rpc_proto_msgTypes[41]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AdminReply.ProtoReflect.Descriptor instead.
+func (*AdminReply) Descriptor() ([]byte, []int) {
+	return file_pkg_tapestry_rpc_proto_rawDescGZIP(), []int{41}
+}
+
+type BackpointersMsg struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Node         *NodeMsg          `protobuf:"bytes,1,opt,name=node,proto3" json:"node,omitempty"`
+	Backpointers []*BackpointerMsg `protobuf:"bytes,2,rep,name=backpointers,proto3" json:"backpointers,omitempty"`
+}
+
+func (x *BackpointersMsg) Reset() {
+	*x = BackpointersMsg{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_tapestry_rpc_proto_msgTypes[42]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BackpointersMsg) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BackpointersMsg) ProtoMessage() {}
+
+func (x *BackpointersMsg) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_tapestry_rpc_proto_msgTypes[42]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BackpointersMsg.ProtoReflect.Descriptor instead.
+func (*BackpointersMsg) Descriptor() ([]byte, []int) {
+	return file_pkg_tapestry_rpc_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *BackpointersMsg) GetNode() *NodeMsg {
+	if x != nil {
+		return x.Node
+	}
+	return nil
+}
+
+func (x *BackpointersMsg) GetBackpointers() []*BackpointerMsg {
+	if x != nil {
+		return x.Backpointers
+	}
+	return nil
+}
+
+type LocationMapMsg struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Node            *NodeMsg            `protobuf:"bytes,1,opt,name=node,proto3" json:"node,omitempty"`
+	Locations       []*LocationEntryMsg `protobuf:"bytes,2,rep,name=locations,proto3" json:"locations,omitempty"`
+	BackupLocations []*LocationEntryMsg `protobuf:"bytes,3,rep,name=backupLocations,proto3" json:"backupLocations,omitempty"`
+}
+
+func (x *LocationMapMsg) Reset() {
+	*x = LocationMapMsg{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_tapestry_rpc_proto_msgTypes[43]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LocationMapMsg) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LocationMapMsg) ProtoMessage() {}
+
+func (x *LocationMapMsg) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_tapestry_rpc_proto_msgTypes[43]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LocationMapMsg.ProtoReflect.Descriptor instead.
+func (*LocationMapMsg) Descriptor() ([]byte, []int) {
+	return file_pkg_tapestry_rpc_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *LocationMapMsg) GetNode() *NodeMsg {
+	if x != nil {
+		return x.Node
+	}
+	return nil
+}
+
+func (x *LocationMapMsg) GetLocations() []*LocationEntryMsg {
+	if x != nil {
+		return x.Locations
+	}
+	return nil
+}
+
+func (x *LocationMapMsg) GetBackupLocations() []*LocationEntryMsg {
+	if x != nil {
+		return x.BackupLocations
+	}
+	return nil
+}
+
+type BlobInfoMsg struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Key      string       `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Metadata *MetadataMsg `protobuf:"bytes,2,opt,name=metadata,proto3" json:"metadata,omitempty"`
+}
+
+func (x *BlobInfoMsg) Reset() {
+	*x = BlobInfoMsg{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_tapestry_rpc_proto_msgTypes[44]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BlobInfoMsg) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BlobInfoMsg) ProtoMessage() {}
+
+func (x *BlobInfoMsg) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_tapestry_rpc_proto_msgTypes[44]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BlobInfoMsg.ProtoReflect.Descriptor instead.
+func (*BlobInfoMsg) Descriptor() ([]byte, []int) {
+	return file_pkg_tapestry_rpc_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *BlobInfoMsg) GetKey() string {
 	if x != nil {
-		return x.Level
+		return x.Key
 	}
-	return 0
+	return ""
 }
 
-type LeaveNotification struct {
+func (x *BlobInfoMsg) GetMetadata() *MetadataMsg {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+type BlobList struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	From        *NodeMsg `protobuf:"bytes,1,opt,name=from,proto3" json:"from,omitempty"`
-	Replacement *NodeMsg `protobuf:"bytes,2,opt,name=replacement,proto3" json:"replacement,omitempty"`
+	Blobs []*BlobInfoMsg `protobuf:"bytes,1,rep,name=blobs,proto3" json:"blobs,omitempty"`
 }
 
-func (x *LeaveNotification) Reset() {
-	*x = LeaveNotification{}
+func (x *BlobList) Reset() {
+	*x = BlobList{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_pkg_tapestry_rpc_proto_msgTypes[12]
+		mi := &file_pkg_tapestry_rpc_proto_msgTypes[45]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *LeaveNotification) String() string {
+func (x *BlobList) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*LeaveNotification) ProtoMessage() {}
+func (*BlobList) ProtoMessage() {}
 
-func (x *LeaveNotification) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_tapestry_rpc_proto_msgTypes[12]
+func (x *BlobList) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_tapestry_rpc_proto_msgTypes[45]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -699,23 +2654,110 @@ func (x *LeaveNotification) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use LeaveNotification.ProtoReflect.Descriptor instead.
-func (*LeaveNotification) Descriptor() ([]byte, []int) {
-	return file_pkg_tapestry_rpc_proto_rawDescGZIP(), []int{12}
+// Deprecated: Use BlobList.ProtoReflect.Descriptor instead.
+func (*BlobList) Descriptor() ([]byte, []int) {
+	return file_pkg_tapestry_rpc_proto_rawDescGZIP(), []int{45}
 }
 
-func (x *LeaveNotification) GetFrom() *NodeMsg {
+func (x *BlobList) GetBlobs() []*BlobInfoMsg {
 	if x != nil {
-		return x.From
+		return x.Blobs
 	}
 	return nil
 }
 
-func (x *LeaveNotification) GetReplacement() *NodeMsg {
+type LogLevelRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Level string `protobuf:"bytes,1,opt,name=level,proto3" json:"level,omitempty"` // error, info, debug or trace
+}
+
+func (x *LogLevelRequest) Reset() {
+	*x = LogLevelRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_tapestry_rpc_proto_msgTypes[46]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LogLevelRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LogLevelRequest) ProtoMessage() {}
+
+func (x *LogLevelRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_tapestry_rpc_proto_msgTypes[46]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LogLevelRequest.ProtoReflect.Descriptor instead.
+func (*LogLevelRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_tapestry_rpc_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *LogLevelRequest) GetLevel() string {
 	if x != nil {
-		return x.Replacement
+		return x.Level
 	}
-	return nil
+	return ""
+}
+
+type LogLevelReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Previous string `protobuf:"bytes,1,opt,name=previous,proto3" json:"previous,omitempty"` // The level before the change
+}
+
+func (x *LogLevelReply) Reset() {
+	*x = LogLevelReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_tapestry_rpc_proto_msgTypes[47]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LogLevelReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LogLevelReply) ProtoMessage() {}
+
+func (x *LogLevelReply) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_tapestry_rpc_proto_msgTypes[47]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LogLevelReply.ProtoReflect.Descriptor instead.
+func (*LogLevelReply) Descriptor() ([]byte, []int) {
+	return file_pkg_tapestry_rpc_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *LogLevelReply) GetPrevious() string {
+	if x != nil {
+		return x.Previous
+	}
+	return ""
 }
 
 var File_pkg_tapestry_rpc_proto protoreflect.FileDescriptor
@@ -727,125 +2769,446 @@ var file_pkg_tapestry_rpc_proto_rawDesc = []byte{
 	0x20, 0x01, 0x28, 0x08, 0x52, 0x02, 0x6f, 0x6b, 0x22, 0x2d, 0x0a, 0x05, 0x49, 0x64, 0x4d, 0x73,
 	0x67, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69,
 	0x64, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05,
-	0x52, 0x05, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x22, 0x30, 0x0a, 0x08, 0x44, 0x61, 0x74, 0x61, 0x42,
-	0x6c, 0x6f, 0x62, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x0c, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x02,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x22, 0x17, 0x0a, 0x03, 0x4b, 0x65, 0x79,
-	0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b,
-	0x65, 0x79, 0x22, 0x33, 0x0a, 0x07, 0x4e, 0x6f, 0x64, 0x65, 0x4d, 0x73, 0x67, 0x12, 0x18, 0x0a,
-	0x07, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07,
-	0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x02, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x22, 0x5f, 0x0a, 0x07, 0x52, 0x6f, 0x6f, 0x74, 0x4d,
-	0x73, 0x67, 0x12, 0x25, 0x0a, 0x04, 0x6e, 0x65, 0x78, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b,
-	0x32, 0x11, 0x2e, 0x74, 0x61, 0x70, 0x65, 0x73, 0x74, 0x72, 0x79, 0x2e, 0x4e, 0x6f, 0x64, 0x65,
-	0x4d, 0x73, 0x67, 0x52, 0x04, 0x6e, 0x65, 0x78, 0x74, 0x12, 0x2d, 0x0a, 0x08, 0x74, 0x6f, 0x52,
-	0x65, 0x6d, 0x6f, 0x76, 0x65, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x74, 0x61,
-	0x70, 0x65, 0x73, 0x74, 0x72, 0x79, 0x2e, 0x4e, 0x6f, 0x64, 0x65, 0x4d, 0x73, 0x67, 0x52, 0x08,
-	0x74, 0x6f, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x22, 0x4f, 0x0a, 0x0c, 0x52, 0x65, 0x67, 0x69,
-	0x73, 0x74, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x2d, 0x0a, 0x08, 0x66, 0x72, 0x6f, 0x6d,
-	0x4e, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x74, 0x61, 0x70,
-	0x65, 0x73, 0x74, 0x72, 0x79, 0x2e, 0x4e, 0x6f, 0x64, 0x65, 0x4d, 0x73, 0x67, 0x52, 0x08, 0x66,
-	0x72, 0x6f, 0x6d, 0x4e, 0x6f, 0x64, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x02,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x22, 0x55, 0x0a, 0x10, 0x46, 0x65, 0x74,
+	0x52, 0x05, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x22, 0xa1, 0x01, 0x0a, 0x08, 0x44, 0x61, 0x74, 0x61,
+	0x42, 0x6c, 0x6f, 0x62, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0c, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x31, 0x0a, 0x08, 0x6d, 0x65,
+	0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x74,
+	0x61, 0x70, 0x65, 0x73, 0x74, 0x72, 0x79, 0x2e, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61,
+	0x4d, 0x73, 0x67, 0x52, 0x08, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x12, 0x20, 0x0a,
+	0x0b, 0x63, 0x6f, 0x6e, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x61, 0x6c, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x0b, 0x63, 0x6f, 0x6e, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x61, 0x6c, 0x12,
+	0x1a, 0x0a, 0x08, 0x65, 0x78, 0x70, 0x65, 0x63, 0x74, 0x65, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x04, 0x52, 0x08, 0x65, 0x78, 0x70, 0x65, 0x63, 0x74, 0x65, 0x64, 0x22, 0x86, 0x01, 0x0a, 0x0a,
+	0x53, 0x74, 0x6f, 0x72, 0x65, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65,
+	0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x07, 0x76, 0x65, 0x72,
+	0x73, 0x69, 0x6f, 0x6e, 0x12, 0x1a, 0x0a, 0x08, 0x63, 0x6f, 0x6e, 0x66, 0x6c, 0x69, 0x63, 0x74,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x63, 0x6f, 0x6e, 0x66, 0x6c, 0x69, 0x63, 0x74,
+	0x12, 0x18, 0x0a, 0x07, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x04, 0x52, 0x07, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x66, 0x75,
+	0x6c, 0x6c, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x04, 0x66, 0x75, 0x6c, 0x6c, 0x12, 0x14,
+	0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65,
+	0x72, 0x72, 0x6f, 0x72, 0x22, 0x35, 0x0a, 0x09, 0x44, 0x61, 0x74, 0x61, 0x42, 0x6c, 0x6f, 0x62,
+	0x73, 0x12, 0x28, 0x0a, 0x05, 0x62, 0x6c, 0x6f, 0x62, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x12, 0x2e, 0x74, 0x61, 0x70, 0x65, 0x73, 0x74, 0x72, 0x79, 0x2e, 0x44, 0x61, 0x74, 0x61,
+	0x42, 0x6c, 0x6f, 0x62, 0x52, 0x05, 0x62, 0x6c, 0x6f, 0x62, 0x73, 0x22, 0x3e, 0x0a, 0x0c, 0x53,
+	0x74, 0x6f, 0x72, 0x65, 0x52, 0x65, 0x70, 0x6c, 0x69, 0x65, 0x73, 0x12, 0x2e, 0x0a, 0x07, 0x72,
+	0x65, 0x70, 0x6c, 0x69, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x74,
+	0x61, 0x70, 0x65, 0x73, 0x74, 0x72, 0x79, 0x2e, 0x53, 0x74, 0x6f, 0x72, 0x65, 0x52, 0x65, 0x70,
+	0x6c, 0x79, 0x52, 0x07, 0x72, 0x65, 0x70, 0x6c, 0x69, 0x65, 0x73, 0x22, 0xc9, 0x02, 0x0a, 0x0b,
+	0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x4d, 0x73, 0x67, 0x12, 0x12, 0x0a, 0x04, 0x73,
+	0x69, 0x7a, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x12,
+	0x20, 0x0a, 0x0b, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x54, 0x79, 0x70, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x54, 0x79, 0x70,
+	0x65, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x07, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x12, 0x1a, 0x0a, 0x08, 0x6d,
+	0x6f, 0x64, 0x69, 0x66, 0x69, 0x65, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x6d,
+	0x6f, 0x64, 0x69, 0x66, 0x69, 0x65, 0x64, 0x12, 0x33, 0x0a, 0x04, 0x74, 0x61, 0x67, 0x73, 0x18,
+	0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x74, 0x61, 0x70, 0x65, 0x73, 0x74, 0x72, 0x79,
+	0x2e, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x4d, 0x73, 0x67, 0x2e, 0x54, 0x61, 0x67,
+	0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x04, 0x74, 0x61, 0x67, 0x73, 0x12, 0x18, 0x0a, 0x07,
+	0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x06, 0x20, 0x01, 0x28, 0x04, 0x52, 0x07, 0x76,
+	0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x18, 0x0a, 0x07, 0x65, 0x78, 0x70, 0x69, 0x72, 0x65,
+	0x73, 0x18, 0x07, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x65, 0x78, 0x70, 0x69, 0x72, 0x65, 0x73,
+	0x12, 0x16, 0x0a, 0x06, 0x63, 0x61, 0x63, 0x68, 0x65, 0x64, 0x18, 0x08, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x06, 0x63, 0x61, 0x63, 0x68, 0x65, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x65, 0x61, 0x73,
+	0x65, 0x18, 0x09, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x6c, 0x65, 0x61, 0x73, 0x65, 0x1a, 0x37,
+	0x0a, 0x09, 0x54, 0x61, 0x67, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b,
+	0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a,
+	0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61,
+	0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x17, 0x0a, 0x03, 0x4b, 0x65, 0x79, 0x12, 0x10,
+	0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79,
+	0x22, 0x1a, 0x0a, 0x04, 0x4b, 0x65, 0x79, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x6b, 0x65, 0x79, 0x73,
+	0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x04, 0x6b, 0x65, 0x79, 0x73, 0x22, 0x33, 0x0a, 0x07,
+	0x4e, 0x6f, 0x64, 0x65, 0x4d, 0x73, 0x67, 0x12, 0x18, 0x0a, 0x07, 0x61, 0x64, 0x64, 0x72, 0x65,
+	0x73, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73,
+	0x73, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69,
+	0x64, 0x22, 0x73, 0x0a, 0x07, 0x52, 0x6f, 0x6f, 0x74, 0x4d, 0x73, 0x67, 0x12, 0x25, 0x0a, 0x04,
+	0x6e, 0x65, 0x78, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x74, 0x61, 0x70,
+	0x65, 0x73, 0x74, 0x72, 0x79, 0x2e, 0x4e, 0x6f, 0x64, 0x65, 0x4d, 0x73, 0x67, 0x52, 0x04, 0x6e,
+	0x65, 0x78, 0x74, 0x12, 0x2d, 0x0a, 0x08, 0x74, 0x6f, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x18,
+	0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x74, 0x61, 0x70, 0x65, 0x73, 0x74, 0x72, 0x79,
+	0x2e, 0x4e, 0x6f, 0x64, 0x65, 0x4d, 0x73, 0x67, 0x52, 0x08, 0x74, 0x6f, 0x52, 0x65, 0x6d, 0x6f,
+	0x76, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x68, 0x6f, 0x70, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x04, 0x68, 0x6f, 0x70, 0x73, 0x22, 0xd5, 0x01, 0x0a, 0x0c, 0x52, 0x65, 0x67, 0x69, 0x73,
+	0x74, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x2d, 0x0a, 0x08, 0x66, 0x72, 0x6f, 0x6d, 0x4e,
+	0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x74, 0x61, 0x70, 0x65,
+	0x73, 0x74, 0x72, 0x79, 0x2e, 0x4e, 0x6f, 0x64, 0x65, 0x4d, 0x73, 0x67, 0x52, 0x08, 0x66, 0x72,
+	0x6f, 0x6d, 0x4e, 0x6f, 0x64, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73,
+	0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69,
+	0x6f, 0x6e, 0x12, 0x20, 0x0a, 0x0b, 0x63, 0x6f, 0x6e, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x61,
+	0x6c, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0b, 0x63, 0x6f, 0x6e, 0x64, 0x69, 0x74, 0x69,
+	0x6f, 0x6e, 0x61, 0x6c, 0x12, 0x1a, 0x0a, 0x08, 0x65, 0x78, 0x70, 0x65, 0x63, 0x74, 0x65, 0x64,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x04, 0x52, 0x08, 0x65, 0x78, 0x70, 0x65, 0x63, 0x74, 0x65, 0x64,
+	0x12, 0x18, 0x0a, 0x07, 0x65, 0x78, 0x70, 0x69, 0x72, 0x65, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x07, 0x65, 0x78, 0x70, 0x69, 0x72, 0x65, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x6f,
+	0x66, 0x74, 0x18, 0x07, 0x20, 0x01, 0x28, 0x08, 0x52, 0x04, 0x73, 0x6f, 0x66, 0x74, 0x22, 0x63,
+	0x0a, 0x11, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65,
+	0x70, 0x6c, 0x79, 0x12, 0x16, 0x0a, 0x06, 0x69, 0x73, 0x52, 0x6f, 0x6f, 0x74, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x06, 0x69, 0x73, 0x52, 0x6f, 0x6f, 0x74, 0x12, 0x1e, 0x0a, 0x0a, 0x72,
+	0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x65, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x0a, 0x72, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x65, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x6e,
+	0x65, 0x77, 0x65, 0x73, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x6e, 0x65, 0x77,
+	0x65, 0x73, 0x74, 0x22, 0x4d, 0x0a, 0x0d, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x72, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x73, 0x12, 0x3c, 0x0a, 0x0d, 0x72, 0x65, 0x67, 0x69, 0x73, 0x74, 0x72, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x74, 0x61,
+	0x70, 0x65, 0x73, 0x74, 0x72, 0x79, 0x2e, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x72, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x52, 0x0d, 0x72, 0x65, 0x67, 0x69, 0x73, 0x74, 0x72, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x73, 0x22, 0x4c, 0x0a, 0x13, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x72, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x52, 0x65, 0x70, 0x6c, 0x69, 0x65, 0x73, 0x12, 0x35, 0x0a, 0x07, 0x72, 0x65, 0x70,
+	0x6c, 0x69, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x74, 0x61, 0x70,
+	0x65, 0x73, 0x74, 0x72, 0x79, 0x2e, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x72, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x52, 0x07, 0x72, 0x65, 0x70, 0x6c, 0x69, 0x65, 0x73,
+	0x22, 0x7b, 0x0a, 0x0a, 0x52, 0x65, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x4d, 0x73, 0x67, 0x12, 0x25,
+	0x0a, 0x04, 0x6e, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x74,
+	0x61, 0x70, 0x65, 0x73, 0x74, 0x72, 0x79, 0x2e, 0x4e, 0x6f, 0x64, 0x65, 0x4d, 0x73, 0x67, 0x52,
+	0x04, 0x6e, 0x6f, 0x64, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12,
+	0x18, 0x0a, 0x07, 0x65, 0x78, 0x70, 0x69, 0x72, 0x65, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x07, 0x65, 0x78, 0x70, 0x69, 0x72, 0x65, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x6f, 0x66,
+	0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x04, 0x73, 0x6f, 0x66, 0x74, 0x22, 0x3c, 0x0a,
+	0x08, 0x52, 0x65, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x73, 0x12, 0x30, 0x0a, 0x08, 0x72, 0x65, 0x70,
+	0x6c, 0x69, 0x63, 0x61, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x74, 0x61,
+	0x70, 0x65, 0x73, 0x74, 0x72, 0x79, 0x2e, 0x52, 0x65, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x4d, 0x73,
+	0x67, 0x52, 0x08, 0x72, 0x65, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x73, 0x22, 0x9b, 0x02, 0x0a, 0x0d,
+	0x52, 0x65, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x73, 0x42, 0x79, 0x4b, 0x65, 0x79, 0x12, 0x41, 0x0a,
+	0x08, 0x72, 0x65, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x25, 0x2e, 0x74, 0x61, 0x70, 0x65, 0x73, 0x74, 0x72, 0x79, 0x2e, 0x52, 0x65, 0x70, 0x6c, 0x69,
+	0x63, 0x61, 0x73, 0x42, 0x79, 0x4b, 0x65, 0x79, 0x2e, 0x52, 0x65, 0x70, 0x6c, 0x69, 0x63, 0x61,
+	0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x08, 0x72, 0x65, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x73,
+	0x12, 0x3b, 0x0a, 0x06, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x23, 0x2e, 0x74, 0x61, 0x70, 0x65, 0x73, 0x74, 0x72, 0x79, 0x2e, 0x52, 0x65, 0x70, 0x6c,
+	0x69, 0x63, 0x61, 0x73, 0x42, 0x79, 0x4b, 0x65, 0x79, 0x2e, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x73,
+	0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x06, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x1a, 0x4f, 0x0a,
+	0x0d, 0x52, 0x65, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10,
+	0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79,
+	0x12, 0x28, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x12, 0x2e, 0x74, 0x61, 0x70, 0x65, 0x73, 0x74, 0x72, 0x79, 0x2e, 0x52, 0x65, 0x70, 0x6c, 0x69,
+	0x63, 0x61, 0x73, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x1a, 0x39,
+	0x0a, 0x0b, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a,
+	0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12,
+	0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05,
+	0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x5c, 0x0a, 0x10, 0x46, 0x65, 0x74,
 	0x63, 0x68, 0x65, 0x64, 0x4c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x16, 0x0a,
 	0x06, 0x69, 0x73, 0x52, 0x6f, 0x6f, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x69,
-	0x73, 0x52, 0x6f, 0x6f, 0x74, 0x12, 0x29, 0x0a, 0x06, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x18,
-	0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x74, 0x61, 0x70, 0x65, 0x73, 0x74, 0x72, 0x79,
-	0x2e, 0x4e, 0x6f, 0x64, 0x65, 0x4d, 0x73, 0x67, 0x52, 0x06, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x73,
-	0x22, 0x3c, 0x0a, 0x09, 0x4e, 0x65, 0x69, 0x67, 0x68, 0x62, 0x6f, 0x72, 0x73, 0x12, 0x2f, 0x0a,
-	0x09, 0x6e, 0x65, 0x69, 0x67, 0x68, 0x62, 0x6f, 0x72, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b,
+	0x73, 0x52, 0x6f, 0x6f, 0x74, 0x12, 0x30, 0x0a, 0x08, 0x72, 0x65, 0x70, 0x6c, 0x69, 0x63, 0x61,
+	0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x74, 0x61, 0x70, 0x65, 0x73, 0x74,
+	0x72, 0x79, 0x2e, 0x52, 0x65, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x4d, 0x73, 0x67, 0x52, 0x08, 0x72,
+	0x65, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x73, 0x22, 0x50, 0x0a, 0x14, 0x46, 0x65, 0x74, 0x63, 0x68,
+	0x65, 0x64, 0x4c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x4c, 0x69, 0x73, 0x74, 0x12,
+	0x38, 0x0a, 0x09, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x01, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x74, 0x61, 0x70, 0x65, 0x73, 0x74, 0x72, 0x79, 0x2e, 0x46, 0x65,
+	0x74, 0x63, 0x68, 0x65, 0x64, 0x4c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x09,
+	0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x22, 0x3c, 0x0a, 0x09, 0x4e, 0x65, 0x69,
+	0x67, 0x68, 0x62, 0x6f, 0x72, 0x73, 0x12, 0x2f, 0x0a, 0x09, 0x6e, 0x65, 0x69, 0x67, 0x68, 0x62,
+	0x6f, 0x72, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x74, 0x61, 0x70, 0x65,
+	0x73, 0x74, 0x72, 0x79, 0x2e, 0x4e, 0x6f, 0x64, 0x65, 0x4d, 0x73, 0x67, 0x52, 0x09, 0x6e, 0x65,
+	0x69, 0x67, 0x68, 0x62, 0x6f, 0x72, 0x73, 0x22, 0x55, 0x0a, 0x10, 0x4d, 0x75, 0x6c, 0x74, 0x69,
+	0x63, 0x61, 0x73, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x2b, 0x0a, 0x07, 0x6e,
+	0x65, 0x77, 0x4e, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x74,
+	0x61, 0x70, 0x65, 0x73, 0x74, 0x72, 0x79, 0x2e, 0x4e, 0x6f, 0x64, 0x65, 0x4d, 0x73, 0x67, 0x52,
+	0x07, 0x6e, 0x65, 0x77, 0x4e, 0x6f, 0x64, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x65, 0x76, 0x65,
+	0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x22, 0xb8,
+	0x01, 0x0a, 0x0c, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x44, 0x61, 0x74, 0x61, 0x12,
+	0x25, 0x0a, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e,
+	0x74, 0x61, 0x70, 0x65, 0x73, 0x74, 0x72, 0x79, 0x2e, 0x4e, 0x6f, 0x64, 0x65, 0x4d, 0x73, 0x67,
+	0x52, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x12, 0x34, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x02,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x20, 0x2e, 0x74, 0x61, 0x70, 0x65, 0x73, 0x74, 0x72, 0x79, 0x2e,
+	0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x44, 0x61, 0x74, 0x61, 0x2e, 0x44, 0x61, 0x74,
+	0x61, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x1a, 0x4b, 0x0a, 0x09,
+	0x44, 0x61, 0x74, 0x61, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x28, 0x0a, 0x05, 0x76,
+	0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x74, 0x61, 0x70,
+	0x65, 0x73, 0x74, 0x72, 0x79, 0x2e, 0x52, 0x65, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x73, 0x52, 0x05,
+	0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x69, 0x0a, 0x0b, 0x4d, 0x69, 0x72,
+	0x72, 0x6f, 0x72, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x2e, 0x0a, 0x07, 0x72, 0x65,
+	0x70, 0x6c, 0x69, 0x63, 0x61, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x74, 0x61,
+	0x70, 0x65, 0x73, 0x74, 0x72, 0x79, 0x2e, 0x52, 0x65, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x4d, 0x73,
+	0x67, 0x52, 0x07, 0x72, 0x65, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x12, 0x18, 0x0a, 0x07, 0x72, 0x65,
+	0x6d, 0x6f, 0x76, 0x65, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x72, 0x65, 0x6d,
+	0x6f, 0x76, 0x65, 0x64, 0x22, 0x64, 0x0a, 0x0a, 0x4d, 0x69, 0x72, 0x72, 0x6f, 0x72, 0x44, 0x61,
+	0x74, 0x61, 0x12, 0x25, 0x0a, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b,
 	0x32, 0x11, 0x2e, 0x74, 0x61, 0x70, 0x65, 0x73, 0x74, 0x72, 0x79, 0x2e, 0x4e, 0x6f, 0x64, 0x65,
-	0x4d, 0x73, 0x67, 0x52, 0x09, 0x6e, 0x65, 0x69, 0x67, 0x68, 0x62, 0x6f, 0x72, 0x73, 0x22, 0x55,
-	0x0a, 0x10, 0x4d, 0x75, 0x6c, 0x74, 0x69, 0x63, 0x61, 0x73, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x12, 0x2b, 0x0a, 0x07, 0x6e, 0x65, 0x77, 0x4e, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20,
-	0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x74, 0x61, 0x70, 0x65, 0x73, 0x74, 0x72, 0x79, 0x2e, 0x4e,
-	0x6f, 0x64, 0x65, 0x4d, 0x73, 0x67, 0x52, 0x07, 0x6e, 0x65, 0x77, 0x4e, 0x6f, 0x64, 0x65, 0x12,
-	0x14, 0x0a, 0x05, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05,
-	0x6c, 0x65, 0x76, 0x65, 0x6c, 0x22, 0xb9, 0x01, 0x0a, 0x0c, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66,
-	0x65, 0x72, 0x44, 0x61, 0x74, 0x61, 0x12, 0x25, 0x0a, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x18, 0x01,
+	0x4d, 0x73, 0x67, 0x52, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x12, 0x2f, 0x0a, 0x07, 0x65, 0x6e, 0x74,
+	0x72, 0x69, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x74, 0x61, 0x70,
+	0x65, 0x73, 0x74, 0x72, 0x79, 0x2e, 0x4d, 0x69, 0x72, 0x72, 0x6f, 0x72, 0x45, 0x6e, 0x74, 0x72,
+	0x79, 0x52, 0x07, 0x65, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x22, 0x28, 0x0a, 0x0e, 0x53, 0x75,
+	0x6d, 0x6d, 0x61, 0x72, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x16, 0x0a, 0x06,
+	0x70, 0x72, 0x65, 0x66, 0x69, 0x78, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x70, 0x72,
+	0x65, 0x66, 0x69, 0x78, 0x22, 0x23, 0x0a, 0x07, 0x53, 0x75, 0x6d, 0x6d, 0x61, 0x72, 0x79, 0x12,
+	0x18, 0x0a, 0x07, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0c,
+	0x52, 0x07, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x73, 0x22, 0x51, 0x0a, 0x12, 0x42, 0x61, 0x63,
+	0x6b, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x25, 0x0a, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e,
+	0x74, 0x61, 0x70, 0x65, 0x73, 0x74, 0x72, 0x79, 0x2e, 0x4e, 0x6f, 0x64, 0x65, 0x4d, 0x73, 0x67,
+	0x52, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x22, 0x6f, 0x0a, 0x11,
+	0x4c, 0x65, 0x61, 0x76, 0x65, 0x4e, 0x6f, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x12, 0x25, 0x0a, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x11, 0x2e, 0x74, 0x61, 0x70, 0x65, 0x73, 0x74, 0x72, 0x79, 0x2e, 0x4e, 0x6f, 0x64, 0x65, 0x4d,
+	0x73, 0x67, 0x52, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x12, 0x33, 0x0a, 0x0b, 0x72, 0x65, 0x70, 0x6c,
+	0x61, 0x63, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e,
+	0x74, 0x61, 0x70, 0x65, 0x73, 0x74, 0x72, 0x79, 0x2e, 0x4e, 0x6f, 0x64, 0x65, 0x4d, 0x73, 0x67,
+	0x52, 0x0b, 0x72, 0x65, 0x70, 0x6c, 0x61, 0x63, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x22, 0x64, 0x0a,
+	0x0f, 0x52, 0x6f, 0x75, 0x74, 0x69, 0x6e, 0x67, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x4d, 0x73, 0x67,
+	0x12, 0x14, 0x0a, 0x05, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x05, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x12, 0x14, 0x0a, 0x05, 0x64, 0x69, 0x67, 0x69, 0x74, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x64, 0x69, 0x67, 0x69, 0x74, 0x12, 0x25, 0x0a, 0x04,
+	0x6e, 0x6f, 0x64, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x74, 0x61, 0x70,
+	0x65, 0x73, 0x74, 0x72, 0x79, 0x2e, 0x4e, 0x6f, 0x64, 0x65, 0x4d, 0x73, 0x67, 0x52, 0x04, 0x6e,
+	0x6f, 0x64, 0x65, 0x22, 0x6d, 0x0a, 0x0f, 0x52, 0x6f, 0x75, 0x74, 0x69, 0x6e, 0x67, 0x54, 0x61,
+	0x62, 0x6c, 0x65, 0x4d, 0x73, 0x67, 0x12, 0x25, 0x0a, 0x04, 0x6e, 0x6f, 0x64, 0x65, 0x18, 0x01,
 	0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x74, 0x61, 0x70, 0x65, 0x73, 0x74, 0x72, 0x79, 0x2e,
-	0x4e, 0x6f, 0x64, 0x65, 0x4d, 0x73, 0x67, 0x52, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x12, 0x34, 0x0a,
-	0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x20, 0x2e, 0x74, 0x61,
-	0x70, 0x65, 0x73, 0x74, 0x72, 0x79, 0x2e, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x44,
-	0x61, 0x74, 0x61, 0x2e, 0x44, 0x61, 0x74, 0x61, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x04, 0x64,
-	0x61, 0x74, 0x61, 0x1a, 0x4c, 0x0a, 0x09, 0x44, 0x61, 0x74, 0x61, 0x45, 0x6e, 0x74, 0x72, 0x79,
-	0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b,
-	0x65, 0x79, 0x12, 0x29, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
-	0x0b, 0x32, 0x13, 0x2e, 0x74, 0x61, 0x70, 0x65, 0x73, 0x74, 0x72, 0x79, 0x2e, 0x4e, 0x65, 0x69,
-	0x67, 0x68, 0x62, 0x6f, 0x72, 0x73, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38,
-	0x01, 0x22, 0x51, 0x0a, 0x12, 0x42, 0x61, 0x63, 0x6b, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x65, 0x72,
-	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x25, 0x0a, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x74, 0x61, 0x70, 0x65, 0x73, 0x74, 0x72, 0x79,
-	0x2e, 0x4e, 0x6f, 0x64, 0x65, 0x4d, 0x73, 0x67, 0x52, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x12, 0x14,
-	0x0a, 0x05, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x6c,
-	0x65, 0x76, 0x65, 0x6c, 0x22, 0x6f, 0x0a, 0x11, 0x4c, 0x65, 0x61, 0x76, 0x65, 0x4e, 0x6f, 0x74,
-	0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x25, 0x0a, 0x04, 0x66, 0x72, 0x6f,
-	0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x74, 0x61, 0x70, 0x65, 0x73, 0x74,
-	0x72, 0x79, 0x2e, 0x4e, 0x6f, 0x64, 0x65, 0x4d, 0x73, 0x67, 0x52, 0x04, 0x66, 0x72, 0x6f, 0x6d,
-	0x12, 0x33, 0x0a, 0x0b, 0x72, 0x65, 0x70, 0x6c, 0x61, 0x63, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x18,
-	0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x74, 0x61, 0x70, 0x65, 0x73, 0x74, 0x72, 0x79,
-	0x2e, 0x4e, 0x6f, 0x64, 0x65, 0x4d, 0x73, 0x67, 0x52, 0x0b, 0x72, 0x65, 0x70, 0x6c, 0x61, 0x63,
-	0x65, 0x6d, 0x65, 0x6e, 0x74, 0x32, 0xb0, 0x07, 0x0a, 0x0b, 0x54, 0x61, 0x70, 0x65, 0x73, 0x74,
-	0x72, 0x79, 0x52, 0x50, 0x43, 0x12, 0x35, 0x0a, 0x0b, 0x48, 0x65, 0x6c, 0x6c, 0x6f, 0x43, 0x61,
-	0x6c, 0x6c, 0x65, 0x72, 0x12, 0x11, 0x2e, 0x74, 0x61, 0x70, 0x65, 0x73, 0x74, 0x72, 0x79, 0x2e,
-	0x4e, 0x6f, 0x64, 0x65, 0x4d, 0x73, 0x67, 0x1a, 0x11, 0x2e, 0x74, 0x61, 0x70, 0x65, 0x73, 0x74,
-	0x72, 0x79, 0x2e, 0x4e, 0x6f, 0x64, 0x65, 0x4d, 0x73, 0x67, 0x22, 0x00, 0x12, 0x36, 0x0a, 0x0e,
-	0x46, 0x69, 0x6e, 0x64, 0x52, 0x6f, 0x6f, 0x74, 0x43, 0x61, 0x6c, 0x6c, 0x65, 0x72, 0x12, 0x0f,
-	0x2e, 0x74, 0x61, 0x70, 0x65, 0x73, 0x74, 0x72, 0x79, 0x2e, 0x49, 0x64, 0x4d, 0x73, 0x67, 0x1a,
-	0x11, 0x2e, 0x74, 0x61, 0x70, 0x65, 0x73, 0x74, 0x72, 0x79, 0x2e, 0x52, 0x6f, 0x6f, 0x74, 0x4d,
-	0x73, 0x67, 0x22, 0x00, 0x12, 0x38, 0x0a, 0x0e, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72,
-	0x43, 0x61, 0x6c, 0x6c, 0x65, 0x72, 0x12, 0x16, 0x2e, 0x74, 0x61, 0x70, 0x65, 0x73, 0x74, 0x72,
-	0x79, 0x2e, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x1a, 0x0c,
-	0x2e, 0x74, 0x61, 0x70, 0x65, 0x73, 0x74, 0x72, 0x79, 0x2e, 0x4f, 0x6b, 0x22, 0x00, 0x12, 0x3a,
-	0x0a, 0x0b, 0x46, 0x65, 0x74, 0x63, 0x68, 0x43, 0x61, 0x6c, 0x6c, 0x65, 0x72, 0x12, 0x0d, 0x2e,
-	0x74, 0x61, 0x70, 0x65, 0x73, 0x74, 0x72, 0x79, 0x2e, 0x4b, 0x65, 0x79, 0x1a, 0x1a, 0x2e, 0x74,
-	0x61, 0x70, 0x65, 0x73, 0x74, 0x72, 0x79, 0x2e, 0x46, 0x65, 0x74, 0x63, 0x68, 0x65, 0x64, 0x4c,
-	0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x22, 0x00, 0x12, 0x39, 0x0a, 0x0d, 0x41, 0x64,
-	0x64, 0x4e, 0x6f, 0x64, 0x65, 0x43, 0x61, 0x6c, 0x6c, 0x65, 0x72, 0x12, 0x11, 0x2e, 0x74, 0x61,
-	0x70, 0x65, 0x73, 0x74, 0x72, 0x79, 0x2e, 0x4e, 0x6f, 0x64, 0x65, 0x4d, 0x73, 0x67, 0x1a, 0x13,
+	0x4e, 0x6f, 0x64, 0x65, 0x4d, 0x73, 0x67, 0x52, 0x04, 0x6e, 0x6f, 0x64, 0x65, 0x12, 0x33, 0x0a,
+	0x07, 0x65, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x19,
+	0x2e, 0x74, 0x61, 0x70, 0x65, 0x73, 0x74, 0x72, 0x79, 0x2e, 0x52, 0x6f, 0x75, 0x74, 0x69, 0x6e,
+	0x67, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x4d, 0x73, 0x67, 0x52, 0x07, 0x65, 0x6e, 0x74, 0x72, 0x69,
+	0x65, 0x73, 0x22, 0x2c, 0x0a, 0x0c, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73,
+	0x22, 0x4d, 0x0a, 0x0e, 0x42, 0x61, 0x63, 0x6b, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x4d,
+	0x73, 0x67, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x05, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x12, 0x25, 0x0a, 0x04, 0x6e, 0x6f, 0x64, 0x65,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x74, 0x61, 0x70, 0x65, 0x73, 0x74, 0x72,
+	0x79, 0x2e, 0x4e, 0x6f, 0x64, 0x65, 0x4d, 0x73, 0x67, 0x52, 0x04, 0x6e, 0x6f, 0x64, 0x65, 0x22,
+	0x56, 0x0a, 0x10, 0x4c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x45, 0x6e, 0x74, 0x72, 0x79,
+	0x4d, 0x73, 0x67, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x30, 0x0a, 0x08, 0x72, 0x65, 0x70, 0x6c, 0x69, 0x63, 0x61,
+	0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x74, 0x61, 0x70, 0x65, 0x73, 0x74,
+	0x72, 0x79, 0x2e, 0x52, 0x65, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x4d, 0x73, 0x67, 0x52, 0x08, 0x72,
+	0x65, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x73, 0x22, 0xb2, 0x02, 0x0a, 0x0c, 0x4e, 0x6f, 0x64, 0x65,
+	0x53, 0x74, 0x61, 0x74, 0x65, 0x4d, 0x73, 0x67, 0x12, 0x25, 0x0a, 0x04, 0x6e, 0x6f, 0x64, 0x65,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x74, 0x61, 0x70, 0x65, 0x73, 0x74, 0x72,
+	0x79, 0x2e, 0x4e, 0x6f, 0x64, 0x65, 0x4d, 0x73, 0x67, 0x52, 0x04, 0x6e, 0x6f, 0x64, 0x65, 0x12,
+	0x3d, 0x0a, 0x0c, 0x72, 0x6f, 0x75, 0x74, 0x69, 0x6e, 0x67, 0x54, 0x61, 0x62, 0x6c, 0x65, 0x18,
+	0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x74, 0x61, 0x70, 0x65, 0x73, 0x74, 0x72, 0x79,
+	0x2e, 0x52, 0x6f, 0x75, 0x74, 0x69, 0x6e, 0x67, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x4d, 0x73, 0x67,
+	0x52, 0x0c, 0x72, 0x6f, 0x75, 0x74, 0x69, 0x6e, 0x67, 0x54, 0x61, 0x62, 0x6c, 0x65, 0x12, 0x3c,
+	0x0a, 0x0c, 0x62, 0x61, 0x63, 0x6b, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x73, 0x18, 0x03,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x74, 0x61, 0x70, 0x65, 0x73, 0x74, 0x72, 0x79, 0x2e,
+	0x42, 0x61, 0x63, 0x6b, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x4d, 0x73, 0x67, 0x52, 0x0c,
+	0x62, 0x61, 0x63, 0x6b, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x73, 0x12, 0x38, 0x0a, 0x09,
+	0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x1a, 0x2e, 0x74, 0x61, 0x70, 0x65, 0x73, 0x74, 0x72, 0x79, 0x2e, 0x4c, 0x6f, 0x63, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x4d, 0x73, 0x67, 0x52, 0x09, 0x6c, 0x6f, 0x63,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x44, 0x0a, 0x0f, 0x62, 0x61, 0x63, 0x6b, 0x75, 0x70,
+	0x4c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x1a, 0x2e, 0x74, 0x61, 0x70, 0x65, 0x73, 0x74, 0x72, 0x79, 0x2e, 0x4c, 0x6f, 0x63, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x4d, 0x73, 0x67, 0x52, 0x0f, 0x62, 0x61, 0x63,
+	0x6b, 0x75, 0x70, 0x4c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x22, 0x5d, 0x0a, 0x0f,
+	0x4c, 0x69, 0x73, 0x74, 0x4b, 0x65, 0x79, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x16, 0x0a, 0x06, 0x70, 0x72, 0x65, 0x66, 0x69, 0x78, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x06, 0x70, 0x72, 0x65, 0x66, 0x69, 0x78, 0x12, 0x1c, 0x0a, 0x09, 0x70, 0x61, 0x67, 0x65, 0x54,
+	0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x70, 0x61, 0x67, 0x65,
+	0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x22, 0x43, 0x0a, 0x07, 0x4b,
+	0x65, 0x79, 0x4c, 0x69, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6b, 0x65, 0x79, 0x73, 0x18, 0x01,
+	0x20, 0x03, 0x28, 0x09, 0x52, 0x04, 0x6b, 0x65, 0x79, 0x73, 0x12, 0x24, 0x0a, 0x0d, 0x6e, 0x65,
+	0x78, 0x74, 0x50, 0x61, 0x67, 0x65, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0d, 0x6e, 0x65, 0x78, 0x74, 0x50, 0x61, 0x67, 0x65, 0x54, 0x6f, 0x6b, 0x65, 0x6e,
+	0x22, 0x16, 0x0a, 0x14, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x65, 0x64, 0x4b, 0x65, 0x79,
+	0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0xfd, 0x01, 0x0a, 0x10, 0x50, 0x75, 0x62,
+	0x6c, 0x69, 0x73, 0x68, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x4d, 0x73, 0x67, 0x12, 0x10, 0x0a,
+	0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12,
+	0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04,
+	0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x6f, 0x66,
+	0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x04, 0x73, 0x6f, 0x66, 0x74, 0x12, 0x25, 0x0a,
+	0x04, 0x72, 0x6f, 0x6f, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x74, 0x61,
+	0x70, 0x65, 0x73, 0x74, 0x72, 0x79, 0x2e, 0x4e, 0x6f, 0x64, 0x65, 0x4d, 0x73, 0x67, 0x52, 0x04,
+	0x72, 0x6f, 0x6f, 0x74, 0x12, 0x26, 0x0a, 0x0e, 0x6c, 0x61, 0x73, 0x74, 0x52, 0x65, 0x67, 0x69,
+	0x73, 0x74, 0x65, 0x72, 0x65, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0e, 0x6c, 0x61,
+	0x73, 0x74, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x65, 0x64, 0x12, 0x1a, 0x0a, 0x08,
+	0x66, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08,
+	0x66, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x73, 0x12, 0x1c, 0x0a, 0x09, 0x6c, 0x61, 0x73, 0x74,
+	0x45, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x6c, 0x61, 0x73,
+	0x74, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x20, 0x0a, 0x0b, 0x6e, 0x65, 0x78, 0x74, 0x41, 0x74,
+	0x74, 0x65, 0x6d, 0x70, 0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0b, 0x6e, 0x65, 0x78,
+	0x74, 0x41, 0x74, 0x74, 0x65, 0x6d, 0x70, 0x74, 0x22, 0x49, 0x0a, 0x0f, 0x50, 0x75, 0x62, 0x6c,
+	0x69, 0x73, 0x68, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x65, 0x73, 0x12, 0x36, 0x0a, 0x08, 0x73,
+	0x74, 0x61, 0x74, 0x75, 0x73, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1a, 0x2e,
+	0x74, 0x61, 0x70, 0x65, 0x73, 0x74, 0x72, 0x79, 0x2e, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68,
+	0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x4d, 0x73, 0x67, 0x52, 0x08, 0x73, 0x74, 0x61, 0x74, 0x75,
+	0x73, 0x65, 0x73, 0x22, 0x0e, 0x0a, 0x0c, 0x41, 0x64, 0x6d, 0x69, 0x6e, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x22, 0x0c, 0x0a, 0x0a, 0x41, 0x64, 0x6d, 0x69, 0x6e, 0x52, 0x65, 0x70, 0x6c,
+	0x79, 0x22, 0x76, 0x0a, 0x0f, 0x42, 0x61, 0x63, 0x6b, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x65, 0x72,
+	0x73, 0x4d, 0x73, 0x67, 0x12, 0x25, 0x0a, 0x04, 0x6e, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x11, 0x2e, 0x74, 0x61, 0x70, 0x65, 0x73, 0x74, 0x72, 0x79, 0x2e, 0x4e, 0x6f,
+	0x64, 0x65, 0x4d, 0x73, 0x67, 0x52, 0x04, 0x6e, 0x6f, 0x64, 0x65, 0x12, 0x3c, 0x0a, 0x0c, 0x62,
+	0x61, 0x63, 0x6b, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x18, 0x2e, 0x74, 0x61, 0x70, 0x65, 0x73, 0x74, 0x72, 0x79, 0x2e, 0x42, 0x61, 0x63,
+	0x6b, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x4d, 0x73, 0x67, 0x52, 0x0c, 0x62, 0x61, 0x63,
+	0x6b, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x73, 0x22, 0xb7, 0x01, 0x0a, 0x0e, 0x4c, 0x6f,
+	0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x4d, 0x61, 0x70, 0x4d, 0x73, 0x67, 0x12, 0x25, 0x0a, 0x04,
+	0x6e, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x74, 0x61, 0x70,
+	0x65, 0x73, 0x74, 0x72, 0x79, 0x2e, 0x4e, 0x6f, 0x64, 0x65, 0x4d, 0x73, 0x67, 0x52, 0x04, 0x6e,
+	0x6f, 0x64, 0x65, 0x12, 0x38, 0x0a, 0x09, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73,
+	0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x74, 0x61, 0x70, 0x65, 0x73, 0x74, 0x72,
+	0x79, 0x2e, 0x4c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x4d,
+	0x73, 0x67, 0x52, 0x09, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x44, 0x0a,
+	0x0f, 0x62, 0x61, 0x63, 0x6b, 0x75, 0x70, 0x4c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73,
+	0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x74, 0x61, 0x70, 0x65, 0x73, 0x74, 0x72,
+	0x79, 0x2e, 0x4c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x4d,
+	0x73, 0x67, 0x52, 0x0f, 0x62, 0x61, 0x63, 0x6b, 0x75, 0x70, 0x4c, 0x6f, 0x63, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x73, 0x22, 0x52, 0x0a, 0x0b, 0x42, 0x6c, 0x6f, 0x62, 0x49, 0x6e, 0x66, 0x6f, 0x4d,
+	0x73, 0x67, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x03, 0x6b, 0x65, 0x79, 0x12, 0x31, 0x0a, 0x08, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x74, 0x61, 0x70, 0x65, 0x73, 0x74, 0x72,
+	0x79, 0x2e, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x4d, 0x73, 0x67, 0x52, 0x08, 0x6d,
+	0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x22, 0x37, 0x0a, 0x08, 0x42, 0x6c, 0x6f, 0x62, 0x4c,
+	0x69, 0x73, 0x74, 0x12, 0x2b, 0x0a, 0x05, 0x62, 0x6c, 0x6f, 0x62, 0x73, 0x18, 0x01, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x15, 0x2e, 0x74, 0x61, 0x70, 0x65, 0x73, 0x74, 0x72, 0x79, 0x2e, 0x42, 0x6c,
+	0x6f, 0x62, 0x49, 0x6e, 0x66, 0x6f, 0x4d, 0x73, 0x67, 0x52, 0x05, 0x62, 0x6c, 0x6f, 0x62, 0x73,
+	0x22, 0x27, 0x0a, 0x0f, 0x4c, 0x6f, 0x67, 0x4c, 0x65, 0x76, 0x65, 0x6c, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x05, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x22, 0x2b, 0x0a, 0x0d, 0x4c, 0x6f, 0x67,
+	0x4c, 0x65, 0x76, 0x65, 0x6c, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x72,
+	0x65, 0x76, 0x69, 0x6f, 0x75, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x70, 0x72,
+	0x65, 0x76, 0x69, 0x6f, 0x75, 0x73, 0x32, 0x80, 0x0e, 0x0a, 0x0b, 0x54, 0x61, 0x70, 0x65, 0x73,
+	0x74, 0x72, 0x79, 0x52, 0x50, 0x43, 0x12, 0x35, 0x0a, 0x0b, 0x48, 0x65, 0x6c, 0x6c, 0x6f, 0x43,
+	0x61, 0x6c, 0x6c, 0x65, 0x72, 0x12, 0x11, 0x2e, 0x74, 0x61, 0x70, 0x65, 0x73, 0x74, 0x72, 0x79,
+	0x2e, 0x4e, 0x6f, 0x64, 0x65, 0x4d, 0x73, 0x67, 0x1a, 0x11, 0x2e, 0x74, 0x61, 0x70, 0x65, 0x73,
+	0x74, 0x72, 0x79, 0x2e, 0x4e, 0x6f, 0x64, 0x65, 0x4d, 0x73, 0x67, 0x22, 0x00, 0x12, 0x36, 0x0a,
+	0x0e, 0x46, 0x69, 0x6e, 0x64, 0x52, 0x6f, 0x6f, 0x74, 0x43, 0x61, 0x6c, 0x6c, 0x65, 0x72, 0x12,
+	0x0f, 0x2e, 0x74, 0x61, 0x70, 0x65, 0x73, 0x74, 0x72, 0x79, 0x2e, 0x49, 0x64, 0x4d, 0x73, 0x67,
+	0x1a, 0x11, 0x2e, 0x74, 0x61, 0x70, 0x65, 0x73, 0x74, 0x72, 0x79, 0x2e, 0x52, 0x6f, 0x6f, 0x74,
+	0x4d, 0x73, 0x67, 0x22, 0x00, 0x12, 0x47, 0x0a, 0x0e, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65,
+	0x72, 0x43, 0x61, 0x6c, 0x6c, 0x65, 0x72, 0x12, 0x16, 0x2e, 0x74, 0x61, 0x70, 0x65, 0x73, 0x74,
+	0x72, 0x79, 0x2e, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x1a,
+	0x1b, 0x2e, 0x74, 0x61, 0x70, 0x65, 0x73, 0x74, 0x72, 0x79, 0x2e, 0x52, 0x65, 0x67, 0x69, 0x73,
+	0x74, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x3a,
+	0x0a, 0x10, 0x55, 0x6e, 0x72, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x43, 0x61, 0x6c, 0x6c,
+	0x65, 0x72, 0x12, 0x16, 0x2e, 0x74, 0x61, 0x70, 0x65, 0x73, 0x74, 0x72, 0x79, 0x2e, 0x52, 0x65,
+	0x67, 0x69, 0x73, 0x74, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x1a, 0x0c, 0x2e, 0x74, 0x61, 0x70,
+	0x65, 0x73, 0x74, 0x72, 0x79, 0x2e, 0x4f, 0x6b, 0x22, 0x00, 0x12, 0x3a, 0x0a, 0x0b, 0x46, 0x65,
+	0x74, 0x63, 0x68, 0x43, 0x61, 0x6c, 0x6c, 0x65, 0x72, 0x12, 0x0d, 0x2e, 0x74, 0x61, 0x70, 0x65,
+	0x73, 0x74, 0x72, 0x79, 0x2e, 0x4b, 0x65, 0x79, 0x1a, 0x1a, 0x2e, 0x74, 0x61, 0x70, 0x65, 0x73,
+	0x74, 0x72, 0x79, 0x2e, 0x46, 0x65, 0x74, 0x63, 0x68, 0x65, 0x64, 0x4c, 0x6f, 0x63, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x73, 0x22, 0x00, 0x12, 0x4e, 0x0a, 0x12, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74,
+	0x65, 0x72, 0x4d, 0x61, 0x6e, 0x79, 0x43, 0x61, 0x6c, 0x6c, 0x65, 0x72, 0x12, 0x17, 0x2e, 0x74,
+	0x61, 0x70, 0x65, 0x73, 0x74, 0x72, 0x79, 0x2e, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x72, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x73, 0x1a, 0x1d, 0x2e, 0x74, 0x61, 0x70, 0x65, 0x73, 0x74, 0x72, 0x79,
+	0x2e, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x70,
+	0x6c, 0x69, 0x65, 0x73, 0x22, 0x00, 0x12, 0x43, 0x0a, 0x0f, 0x46, 0x65, 0x74, 0x63, 0x68, 0x4d,
+	0x61, 0x6e, 0x79, 0x43, 0x61, 0x6c, 0x6c, 0x65, 0x72, 0x12, 0x0e, 0x2e, 0x74, 0x61, 0x70, 0x65,
+	0x73, 0x74, 0x72, 0x79, 0x2e, 0x4b, 0x65, 0x79, 0x73, 0x1a, 0x1e, 0x2e, 0x74, 0x61, 0x70, 0x65,
+	0x73, 0x74, 0x72, 0x79, 0x2e, 0x46, 0x65, 0x74, 0x63, 0x68, 0x65, 0x64, 0x4c, 0x6f, 0x63, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x73, 0x4c, 0x69, 0x73, 0x74, 0x22, 0x00, 0x12, 0x39, 0x0a, 0x0d, 0x41,
+	0x64, 0x64, 0x4e, 0x6f, 0x64, 0x65, 0x43, 0x61, 0x6c, 0x6c, 0x65, 0x72, 0x12, 0x11, 0x2e, 0x74,
+	0x61, 0x70, 0x65, 0x73, 0x74, 0x72, 0x79, 0x2e, 0x4e, 0x6f, 0x64, 0x65, 0x4d, 0x73, 0x67, 0x1a,
+	0x13, 0x2e, 0x74, 0x61, 0x70, 0x65, 0x73, 0x74, 0x72, 0x79, 0x2e, 0x4e, 0x65, 0x69, 0x67, 0x68,
+	0x62, 0x6f, 0x72, 0x73, 0x22, 0x00, 0x12, 0x3b, 0x0a, 0x14, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65,
+	0x42, 0x61, 0x64, 0x4e, 0x6f, 0x64, 0x65, 0x73, 0x43, 0x61, 0x6c, 0x6c, 0x65, 0x72, 0x12, 0x13,
 	0x2e, 0x74, 0x61, 0x70, 0x65, 0x73, 0x74, 0x72, 0x79, 0x2e, 0x4e, 0x65, 0x69, 0x67, 0x68, 0x62,
-	0x6f, 0x72, 0x73, 0x22, 0x00, 0x12, 0x3b, 0x0a, 0x14, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x42,
-	0x61, 0x64, 0x4e, 0x6f, 0x64, 0x65, 0x73, 0x43, 0x61, 0x6c, 0x6c, 0x65, 0x72, 0x12, 0x13, 0x2e,
-	0x74, 0x61, 0x70, 0x65, 0x73, 0x74, 0x72, 0x79, 0x2e, 0x4e, 0x65, 0x69, 0x67, 0x68, 0x62, 0x6f,
-	0x72, 0x73, 0x1a, 0x0c, 0x2e, 0x74, 0x61, 0x70, 0x65, 0x73, 0x74, 0x72, 0x79, 0x2e, 0x4f, 0x6b,
-	0x22, 0x00, 0x12, 0x4b, 0x0a, 0x16, 0x41, 0x64, 0x64, 0x4e, 0x6f, 0x64, 0x65, 0x4d, 0x75, 0x6c,
-	0x74, 0x69, 0x63, 0x61, 0x73, 0x74, 0x43, 0x61, 0x6c, 0x6c, 0x65, 0x72, 0x12, 0x1a, 0x2e, 0x74,
-	0x61, 0x70, 0x65, 0x73, 0x74, 0x72, 0x79, 0x2e, 0x4d, 0x75, 0x6c, 0x74, 0x69, 0x63, 0x61, 0x73,
-	0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x13, 0x2e, 0x74, 0x61, 0x70, 0x65, 0x73,
-	0x74, 0x72, 0x79, 0x2e, 0x4e, 0x65, 0x69, 0x67, 0x68, 0x62, 0x6f, 0x72, 0x73, 0x22, 0x00, 0x12,
-	0x38, 0x0a, 0x0e, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x43, 0x61, 0x6c, 0x6c, 0x65,
-	0x72, 0x12, 0x16, 0x2e, 0x74, 0x61, 0x70, 0x65, 0x73, 0x74, 0x72, 0x79, 0x2e, 0x54, 0x72, 0x61,
-	0x6e, 0x73, 0x66, 0x65, 0x72, 0x44, 0x61, 0x74, 0x61, 0x1a, 0x0c, 0x2e, 0x74, 0x61, 0x70, 0x65,
-	0x73, 0x74, 0x72, 0x79, 0x2e, 0x4f, 0x6b, 0x22, 0x00, 0x12, 0x39, 0x0a, 0x14, 0x41, 0x64, 0x64,
-	0x42, 0x61, 0x63, 0x6b, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x43, 0x61, 0x6c, 0x6c, 0x65,
-	0x72, 0x12, 0x11, 0x2e, 0x74, 0x61, 0x70, 0x65, 0x73, 0x74, 0x72, 0x79, 0x2e, 0x4e, 0x6f, 0x64,
-	0x65, 0x4d, 0x73, 0x67, 0x1a, 0x0c, 0x2e, 0x74, 0x61, 0x70, 0x65, 0x73, 0x74, 0x72, 0x79, 0x2e,
-	0x4f, 0x6b, 0x22, 0x00, 0x12, 0x3c, 0x0a, 0x17, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x42, 0x61,
-	0x63, 0x6b, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x43, 0x61, 0x6c, 0x6c, 0x65, 0x72, 0x12,
-	0x11, 0x2e, 0x74, 0x61, 0x70, 0x65, 0x73, 0x74, 0x72, 0x79, 0x2e, 0x4e, 0x6f, 0x64, 0x65, 0x4d,
-	0x73, 0x67, 0x1a, 0x0c, 0x2e, 0x74, 0x61, 0x70, 0x65, 0x73, 0x74, 0x72, 0x79, 0x2e, 0x4f, 0x6b,
-	0x22, 0x00, 0x12, 0x4c, 0x0a, 0x15, 0x47, 0x65, 0x74, 0x42, 0x61, 0x63, 0x6b, 0x70, 0x6f, 0x69,
-	0x6e, 0x74, 0x65, 0x72, 0x73, 0x43, 0x61, 0x6c, 0x6c, 0x65, 0x72, 0x12, 0x1c, 0x2e, 0x74, 0x61,
-	0x70, 0x65, 0x73, 0x74, 0x72, 0x79, 0x2e, 0x42, 0x61, 0x63, 0x6b, 0x70, 0x6f, 0x69, 0x6e, 0x74,
-	0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x13, 0x2e, 0x74, 0x61, 0x70, 0x65,
+	0x6f, 0x72, 0x73, 0x1a, 0x0c, 0x2e, 0x74, 0x61, 0x70, 0x65, 0x73, 0x74, 0x72, 0x79, 0x2e, 0x4f,
+	0x6b, 0x22, 0x00, 0x12, 0x4b, 0x0a, 0x16, 0x41, 0x64, 0x64, 0x4e, 0x6f, 0x64, 0x65, 0x4d, 0x75,
+	0x6c, 0x74, 0x69, 0x63, 0x61, 0x73, 0x74, 0x43, 0x61, 0x6c, 0x6c, 0x65, 0x72, 0x12, 0x1a, 0x2e,
+	0x74, 0x61, 0x70, 0x65, 0x73, 0x74, 0x72, 0x79, 0x2e, 0x4d, 0x75, 0x6c, 0x74, 0x69, 0x63, 0x61,
+	0x73, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x13, 0x2e, 0x74, 0x61, 0x70, 0x65,
 	0x73, 0x74, 0x72, 0x79, 0x2e, 0x4e, 0x65, 0x69, 0x67, 0x68, 0x62, 0x6f, 0x72, 0x73, 0x22, 0x00,
-	0x12, 0x40, 0x0a, 0x11, 0x4e, 0x6f, 0x74, 0x69, 0x66, 0x79, 0x4c, 0x65, 0x61, 0x76, 0x65, 0x43,
-	0x61, 0x6c, 0x6c, 0x65, 0x72, 0x12, 0x1b, 0x2e, 0x74, 0x61, 0x70, 0x65, 0x73, 0x74, 0x72, 0x79,
-	0x2e, 0x4c, 0x65, 0x61, 0x76, 0x65, 0x4e, 0x6f, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69,
-	0x6f, 0x6e, 0x1a, 0x0c, 0x2e, 0x74, 0x61, 0x70, 0x65, 0x73, 0x74, 0x72, 0x79, 0x2e, 0x4f, 0x6b,
-	0x22, 0x00, 0x12, 0x3b, 0x0a, 0x14, 0x42, 0x6c, 0x6f, 0x62, 0x53, 0x74, 0x6f, 0x72, 0x65, 0x46,
-	0x65, 0x74, 0x63, 0x68, 0x43, 0x61, 0x6c, 0x6c, 0x65, 0x72, 0x12, 0x0d, 0x2e, 0x74, 0x61, 0x70,
-	0x65, 0x73, 0x74, 0x72, 0x79, 0x2e, 0x4b, 0x65, 0x79, 0x1a, 0x12, 0x2e, 0x74, 0x61, 0x70, 0x65,
-	0x73, 0x74, 0x72, 0x79, 0x2e, 0x44, 0x61, 0x74, 0x61, 0x42, 0x6c, 0x6f, 0x62, 0x22, 0x00, 0x12,
-	0x39, 0x0a, 0x13, 0x54, 0x61, 0x70, 0x65, 0x73, 0x74, 0x72, 0x79, 0x53, 0x74, 0x6f, 0x72, 0x65,
-	0x43, 0x61, 0x6c, 0x6c, 0x65, 0x72, 0x12, 0x12, 0x2e, 0x74, 0x61, 0x70, 0x65, 0x73, 0x74, 0x72,
-	0x79, 0x2e, 0x44, 0x61, 0x74, 0x61, 0x42, 0x6c, 0x6f, 0x62, 0x1a, 0x0c, 0x2e, 0x74, 0x61, 0x70,
-	0x65, 0x73, 0x74, 0x72, 0x79, 0x2e, 0x4f, 0x6b, 0x22, 0x00, 0x12, 0x3c, 0x0a, 0x14, 0x54, 0x61,
-	0x70, 0x65, 0x73, 0x74, 0x72, 0x79, 0x4c, 0x6f, 0x6f, 0x6b, 0x75, 0x70, 0x43, 0x61, 0x6c, 0x6c,
-	0x65, 0x72, 0x12, 0x0d, 0x2e, 0x74, 0x61, 0x70, 0x65, 0x73, 0x74, 0x72, 0x79, 0x2e, 0x4b, 0x65,
-	0x79, 0x1a, 0x13, 0x2e, 0x74, 0x61, 0x70, 0x65, 0x73, 0x74, 0x72, 0x79, 0x2e, 0x4e, 0x65, 0x69,
-	0x67, 0x68, 0x62, 0x6f, 0x72, 0x73, 0x22, 0x00, 0x42, 0x09, 0x5a, 0x07, 0x70, 0x6b, 0x67, 0x2f,
-	0x70, 0x6b, 0x67, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x12, 0x38, 0x0a, 0x0e, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x43, 0x61, 0x6c, 0x6c,
+	0x65, 0x72, 0x12, 0x16, 0x2e, 0x74, 0x61, 0x70, 0x65, 0x73, 0x74, 0x72, 0x79, 0x2e, 0x54, 0x72,
+	0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x44, 0x61, 0x74, 0x61, 0x1a, 0x0c, 0x2e, 0x74, 0x61, 0x70,
+	0x65, 0x73, 0x74, 0x72, 0x79, 0x2e, 0x4f, 0x6b, 0x22, 0x00, 0x12, 0x34, 0x0a, 0x0c, 0x4d, 0x69,
+	0x72, 0x72, 0x6f, 0x72, 0x43, 0x61, 0x6c, 0x6c, 0x65, 0x72, 0x12, 0x14, 0x2e, 0x74, 0x61, 0x70,
+	0x65, 0x73, 0x74, 0x72, 0x79, 0x2e, 0x4d, 0x69, 0x72, 0x72, 0x6f, 0x72, 0x44, 0x61, 0x74, 0x61,
+	0x1a, 0x0c, 0x2e, 0x74, 0x61, 0x70, 0x65, 0x73, 0x74, 0x72, 0x79, 0x2e, 0x4f, 0x6b, 0x22, 0x00,
+	0x12, 0x3e, 0x0a, 0x0d, 0x53, 0x75, 0x6d, 0x6d, 0x61, 0x72, 0x79, 0x43, 0x61, 0x6c, 0x6c, 0x65,
+	0x72, 0x12, 0x18, 0x2e, 0x74, 0x61, 0x70, 0x65, 0x73, 0x74, 0x72, 0x79, 0x2e, 0x53, 0x75, 0x6d,
+	0x6d, 0x61, 0x72, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x11, 0x2e, 0x74, 0x61,
+	0x70, 0x65, 0x73, 0x74, 0x72, 0x79, 0x2e, 0x53, 0x75, 0x6d, 0x6d, 0x61, 0x72, 0x79, 0x22, 0x00,
+	0x12, 0x39, 0x0a, 0x14, 0x41, 0x64, 0x64, 0x42, 0x61, 0x63, 0x6b, 0x70, 0x6f, 0x69, 0x6e, 0x74,
+	0x65, 0x72, 0x43, 0x61, 0x6c, 0x6c, 0x65, 0x72, 0x12, 0x11, 0x2e, 0x74, 0x61, 0x70, 0x65, 0x73,
+	0x74, 0x72, 0x79, 0x2e, 0x4e, 0x6f, 0x64, 0x65, 0x4d, 0x73, 0x67, 0x1a, 0x0c, 0x2e, 0x74, 0x61,
+	0x70, 0x65, 0x73, 0x74, 0x72, 0x79, 0x2e, 0x4f, 0x6b, 0x22, 0x00, 0x12, 0x3c, 0x0a, 0x17, 0x52,
+	0x65, 0x6d, 0x6f, 0x76, 0x65, 0x42, 0x61, 0x63, 0x6b, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x65, 0x72,
+	0x43, 0x61, 0x6c, 0x6c, 0x65, 0x72, 0x12, 0x11, 0x2e, 0x74, 0x61, 0x70, 0x65, 0x73, 0x74, 0x72,
+	0x79, 0x2e, 0x4e, 0x6f, 0x64, 0x65, 0x4d, 0x73, 0x67, 0x1a, 0x0c, 0x2e, 0x74, 0x61, 0x70, 0x65,
+	0x73, 0x74, 0x72, 0x79, 0x2e, 0x4f, 0x6b, 0x22, 0x00, 0x12, 0x4c, 0x0a, 0x15, 0x47, 0x65, 0x74,
+	0x42, 0x61, 0x63, 0x6b, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x73, 0x43, 0x61, 0x6c, 0x6c,
+	0x65, 0x72, 0x12, 0x1c, 0x2e, 0x74, 0x61, 0x70, 0x65, 0x73, 0x74, 0x72, 0x79, 0x2e, 0x42, 0x61,
+	0x63, 0x6b, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x13, 0x2e, 0x74, 0x61, 0x70, 0x65, 0x73, 0x74, 0x72, 0x79, 0x2e, 0x4e, 0x65, 0x69, 0x67,
+	0x68, 0x62, 0x6f, 0x72, 0x73, 0x22, 0x00, 0x12, 0x40, 0x0a, 0x11, 0x4e, 0x6f, 0x74, 0x69, 0x66,
+	0x79, 0x4c, 0x65, 0x61, 0x76, 0x65, 0x43, 0x61, 0x6c, 0x6c, 0x65, 0x72, 0x12, 0x1b, 0x2e, 0x74,
+	0x61, 0x70, 0x65, 0x73, 0x74, 0x72, 0x79, 0x2e, 0x4c, 0x65, 0x61, 0x76, 0x65, 0x4e, 0x6f, 0x74,
+	0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x1a, 0x0c, 0x2e, 0x74, 0x61, 0x70, 0x65,
+	0x73, 0x74, 0x72, 0x79, 0x2e, 0x4f, 0x6b, 0x22, 0x00, 0x12, 0x3b, 0x0a, 0x14, 0x42, 0x6c, 0x6f,
+	0x62, 0x53, 0x74, 0x6f, 0x72, 0x65, 0x46, 0x65, 0x74, 0x63, 0x68, 0x43, 0x61, 0x6c, 0x6c, 0x65,
+	0x72, 0x12, 0x0d, 0x2e, 0x74, 0x61, 0x70, 0x65, 0x73, 0x74, 0x72, 0x79, 0x2e, 0x4b, 0x65, 0x79,
+	0x1a, 0x12, 0x2e, 0x74, 0x61, 0x70, 0x65, 0x73, 0x74, 0x72, 0x79, 0x2e, 0x44, 0x61, 0x74, 0x61,
+	0x42, 0x6c, 0x6f, 0x62, 0x22, 0x00, 0x12, 0x3d, 0x0a, 0x13, 0x42, 0x6c, 0x6f, 0x62, 0x53, 0x74,
+	0x6f, 0x72, 0x65, 0x53, 0x74, 0x61, 0x74, 0x43, 0x61, 0x6c, 0x6c, 0x65, 0x72, 0x12, 0x0d, 0x2e,
+	0x74, 0x61, 0x70, 0x65, 0x73, 0x74, 0x72, 0x79, 0x2e, 0x4b, 0x65, 0x79, 0x1a, 0x15, 0x2e, 0x74,
+	0x61, 0x70, 0x65, 0x73, 0x74, 0x72, 0x79, 0x2e, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61,
+	0x4d, 0x73, 0x67, 0x22, 0x00, 0x12, 0x41, 0x0a, 0x13, 0x54, 0x61, 0x70, 0x65, 0x73, 0x74, 0x72,
+	0x79, 0x53, 0x74, 0x6f, 0x72, 0x65, 0x43, 0x61, 0x6c, 0x6c, 0x65, 0x72, 0x12, 0x12, 0x2e, 0x74,
+	0x61, 0x70, 0x65, 0x73, 0x74, 0x72, 0x79, 0x2e, 0x44, 0x61, 0x74, 0x61, 0x42, 0x6c, 0x6f, 0x62,
+	0x1a, 0x14, 0x2e, 0x74, 0x61, 0x70, 0x65, 0x73, 0x74, 0x72, 0x79, 0x2e, 0x53, 0x74, 0x6f, 0x72,
+	0x65, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x3b, 0x0a, 0x14, 0x54, 0x61, 0x70, 0x65,
+	0x73, 0x74, 0x72, 0x79, 0x4c, 0x6f, 0x6f, 0x6b, 0x75, 0x70, 0x43, 0x61, 0x6c, 0x6c, 0x65, 0x72,
+	0x12, 0x0d, 0x2e, 0x74, 0x61, 0x70, 0x65, 0x73, 0x74, 0x72, 0x79, 0x2e, 0x4b, 0x65, 0x79, 0x1a,
+	0x12, 0x2e, 0x74, 0x61, 0x70, 0x65, 0x73, 0x74, 0x72, 0x79, 0x2e, 0x52, 0x65, 0x70, 0x6c, 0x69,
+	0x63, 0x61, 0x73, 0x22, 0x00, 0x12, 0x48, 0x0a, 0x17, 0x54, 0x61, 0x70, 0x65, 0x73, 0x74, 0x72,
+	0x79, 0x53, 0x74, 0x6f, 0x72, 0x65, 0x4d, 0x61, 0x6e, 0x79, 0x43, 0x61, 0x6c, 0x6c, 0x65, 0x72,
+	0x12, 0x13, 0x2e, 0x74, 0x61, 0x70, 0x65, 0x73, 0x74, 0x72, 0x79, 0x2e, 0x44, 0x61, 0x74, 0x61,
+	0x42, 0x6c, 0x6f, 0x62, 0x73, 0x1a, 0x16, 0x2e, 0x74, 0x61, 0x70, 0x65, 0x73, 0x74, 0x72, 0x79,
+	0x2e, 0x53, 0x74, 0x6f, 0x72, 0x65, 0x52, 0x65, 0x70, 0x6c, 0x69, 0x65, 0x73, 0x22, 0x00, 0x12,
+	0x45, 0x0a, 0x18, 0x54, 0x61, 0x70, 0x65, 0x73, 0x74, 0x72, 0x79, 0x4c, 0x6f, 0x6f, 0x6b, 0x75,
+	0x70, 0x4d, 0x61, 0x6e, 0x79, 0x43, 0x61, 0x6c, 0x6c, 0x65, 0x72, 0x12, 0x0e, 0x2e, 0x74, 0x61,
+	0x70, 0x65, 0x73, 0x74, 0x72, 0x79, 0x2e, 0x4b, 0x65, 0x79, 0x73, 0x1a, 0x17, 0x2e, 0x74, 0x61,
+	0x70, 0x65, 0x73, 0x74, 0x72, 0x79, 0x2e, 0x52, 0x65, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x73, 0x42,
+	0x79, 0x4b, 0x65, 0x79, 0x22, 0x00, 0x12, 0x47, 0x0a, 0x15, 0x47, 0x65, 0x74, 0x52, 0x6f, 0x75,
+	0x74, 0x69, 0x6e, 0x67, 0x54, 0x61, 0x62, 0x6c, 0x65, 0x43, 0x61, 0x6c, 0x6c, 0x65, 0x72, 0x12,
+	0x11, 0x2e, 0x74, 0x61, 0x70, 0x65, 0x73, 0x74, 0x72, 0x79, 0x2e, 0x4e, 0x6f, 0x64, 0x65, 0x4d,
+	0x73, 0x67, 0x1a, 0x19, 0x2e, 0x74, 0x61, 0x70, 0x65, 0x73, 0x74, 0x72, 0x79, 0x2e, 0x52, 0x6f,
+	0x75, 0x74, 0x69, 0x6e, 0x67, 0x54, 0x61, 0x62, 0x6c, 0x65, 0x4d, 0x73, 0x67, 0x22, 0x00, 0x12,
+	0x42, 0x0a, 0x0e, 0x47, 0x65, 0x74, 0x53, 0x74, 0x61, 0x74, 0x65, 0x43, 0x61, 0x6c, 0x6c, 0x65,
+	0x72, 0x12, 0x16, 0x2e, 0x74, 0x61, 0x70, 0x65, 0x73, 0x74, 0x72, 0x79, 0x2e, 0x53, 0x74, 0x61,
+	0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x74, 0x61, 0x70, 0x65,
+	0x73, 0x74, 0x72, 0x79, 0x2e, 0x4e, 0x6f, 0x64, 0x65, 0x53, 0x74, 0x61, 0x74, 0x65, 0x4d, 0x73,
+	0x67, 0x22, 0x00, 0x12, 0x40, 0x0a, 0x0e, 0x4c, 0x69, 0x73, 0x74, 0x4b, 0x65, 0x79, 0x73, 0x43,
+	0x61, 0x6c, 0x6c, 0x65, 0x72, 0x12, 0x19, 0x2e, 0x74, 0x61, 0x70, 0x65, 0x73, 0x74, 0x72, 0x79,
+	0x2e, 0x4c, 0x69, 0x73, 0x74, 0x4b, 0x65, 0x79, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x11, 0x2e, 0x74, 0x61, 0x70, 0x65, 0x73, 0x74, 0x72, 0x79, 0x2e, 0x4b, 0x65, 0x79, 0x4c,
+	0x69, 0x73, 0x74, 0x22, 0x00, 0x12, 0x52, 0x0a, 0x13, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68,
+	0x65, 0x64, 0x4b, 0x65, 0x79, 0x73, 0x43, 0x61, 0x6c, 0x6c, 0x65, 0x72, 0x12, 0x1e, 0x2e, 0x74,
+	0x61, 0x70, 0x65, 0x73, 0x74, 0x72, 0x79, 0x2e, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x65,
+	0x64, 0x4b, 0x65, 0x79, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e, 0x74,
+	0x61, 0x70, 0x65, 0x73, 0x74, 0x72, 0x79, 0x2e, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x53,
+	0x74, 0x61, 0x74, 0x75, 0x73, 0x65, 0x73, 0x22, 0x00, 0x32, 0xd7, 0x03, 0x0a, 0x0d, 0x54, 0x61,
+	0x70, 0x65, 0x73, 0x74, 0x72, 0x79, 0x41, 0x64, 0x6d, 0x69, 0x6e, 0x12, 0x46, 0x0a, 0x0f, 0x47,
+	0x65, 0x74, 0x52, 0x6f, 0x75, 0x74, 0x69, 0x6e, 0x67, 0x54, 0x61, 0x62, 0x6c, 0x65, 0x12, 0x16,
+	0x2e, 0x74, 0x61, 0x70, 0x65, 0x73, 0x74, 0x72, 0x79, 0x2e, 0x41, 0x64, 0x6d, 0x69, 0x6e, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e, 0x74, 0x61, 0x70, 0x65, 0x73, 0x74, 0x72,
+	0x79, 0x2e, 0x52, 0x6f, 0x75, 0x74, 0x69, 0x6e, 0x67, 0x54, 0x61, 0x62, 0x6c, 0x65, 0x4d, 0x73,
+	0x67, 0x22, 0x00, 0x12, 0x46, 0x0a, 0x0f, 0x47, 0x65, 0x74, 0x42, 0x61, 0x63, 0x6b, 0x70, 0x6f,
+	0x69, 0x6e, 0x74, 0x65, 0x72, 0x73, 0x12, 0x16, 0x2e, 0x74, 0x61, 0x70, 0x65, 0x73, 0x74, 0x72,
+	0x79, 0x2e, 0x41, 0x64, 0x6d, 0x69, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x19,
+	0x2e, 0x74, 0x61, 0x70, 0x65, 0x73, 0x74, 0x72, 0x79, 0x2e, 0x42, 0x61, 0x63, 0x6b, 0x70, 0x6f,
+	0x69, 0x6e, 0x74, 0x65, 0x72, 0x73, 0x4d, 0x73, 0x67, 0x22, 0x00, 0x12, 0x44, 0x0a, 0x0e, 0x47,
+	0x65, 0x74, 0x4c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x4d, 0x61, 0x70, 0x12, 0x16, 0x2e,
+	0x74, 0x61, 0x70, 0x65, 0x73, 0x74, 0x72, 0x79, 0x2e, 0x41, 0x64, 0x6d, 0x69, 0x6e, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x18, 0x2e, 0x74, 0x61, 0x70, 0x65, 0x73, 0x74, 0x72, 0x79,
+	0x2e, 0x4c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x4d, 0x61, 0x70, 0x4d, 0x73, 0x67, 0x22,
+	0x00, 0x12, 0x39, 0x0a, 0x09, 0x4c, 0x69, 0x73, 0x74, 0x42, 0x6c, 0x6f, 0x62, 0x73, 0x12, 0x16,
+	0x2e, 0x74, 0x61, 0x70, 0x65, 0x73, 0x74, 0x72, 0x79, 0x2e, 0x41, 0x64, 0x6d, 0x69, 0x6e, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x12, 0x2e, 0x74, 0x61, 0x70, 0x65, 0x73, 0x74, 0x72,
+	0x79, 0x2e, 0x42, 0x6c, 0x6f, 0x62, 0x4c, 0x69, 0x73, 0x74, 0x22, 0x00, 0x12, 0x43, 0x0a, 0x0b,
+	0x53, 0x65, 0x74, 0x4c, 0x6f, 0x67, 0x4c, 0x65, 0x76, 0x65, 0x6c, 0x12, 0x19, 0x2e, 0x74, 0x61,
+	0x70, 0x65, 0x73, 0x74, 0x72, 0x79, 0x2e, 0x4c, 0x6f, 0x67, 0x4c, 0x65, 0x76, 0x65, 0x6c, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x17, 0x2e, 0x74, 0x61, 0x70, 0x65, 0x73, 0x74, 0x72,
+	0x79, 0x2e, 0x4c, 0x6f, 0x67, 0x4c, 0x65, 0x76, 0x65, 0x6c, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22,
+	0x00, 0x12, 0x37, 0x0a, 0x05, 0x4c, 0x65, 0x61, 0x76, 0x65, 0x12, 0x16, 0x2e, 0x74, 0x61, 0x70,
+	0x65, 0x73, 0x74, 0x72, 0x79, 0x2e, 0x41, 0x64, 0x6d, 0x69, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x14, 0x2e, 0x74, 0x61, 0x70, 0x65, 0x73, 0x74, 0x72, 0x79, 0x2e, 0x41, 0x64,
+	0x6d, 0x69, 0x6e, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x37, 0x0a, 0x05, 0x44, 0x72,
+	0x61, 0x69, 0x6e, 0x12, 0x16, 0x2e, 0x74, 0x61, 0x70, 0x65, 0x73, 0x74, 0x72, 0x79, 0x2e, 0x41,
+	0x64, 0x6d, 0x69, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x14, 0x2e, 0x74, 0x61,
+	0x70, 0x65, 0x73, 0x74, 0x72, 0x79, 0x2e, 0x41, 0x64, 0x6d, 0x69, 0x6e, 0x52, 0x65, 0x70, 0x6c,
+	0x79, 0x22, 0x00, 0x42, 0x09, 0x5a, 0x07, 0x70, 0x6b, 0x67, 0x2f, 0x70, 0x6b, 0x67, 0x62, 0x06,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -860,71 +3223,181 @@ func file_pkg_tapestry_rpc_proto_rawDescGZIP() []byte {
 	return file_pkg_tapestry_rpc_proto_rawDescData
 }
 
-var file_pkg_tapestry_rpc_proto_msgTypes = make([]protoimpl.MessageInfo, 14)
+var file_pkg_tapestry_rpc_proto_msgTypes = make([]protoimpl.MessageInfo, 52)
 var file_pkg_tapestry_rpc_proto_goTypes = []interface{}{
-	(*Ok)(nil),                 // 0: tapestry.Ok
-	(*IdMsg)(nil),              // 1: tapestry.IdMsg
-	(*DataBlob)(nil),           // 2: tapestry.DataBlob
-	(*Key)(nil),                // 3: tapestry.Key
-	(*NodeMsg)(nil),            // 4: tapestry.NodeMsg
-	(*RootMsg)(nil),            // 5: tapestry.RootMsg
-	(*Registration)(nil),       // 6: tapestry.Registration
-	(*FetchedLocations)(nil),   // 7: tapestry.FetchedLocations
-	(*Neighbors)(nil),          // 8: tapestry.Neighbors
-	(*MulticastRequest)(nil),   // 9: tapestry.MulticastRequest
-	(*TransferData)(nil),       // 10: tapestry.TransferData
-	(*BackpointerRequest)(nil), // 11: tapestry.BackpointerRequest
-	(*LeaveNotification)(nil),  // 12: tapestry.LeaveNotification
-	nil,                        // 13: tapestry.TransferData.DataEntry
+	(*Ok)(nil),                   // 0: tapestry.Ok
+	(*IdMsg)(nil),                // 1: tapestry.IdMsg
+	(*DataBlob)(nil),             // 2: tapestry.DataBlob
+	(*StoreReply)(nil),           // 3: tapestry.StoreReply
+	(*DataBlobs)(nil),            // 4: tapestry.DataBlobs
+	(*StoreReplies)(nil),         // 5: tapestry.StoreReplies
+	(*MetadataMsg)(nil),          // 6: tapestry.MetadataMsg
+	(*Key)(nil),                  // 7: tapestry.Key
+	(*Keys)(nil),                 // 8: tapestry.Keys
+	(*NodeMsg)(nil),              // 9: tapestry.NodeMsg
+	(*RootMsg)(nil),              // 10: tapestry.RootMsg
+	(*Registration)(nil),         // 11: tapestry.Registration
+	(*RegistrationReply)(nil),    // 12: tapestry.RegistrationReply
+	(*Registrations)(nil),        // 13: tapestry.Registrations
+	(*RegistrationReplies)(nil),  // 14: tapestry.RegistrationReplies
+	(*ReplicaMsg)(nil),           // 15: tapestry.ReplicaMsg
+	(*Replicas)(nil),             // 16: tapestry.Replicas
+	(*ReplicasByKey)(nil),        // 17: tapestry.ReplicasByKey
+	(*FetchedLocations)(nil),     // 18: tapestry.FetchedLocations
+	(*FetchedLocationsList)(nil), // 19: tapestry.FetchedLocationsList
+	(*Neighbors)(nil),            // 20: tapestry.Neighbors
+	(*MulticastRequest)(nil),     // 21: tapestry.MulticastRequest
+	(*TransferData)(nil),         // 22: tapestry.TransferData
+	(*MirrorEntry)(nil),          // 23: tapestry.MirrorEntry
+	(*MirrorData)(nil),           // 24: tapestry.MirrorData
+	(*SummaryRequest)(nil),       // 25: tapestry.SummaryRequest
+	(*Summary)(nil),              // 26: tapestry.Summary
+	(*BackpointerRequest)(nil),   // 27: tapestry.BackpointerRequest
+	(*LeaveNotification)(nil),    // 28: tapestry.LeaveNotification
+	(*RoutingEntryMsg)(nil),      // 29: tapestry.RoutingEntryMsg
+	(*RoutingTableMsg)(nil),      // 30: tapestry.RoutingTableMsg
+	(*StateRequest)(nil),         // 31: tapestry.StateRequest
+	(*BackpointerMsg)(nil),       // 32: tapestry.BackpointerMsg
+	(*LocationEntryMsg)(nil),     // 33: tapestry.LocationEntryMsg
+	(*NodeStateMsg)(nil),         // 34: tapestry.NodeStateMsg
+	(*ListKeysRequest)(nil),      // 35: tapestry.ListKeysRequest
+	(*KeyList)(nil),              // 36: tapestry.KeyList
+	(*PublishedKeysRequest)(nil), // 37: tapestry.PublishedKeysRequest
+	(*PublishStatusMsg)(nil),     // 38: tapestry.PublishStatusMsg
+	(*PublishStatuses)(nil),      // 39: tapestry.PublishStatuses
+	(*AdminRequest)(nil),         // 40: tapestry.AdminRequest
+	(*AdminReply)(nil),           // 41: tapestry.AdminReply
+	(*BackpointersMsg)(nil),      // 42: tapestry.BackpointersMsg
+	(*LocationMapMsg)(nil),       // 43: tapestry.LocationMapMsg
+	(*BlobInfoMsg)(nil),          // 44: tapestry.BlobInfoMsg
+	(*BlobList)(nil),             // 45: tapestry.BlobList
+	(*LogLevelRequest)(nil),      // 46: tapestry.LogLevelRequest
+	(*LogLevelReply)(nil),        // 47: tapestry.LogLevelReply
+	nil,                          // 48: tapestry.MetadataMsg.TagsEntry
+	nil,                          // 49: tapestry.ReplicasByKey.ReplicasEntry
+	nil,                          // 50: tapestry.ReplicasByKey.ErrorsEntry
+	nil,                          // 51: tapestry.TransferData.DataEntry
 }
 var file_pkg_tapestry_rpc_proto_depIdxs = []int32{
-	4,  // 0: tapestry.RootMsg.next:type_name -> tapestry.NodeMsg
-	4,  // 1: tapestry.RootMsg.toRemove:type_name -> tapestry.NodeMsg
-	4,  // 2: tapestry.Registration.fromNode:type_name -> tapestry.NodeMsg
-	4,  // 3: tapestry.FetchedLocations.values:type_name -> tapestry.NodeMsg
-	4,  // 4: tapestry.Neighbors.neighbors:type_name -> tapestry.NodeMsg
-	4,  // 5: tapestry.MulticastRequest.newNode:type_name -> tapestry.NodeMsg
-	4,  // 6: tapestry.TransferData.from:type_name -> tapestry.NodeMsg
-	13, // 7: tapestry.TransferData.data:type_name -> tapestry.TransferData.DataEntry
-	4,  // 8: tapestry.BackpointerRequest.from:type_name -> tapestry.NodeMsg
-	4,  // 9: tapestry.LeaveNotification.from:type_name -> tapestry.NodeMsg
-	4,  // 10: tapestry.LeaveNotification.replacement:type_name -> tapestry.NodeMsg
-	8,  // 11: tapestry.TransferData.DataEntry.value:type_name -> tapestry.Neighbors
-	4,  // 12: tapestry.TapestryRPC.HelloCaller:input_type -> tapestry.NodeMsg
-	1,  // 13: tapestry.TapestryRPC.FindRootCaller:input_type -> tapestry.IdMsg
-	6,  // 14: tapestry.TapestryRPC.RegisterCaller:input_type -> tapestry.Registration
-	3,  // 15: tapestry.TapestryRPC.FetchCaller:input_type -> tapestry.Key
-	4,  // 16: tapestry.TapestryRPC.AddNodeCaller:input_type -> tapestry.NodeMsg
-	8,  // 17: tapestry.TapestryRPC.RemoveBadNodesCaller:input_type -> tapestry.Neighbors
-	9,  // 18: tapestry.TapestryRPC.AddNodeMulticastCaller:input_type -> tapestry.MulticastRequest
-	10, // 19: tapestry.TapestryRPC.TransferCaller:input_type -> tapestry.TransferData
-	4,  // 20: tapestry.TapestryRPC.AddBackpointerCaller:input_type -> tapestry.NodeMsg
-	4,  // 21: tapestry.TapestryRPC.RemoveBackpointerCaller:input_type -> tapestry.NodeMsg
-	11, // 22: tapestry.TapestryRPC.GetBackpointersCaller:input_type -> tapestry.BackpointerRequest
-	12, // 23: tapestry.TapestryRPC.NotifyLeaveCaller:input_type -> tapestry.LeaveNotification
-	3,  // 24: tapestry.TapestryRPC.BlobStoreFetchCaller:input_type -> tapestry.Key
-	2,  // 25: tapestry.TapestryRPC.TapestryStoreCaller:input_type -> tapestry.DataBlob
-	3,  // 26: tapestry.TapestryRPC.TapestryLookupCaller:input_type -> tapestry.Key
-	4,  // 27: tapestry.TapestryRPC.HelloCaller:output_type -> tapestry.NodeMsg
-	5,  // 28: tapestry.TapestryRPC.FindRootCaller:output_type -> tapestry.RootMsg
-	0,  // 29: tapestry.TapestryRPC.RegisterCaller:output_type -> tapestry.Ok
-	7,  // 30: tapestry.TapestryRPC.FetchCaller:output_type -> tapestry.FetchedLocations
-	8,  // 31: tapestry.TapestryRPC.AddNodeCaller:output_type -> tapestry.Neighbors
-	0,  // 32: tapestry.TapestryRPC.RemoveBadNodesCaller:output_type -> tapestry.Ok
-	8,  // 33: tapestry.TapestryRPC.AddNodeMulticastCaller:output_type -> tapestry.Neighbors
-	0,  // 34: tapestry.TapestryRPC.TransferCaller:output_type -> tapestry.Ok
-	0,  // 35: tapestry.TapestryRPC.AddBackpointerCaller:output_type -> tapestry.Ok
-	0,  // 36: tapestry.TapestryRPC.RemoveBackpointerCaller:output_type -> tapestry.Ok
-	8,  // 37: tapestry.TapestryRPC.GetBackpointersCaller:output_type -> tapestry.Neighbors
-	0,  // 38: tapestry.TapestryRPC.NotifyLeaveCaller:output_type -> tapestry.Ok
-	2,  // 39: tapestry.TapestryRPC.BlobStoreFetchCaller:output_type -> tapestry.DataBlob
-	0,  // 40: tapestry.TapestryRPC.TapestryStoreCaller:output_type -> tapestry.Ok
-	8,  // 41: tapestry.TapestryRPC.TapestryLookupCaller:output_type -> tapestry.Neighbors
-	27, // [27:42] is the sub-list for method output_type
-	12, // [12:27] is the sub-list for method input_type
-	12, // [12:12] is the sub-list for extension type_name
-	12, // [12:12] is the sub-list for extension extendee
-	0,  // [0:12] is the sub-list for field type_name
+	6,  // 0: tapestry.DataBlob.metadata:type_name -> tapestry.MetadataMsg
+	2,  // 1: tapestry.DataBlobs.blobs:type_name -> tapestry.DataBlob
+	3,  // 2: tapestry.StoreReplies.replies:type_name -> tapestry.StoreReply
+	48, // 3: tapestry.MetadataMsg.tags:type_name -> tapestry.MetadataMsg.TagsEntry
+	9,  // 4: tapestry.RootMsg.next:type_name -> tapestry.NodeMsg
+	9,  // 5: tapestry.RootMsg.toRemove:type_name -> tapestry.NodeMsg
+	9,  // 6: tapestry.Registration.fromNode:type_name -> tapestry.NodeMsg
+	11, // 7: tapestry.Registrations.registrations:type_name -> tapestry.Registration
+	12, // 8: tapestry.RegistrationReplies.replies:type_name -> tapestry.RegistrationReply
+	9,  // 9: tapestry.ReplicaMsg.node:type_name -> tapestry.NodeMsg
+	15, // 10: tapestry.Replicas.replicas:type_name -> tapestry.ReplicaMsg
+	49, // 11: tapestry.ReplicasByKey.replicas:type_name -> tapestry.ReplicasByKey.ReplicasEntry
+	50, // 12: tapestry.ReplicasByKey.errors:type_name -> tapestry.ReplicasByKey.ErrorsEntry
+	15, // 13: tapestry.FetchedLocations.replicas:type_name -> tapestry.ReplicaMsg
+	18, // 14: tapestry.FetchedLocationsList.locations:type_name -> tapestry.FetchedLocations
+	9,  // 15: tapestry.Neighbors.neighbors:type_name -> tapestry.NodeMsg
+	9,  // 16: tapestry.MulticastRequest.newNode:type_name -> tapestry.NodeMsg
+	9,  // 17: tapestry.TransferData.from:type_name -> tapestry.NodeMsg
+	51, // 18: tapestry.TransferData.data:type_name -> tapestry.TransferData.DataEntry
+	15, // 19: tapestry.MirrorEntry.replica:type_name -> tapestry.ReplicaMsg
+	9,  // 20: tapestry.MirrorData.from:type_name -> tapestry.NodeMsg
+	23, // 21: tapestry.MirrorData.entries:type_name -> tapestry.MirrorEntry
+	9,  // 22: tapestry.BackpointerRequest.from:type_name -> tapestry.NodeMsg
+	9,  // 23: tapestry.LeaveNotification.from:type_name -> tapestry.NodeMsg
+	9,  // 24: tapestry.LeaveNotification.replacement:type_name -> tapestry.NodeMsg
+	9,  // 25: tapestry.RoutingEntryMsg.node:type_name -> tapestry.NodeMsg
+	9,  // 26: tapestry.RoutingTableMsg.node:type_name -> tapestry.NodeMsg
+	29, // 27: tapestry.RoutingTableMsg.entries:type_name -> tapestry.RoutingEntryMsg
+	9,  // 28: tapestry.BackpointerMsg.node:type_name -> tapestry.NodeMsg
+	15, // 29: tapestry.LocationEntryMsg.replicas:type_name -> tapestry.ReplicaMsg
+	9,  // 30: tapestry.NodeStateMsg.node:type_name -> tapestry.NodeMsg
+	29, // 31: tapestry.NodeStateMsg.routingTable:type_name -> tapestry.RoutingEntryMsg
+	32, // 32: tapestry.NodeStateMsg.backpointers:type_name -> tapestry.BackpointerMsg
+	33, // 33: tapestry.NodeStateMsg.locations:type_name -> tapestry.LocationEntryMsg
+	33, // 34: tapestry.NodeStateMsg.backupLocations:type_name -> tapestry.LocationEntryMsg
+	9,  // 35: tapestry.PublishStatusMsg.root:type_name -> tapestry.NodeMsg
+	38, // 36: tapestry.PublishStatuses.statuses:type_name -> tapestry.PublishStatusMsg
+	9,  // 37: tapestry.BackpointersMsg.node:type_name -> tapestry.NodeMsg
+	32, // 38: tapestry.BackpointersMsg.backpointers:type_name -> tapestry.BackpointerMsg
+	9,  // 39: tapestry.LocationMapMsg.node:type_name -> tapestry.NodeMsg
+	33, // 40: tapestry.LocationMapMsg.locations:type_name -> tapestry.LocationEntryMsg
+	33, // 41: tapestry.LocationMapMsg.backupLocations:type_name -> tapestry.LocationEntryMsg
+	6,  // 42: tapestry.BlobInfoMsg.metadata:type_name -> tapestry.MetadataMsg
+	44, // 43: tapestry.BlobList.blobs:type_name -> tapestry.BlobInfoMsg
+	16, // 44: tapestry.ReplicasByKey.ReplicasEntry.value:type_name -> tapestry.Replicas
+	16, // 45: tapestry.TransferData.DataEntry.value:type_name -> tapestry.Replicas
+	9,  // 46: tapestry.TapestryRPC.HelloCaller:input_type -> tapestry.NodeMsg
+	1,  // 47: tapestry.TapestryRPC.FindRootCaller:input_type -> tapestry.IdMsg
+	11, // 48: tapestry.TapestryRPC.RegisterCaller:input_type -> tapestry.Registration
+	11, // 49: tapestry.TapestryRPC.UnregisterCaller:input_type -> tapestry.Registration
+	7,  // 50: tapestry.TapestryRPC.FetchCaller:input_type -> tapestry.Key
+	13, // 51: tapestry.TapestryRPC.RegisterManyCaller:input_type -> tapestry.Registrations
+	8,  // 52: tapestry.TapestryRPC.FetchManyCaller:input_type -> tapestry.Keys
+	9,  // 53: tapestry.TapestryRPC.AddNodeCaller:input_type -> tapestry.NodeMsg
+	20, // 54: tapestry.TapestryRPC.RemoveBadNodesCaller:input_type -> tapestry.Neighbors
+	21, // 55: tapestry.TapestryRPC.AddNodeMulticastCaller:input_type -> tapestry.MulticastRequest
+	22, // 56: tapestry.TapestryRPC.TransferCaller:input_type -> tapestry.TransferData
+	24, // 57: tapestry.TapestryRPC.MirrorCaller:input_type -> tapestry.MirrorData
+	25, // 58: tapestry.TapestryRPC.SummaryCaller:input_type -> tapestry.SummaryRequest
+	9,  // 59: tapestry.TapestryRPC.AddBackpointerCaller:input_type -> tapestry.NodeMsg
+	9,  // 60: tapestry.TapestryRPC.RemoveBackpointerCaller:input_type -> tapestry.NodeMsg
+	27, // 61: tapestry.TapestryRPC.GetBackpointersCaller:input_type -> tapestry.BackpointerRequest
+	28, // 62: tapestry.TapestryRPC.NotifyLeaveCaller:input_type -> tapestry.LeaveNotification
+	7,  // 63: tapestry.TapestryRPC.BlobStoreFetchCaller:input_type -> tapestry.Key
+	7,  // 64: tapestry.TapestryRPC.BlobStoreStatCaller:input_type -> tapestry.Key
+	2,  // 65: tapestry.TapestryRPC.TapestryStoreCaller:input_type -> tapestry.DataBlob
+	7,  // 66: tapestry.TapestryRPC.TapestryLookupCaller:input_type -> tapestry.Key
+	4,  // 67: tapestry.TapestryRPC.TapestryStoreManyCaller:input_type -> tapestry.DataBlobs
+	8,  // 68: tapestry.TapestryRPC.TapestryLookupManyCaller:input_type -> tapestry.Keys
+	9,  // 69: tapestry.TapestryRPC.GetRoutingTableCaller:input_type -> tapestry.NodeMsg
+	31, // 70: tapestry.TapestryRPC.GetStateCaller:input_type -> tapestry.StateRequest
+	35, // 71: tapestry.TapestryRPC.ListKeysCaller:input_type -> tapestry.ListKeysRequest
+	37, // 72: tapestry.TapestryRPC.PublishedKeysCaller:input_type -> tapestry.PublishedKeysRequest
+	40, // 73: tapestry.TapestryAdmin.GetRoutingTable:input_type -> tapestry.AdminRequest
+	40, // 74: tapestry.TapestryAdmin.GetBackpointers:input_type -> tapestry.AdminRequest
+	40, // 75: tapestry.TapestryAdmin.GetLocationMap:input_type -> tapestry.AdminRequest
+	40, // 76: tapestry.TapestryAdmin.ListBlobs:input_type -> tapestry.AdminRequest
+	46, // 77: tapestry.TapestryAdmin.SetLogLevel:input_type -> tapestry.LogLevelRequest
+	40, // 78: tapestry.TapestryAdmin.Leave:input_type -> tapestry.AdminRequest
+	40, // 79: tapestry.TapestryAdmin.Drain:input_type -> tapestry.AdminRequest
+	9,  // 80: tapestry.TapestryRPC.HelloCaller:output_type -> tapestry.NodeMsg
+	10, // 81: tapestry.TapestryRPC.FindRootCaller:output_type -> tapestry.RootMsg
+	12, // 82: tapestry.TapestryRPC.RegisterCaller:output_type -> tapestry.RegistrationReply
+	0,  // 83: tapestry.TapestryRPC.UnregisterCaller:output_type -> tapestry.Ok
+	18, // 84: tapestry.TapestryRPC.FetchCaller:output_type -> tapestry.FetchedLocations
+	14, // 85: tapestry.TapestryRPC.RegisterManyCaller:output_type -> tapestry.RegistrationReplies
+	19, // 86: tapestry.TapestryRPC.FetchManyCaller:output_type -> tapestry.FetchedLocationsList
+	20, // 87: tapestry.TapestryRPC.AddNodeCaller:output_type -> tapestry.Neighbors
+	0,  // 88: tapestry.TapestryRPC.RemoveBadNodesCaller:output_type -> tapestry.Ok
+	20, // 89: tapestry.TapestryRPC.AddNodeMulticastCaller:output_type -> tapestry.Neighbors
+	0,  // 90: tapestry.TapestryRPC.TransferCaller:output_type -> tapestry.Ok
+	0,  // 91: tapestry.TapestryRPC.MirrorCaller:output_type -> tapestry.Ok
+	26, // 92: tapestry.TapestryRPC.SummaryCaller:output_type -> tapestry.Summary
+	0,  // 93: tapestry.TapestryRPC.AddBackpointerCaller:output_type -> tapestry.Ok
+	0,  // 94: tapestry.TapestryRPC.RemoveBackpointerCaller:output_type -> tapestry.Ok
+	20, // 95: tapestry.TapestryRPC.GetBackpointersCaller:output_type -> tapestry.Neighbors
+	0,  // 96: tapestry.TapestryRPC.NotifyLeaveCaller:output_type -> tapestry.Ok
+	2,  // 97: tapestry.TapestryRPC.BlobStoreFetchCaller:output_type -> tapestry.DataBlob
+	6,  // 98: tapestry.TapestryRPC.BlobStoreStatCaller:output_type -> tapestry.MetadataMsg
+	3,  // 99: tapestry.TapestryRPC.TapestryStoreCaller:output_type -> tapestry.StoreReply
+	16, // 100: tapestry.TapestryRPC.TapestryLookupCaller:output_type -> tapestry.Replicas
+	5,  // 101: tapestry.TapestryRPC.TapestryStoreManyCaller:output_type -> tapestry.StoreReplies
+	17, // 102: tapestry.TapestryRPC.TapestryLookupManyCaller:output_type -> tapestry.ReplicasByKey
+	30, // 103: tapestry.TapestryRPC.GetRoutingTableCaller:output_type -> tapestry.RoutingTableMsg
+	34, // 104: tapestry.TapestryRPC.GetStateCaller:output_type -> tapestry.NodeStateMsg
+	36, // 105: tapestry.TapestryRPC.ListKeysCaller:output_type -> tapestry.KeyList
+	39, // 106: tapestry.TapestryRPC.PublishedKeysCaller:output_type -> tapestry.PublishStatuses
+	30, // 107: tapestry.TapestryAdmin.GetRoutingTable:output_type -> tapestry.RoutingTableMsg
+	42, // 108: tapestry.TapestryAdmin.GetBackpointers:output_type -> tapestry.BackpointersMsg
+	43, // 109: tapestry.TapestryAdmin.GetLocationMap:output_type -> tapestry.LocationMapMsg
+	45, // 110: tapestry.TapestryAdmin.ListBlobs:output_type -> tapestry.BlobList
+	47, // 111: tapestry.TapestryAdmin.SetLogLevel:output_type -> tapestry.LogLevelReply
+	41, // 112: tapestry.TapestryAdmin.Leave:output_type -> tapestry.AdminReply
+	41, // 113: tapestry.TapestryAdmin.Drain:output_type -> tapestry.AdminReply
+	80, // [80:114] is the sub-list for method output_type
+	46, // [46:80] is the sub-list for method input_type
+	46, // [46:46] is the sub-list for extension type_name
+	46, // [46:46] is the sub-list for extension extendee
+	0,  // [0:46] is the sub-list for field type_name
 }
 
 func init() { file_pkg_tapestry_rpc_proto_init() }
@@ -945,8 +3418,152 @@ func file_pkg_tapestry_rpc_proto_init() {
 				return nil
 			}
 		}
-		file_pkg_tapestry_rpc_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*IdMsg); i {
+		file_pkg_tapestry_rpc_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*IdMsg); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_tapestry_rpc_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DataBlob); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_tapestry_rpc_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StoreReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_tapestry_rpc_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DataBlobs); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_tapestry_rpc_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StoreReplies); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_tapestry_rpc_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MetadataMsg); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_tapestry_rpc_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Key); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_tapestry_rpc_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Keys); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_tapestry_rpc_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*NodeMsg); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_tapestry_rpc_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RootMsg); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_tapestry_rpc_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Registration); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_tapestry_rpc_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RegistrationReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_tapestry_rpc_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Registrations); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -957,8 +3574,8 @@ func file_pkg_tapestry_rpc_proto_init() {
 				return nil
 			}
 		}
-		file_pkg_tapestry_rpc_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*DataBlob); i {
+		file_pkg_tapestry_rpc_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RegistrationReplies); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -969,8 +3586,8 @@ func file_pkg_tapestry_rpc_proto_init() {
 				return nil
 			}
 		}
-		file_pkg_tapestry_rpc_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Key); i {
+		file_pkg_tapestry_rpc_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReplicaMsg); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -981,8 +3598,8 @@ func file_pkg_tapestry_rpc_proto_init() {
 				return nil
 			}
 		}
-		file_pkg_tapestry_rpc_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*NodeMsg); i {
+		file_pkg_tapestry_rpc_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Replicas); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -993,8 +3610,8 @@ func file_pkg_tapestry_rpc_proto_init() {
 				return nil
 			}
 		}
-		file_pkg_tapestry_rpc_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*RootMsg); i {
+		file_pkg_tapestry_rpc_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReplicasByKey); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1005,8 +3622,8 @@ func file_pkg_tapestry_rpc_proto_init() {
 				return nil
 			}
 		}
-		file_pkg_tapestry_rpc_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Registration); i {
+		file_pkg_tapestry_rpc_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FetchedLocations); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1017,8 +3634,8 @@ func file_pkg_tapestry_rpc_proto_init() {
 				return nil
 			}
 		}
-		file_pkg_tapestry_rpc_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*FetchedLocations); i {
+		file_pkg_tapestry_rpc_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FetchedLocationsList); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1029,7 +3646,7 @@ func file_pkg_tapestry_rpc_proto_init() {
 				return nil
 			}
 		}
-		file_pkg_tapestry_rpc_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+		file_pkg_tapestry_rpc_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*Neighbors); i {
 			case 0:
 				return &v.state
@@ -1041,7 +3658,7 @@ func file_pkg_tapestry_rpc_proto_init() {
 				return nil
 			}
 		}
-		file_pkg_tapestry_rpc_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+		file_pkg_tapestry_rpc_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*MulticastRequest); i {
 			case 0:
 				return &v.state
@@ -1053,7 +3670,7 @@ func file_pkg_tapestry_rpc_proto_init() {
 				return nil
 			}
 		}
-		file_pkg_tapestry_rpc_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+		file_pkg_tapestry_rpc_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*TransferData); i {
 			case 0:
 				return &v.state
@@ -1065,7 +3682,55 @@ func file_pkg_tapestry_rpc_proto_init() {
 				return nil
 			}
 		}
-		file_pkg_tapestry_rpc_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+		file_pkg_tapestry_rpc_proto_msgTypes[23].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MirrorEntry); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_tapestry_rpc_proto_msgTypes[24].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MirrorData); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_tapestry_rpc_proto_msgTypes[25].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SummaryRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_tapestry_rpc_proto_msgTypes[26].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Summary); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_tapestry_rpc_proto_msgTypes[27].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*BackpointerRequest); i {
 			case 0:
 				return &v.state
@@ -1077,7 +3742,7 @@ func file_pkg_tapestry_rpc_proto_init() {
 				return nil
 			}
 		}
-		file_pkg_tapestry_rpc_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+		file_pkg_tapestry_rpc_proto_msgTypes[28].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*LeaveNotification); i {
 			case 0:
 				return &v.state
@@ -1089,6 +3754,234 @@ func file_pkg_tapestry_rpc_proto_init() {
 				return nil
 			}
 		}
+		file_pkg_tapestry_rpc_proto_msgTypes[29].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RoutingEntryMsg); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_tapestry_rpc_proto_msgTypes[30].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RoutingTableMsg); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_tapestry_rpc_proto_msgTypes[31].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StateRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_tapestry_rpc_proto_msgTypes[32].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BackpointerMsg); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_tapestry_rpc_proto_msgTypes[33].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LocationEntryMsg); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_tapestry_rpc_proto_msgTypes[34].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*NodeStateMsg); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_tapestry_rpc_proto_msgTypes[35].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListKeysRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_tapestry_rpc_proto_msgTypes[36].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*KeyList); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_tapestry_rpc_proto_msgTypes[37].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PublishedKeysRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_tapestry_rpc_proto_msgTypes[38].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PublishStatusMsg); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_tapestry_rpc_proto_msgTypes[39].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PublishStatuses); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_tapestry_rpc_proto_msgTypes[40].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AdminRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_tapestry_rpc_proto_msgTypes[41].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AdminReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_tapestry_rpc_proto_msgTypes[42].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BackpointersMsg); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_tapestry_rpc_proto_msgTypes[43].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LocationMapMsg); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_tapestry_rpc_proto_msgTypes[44].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BlobInfoMsg); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_tapestry_rpc_proto_msgTypes[45].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BlobList); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_tapestry_rpc_proto_msgTypes[46].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LogLevelRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_tapestry_rpc_proto_msgTypes[47].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LogLevelReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
@@ -1096,9 +3989,9 @@ func file_pkg_tapestry_rpc_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_pkg_tapestry_rpc_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   14,
+			NumMessages:   52,
 			NumExtensions: 0,
-			NumServices:   1,
+			NumServices:   2,
 		},
 		GoTypes:           file_pkg_tapestry_rpc_proto_goTypes,
 		DependencyIndexes: file_pkg_tapestry_rpc_proto_depIdxs,
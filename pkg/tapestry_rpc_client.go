@@ -8,18 +8,39 @@
 package pkg
 
 import (
+	"errors"
+	"fmt"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"golang.org/x/net/context"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	// Uncomment for xtrace
 	// util "github.com/brown-csci1380/tracing-framework-go/xtrace/grpcutil"
 )
 
+// GRPCTimeout is how long RPCs to other nodes may take by default. See SetRPCTimeout.
 const GRPCTimeout = 5 * time.Second
 
-// clientUnaryInterceptor is a client unary interceptor that injects a default timeout
+// The deadline of RPCs to other nodes, in nanoseconds
+var rpcTimeout = int64(GRPCTimeout)
+
+// SetRPCTimeout sets how long RPCs to other nodes may take, GRPCTimeout by default. It applies to
+// every node in the process. Returns the previous timeout.
+func SetRPCTimeout(timeout time.Duration) (previous time.Duration) {
+	return time.Duration(atomic.SwapInt64(&rpcTimeout, int64(timeout)))
+}
+
+// RPCTimeout returns how long RPCs to other nodes may take
+func RPCTimeout() time.Duration {
+	return time.Duration(atomic.LoadInt64(&rpcTimeout))
+}
+
+// clientUnaryInterceptor is a client unary interceptor that injects a default timeout into calls
+// without a deadline
 func clientUnaryInterceptor(
 	ctx context.Context,
 	method string,
@@ -28,16 +49,19 @@ func clientUnaryInterceptor(
 	invoker grpc.UnaryInvoker,
 	opts ...grpc.CallOption,
 ) error {
-	ctx, cancel := context.WithTimeout(ctx, GRPCTimeout)
-	defer cancel()
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, RPCTimeout())
+		defer cancel()
+	}
 
 	return invoker(ctx, method, req, reply, cc, opts...)
 }
 
 // RemoteNode represents non-local node addresses in the tapestry
 type RemoteNode struct {
-	ID      ID
-	Address string
+	ID      ID     `json:"id"`
+	Address string `json:"address"`
 }
 
 // Turns a NodeMsg into a RemoteNode
@@ -66,6 +90,55 @@ func (n *RemoteNode) toNodeMsg() *NodeMsg {
 	}
 }
 
+// Turns a MetadataMsg into Metadata
+func (m *MetadataMsg) toMetadata() Metadata {
+	if m == nil {
+		return Metadata{}
+	}
+	return Metadata{
+		Size:        int(m.Size),
+		ContentType: m.ContentType,
+		Created:     unixNanoTime(m.Created),
+		Modified:    unixNanoTime(m.Modified),
+		Tags:        m.Tags,
+		Version:     Version(m.Version),
+		Expires:     unixNanoTime(m.Expires),
+		Cached:      m.Cached,
+		Lease:       unixNanoTime(m.Lease),
+	}
+}
+
+// Zero stays the zero time rather than the start of the Unix epoch
+func unixNanoTime(nanos int64) time.Time {
+	if nanos == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, nanos)
+}
+
+// The zero time becomes zero rather than a large negative number
+func unixNano(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixNano()
+}
+
+// Turns Metadata into a MetadataMsg
+func (m Metadata) toMetadataMsg() *MetadataMsg {
+	return &MetadataMsg{
+		Size:        int64(m.Size),
+		ContentType: m.ContentType,
+		Created:     unixNano(m.Created),
+		Modified:    unixNano(m.Modified),
+		Tags:        m.Tags,
+		Version:     uint64(m.Version),
+		Expires:     unixNano(m.Expires),
+		Cached:      m.Cached,
+		Lease:       unixNano(m.Lease),
+	}
+}
+
 /**
  *  RPC invocation functions
  */
@@ -142,9 +215,15 @@ func SayHelloRPC(addr string, joiner RemoteNode) (RemoteNode, error) {
 
 func (remote *RemoteNode) FindRootRPC(id ID, level int32) (RemoteNode, *NodeSet, error) {
 	// TODO: students should implement this
+	root, toRemove, _, err := remote.findRootRPC(id, level)
+	return root, toRemove, err
+}
+
+// Like FindRootRPC, also returning how many hops the remote node took to reach the root
+func (remote *RemoteNode) findRootRPC(id ID, level int32) (RemoteNode, *NodeSet, int, error) {
 	cc, err := remote.ClientConn()
 	if err != nil {
-		return RemoteNode{}, NewNodeSet(), err
+		return RemoteNode{}, NewNodeSet(), 0, err
 	}
 	rsp, err := cc.FindRootCaller(context.Background(), &IdMsg{
 		Id:    id.String(),
@@ -155,22 +234,75 @@ func (remote *RemoteNode) FindRootRPC(id ID, level int32) (RemoteNode, *NodeSet,
 	for _, node := range set {
 		nodeSet.Add(node)
 	}
-	return rsp.GetNext().toRemoteNode(), nodeSet, remote.connCheck(err)
+	return rsp.GetNext().toRemoteNode(), nodeSet, int(rsp.GetHops()), remote.connCheck(err)
 }
 
-func (remote *RemoteNode) RegisterRPC(key string, replica RemoteNode) (bool, error) {
+// RegisterRPC registers the replica of key on the remote node.
+// Returns whether the remote node is the root for key, and the newest version registered before this one.
+func (remote *RemoteNode) RegisterRPC(key string, replica Replica) (bool, Version, error) {
 	cc, err := remote.ClientConn()
 	if err != nil {
-		return false, err
+		return false, 0, err
 	}
 	rsp, err := cc.RegisterCaller(context.Background(), &Registration{
+		FromNode: replica.Node.toNodeMsg(),
+		Key:      key,
+		Version:  uint64(replica.Version),
+		Expires:  unixNano(replica.Expires),
+		Soft:     replica.Soft,
+	})
+	return rsp.GetIsRoot(), Version(rsp.GetNewest()), remote.connCheck(err)
+}
+
+// RegisterIfRPC registers the replica of key on the remote node, if the newest version registered there
+// is expected. Returns whether the remote node is the root for key, whether the replica was registered,
+// and the newest version registered before this one.
+func (remote *RemoteNode) RegisterIfRPC(key string, replica Replica, expected Version) (bool, bool, Version, error) {
+	cc, err := remote.ClientConn()
+	if err != nil {
+		return false, false, 0, err
+	}
+	rsp, err := cc.RegisterCaller(context.Background(), &Registration{
+		FromNode:    replica.Node.toNodeMsg(),
+		Key:         key,
+		Version:     uint64(replica.Version),
+		Expires:     unixNano(replica.Expires),
+		Soft:        replica.Soft,
+		Conditional: true,
+		Expected:    uint64(expected),
+	})
+	return rsp.GetIsRoot(), rsp.GetRegistered(), Version(rsp.GetNewest()), remote.connCheck(err)
+}
+
+// UnregisterRPC removes the replica of key from the location map of the remote node
+func (remote *RemoteNode) UnregisterRPC(key string, replica RemoteNode) error {
+	cc, err := remote.ClientConn()
+	if err != nil {
+		return err
+	}
+	_, err = cc.UnregisterCaller(context.Background(), &Registration{
 		FromNode: replica.toNodeMsg(),
 		Key:      key,
 	})
-	return rsp.GetOk(), remote.connCheck(err)
+	return remote.connCheck(err)
+}
+
+// UnregisterVersionRPC removes the replica of key from the location map of the remote node, if the
+// replica's version is the one registered for its node
+func (remote *RemoteNode) UnregisterVersionRPC(key string, replica Replica) error {
+	cc, err := remote.ClientConn()
+	if err != nil {
+		return err
+	}
+	_, err = cc.UnregisterCaller(context.Background(), &Registration{
+		FromNode: replica.Node.toNodeMsg(),
+		Key:      key,
+		Version:  uint64(replica.Version),
+	})
+	return remote.connCheck(err)
 }
 
-func (remote *RemoteNode) FetchRPC(key string) (bool, []RemoteNode, error) {
+func (remote *RemoteNode) FetchRPC(key string) (bool, []Replica, error) {
 	// TODO: students should implement this
 	cc, err := remote.ClientConn()
 	if err != nil {
@@ -179,7 +311,62 @@ func (remote *RemoteNode) FetchRPC(key string) (bool, []RemoteNode, error) {
 	rsp, err := cc.FetchCaller(context.Background(), &Key{
 		Key: key,
 	})
-	return true, nodeMsgsToRemoteNodes(rsp.GetValues()), remote.connCheck(err)
+	return true, replicaMsgsToReplicas(rsp.GetReplicas()), remote.connCheck(err)
+}
+
+// RegisterManyRPC registers the replica of each key on the remote node in a single call.
+// Returns, for each key in order, whether the remote node is its root, and the newest version
+// registered before this one.
+func (remote *RemoteNode) RegisterManyRPC(keys []string, replicas []Replica) ([]bool, []Version, error) {
+	cc, err := remote.ClientConn()
+	if err != nil {
+		return nil, nil, err
+	}
+	registrations := make([]*Registration, len(keys))
+	for i, key := range keys {
+		registrations[i] = &Registration{
+			FromNode: replicas[i].Node.toNodeMsg(),
+			Key:      key,
+			Version:  uint64(replicas[i].Version),
+			Expires:  unixNano(replicas[i].Expires),
+			Soft:     replicas[i].Soft,
+		}
+	}
+	rsp, err := cc.RegisterManyCaller(context.Background(), &Registrations{Registrations: registrations})
+	if err != nil {
+		return nil, nil, remote.connCheck(err)
+	}
+	if len(rsp.Replies) != len(keys) {
+		return nil, nil, fmt.Errorf("%v replied to %v registrations with %v replies", remote, len(keys), len(rsp.Replies))
+	}
+	isRoot := make([]bool, len(keys))
+	newest := make([]Version, len(keys))
+	for i, reply := range rsp.Replies {
+		isRoot[i], newest[i] = reply.IsRoot, Version(reply.Newest)
+	}
+	return isRoot, newest, nil
+}
+
+// FetchManyRPC fetches the replicas of each key from the remote node in a single call.
+// Returns, for each key in order, whether the remote node is its root, and its replicas.
+func (remote *RemoteNode) FetchManyRPC(keys []string) ([]bool, [][]Replica, error) {
+	cc, err := remote.ClientConn()
+	if err != nil {
+		return nil, nil, err
+	}
+	rsp, err := cc.FetchManyCaller(context.Background(), &Keys{Keys: keys})
+	if err != nil {
+		return nil, nil, remote.connCheck(err)
+	}
+	if len(rsp.Locations) != len(keys) {
+		return nil, nil, fmt.Errorf("%v replied to a fetch of %v keys with %v locations", remote, len(keys), len(rsp.Locations))
+	}
+	isRoot := make([]bool, len(keys))
+	replicas := make([][]Replica, len(keys))
+	for i, locations := range rsp.Locations {
+		isRoot[i], replicas[i] = locations.IsRoot, replicaMsgsToReplicas(locations.Replicas)
+	}
+	return isRoot, replicas, nil
 }
 
 func (remote *RemoteNode) RemoveBadNodesRPC(badnodes []RemoteNode) error {
@@ -198,6 +385,10 @@ func (remote *RemoteNode) AddNodeRPC(toAdd RemoteNode) ([]RemoteNode, error) {
 		return nil, err
 	}
 	rsp, err := cc.AddNodeCaller(context.Background(), toAdd.toNodeMsg())
+	if status.Code(err) == codes.AlreadyExists {
+		// The remote node already owns the ID, so the connection itself is fine
+		return nil, &IDCollisionError{ID: toAdd.ID, Existing: *remote}
+	}
 	if err != nil {
 		return nil, remote.connCheck(err)
 	}
@@ -219,16 +410,16 @@ func (remote *RemoteNode) AddNodeMulticastRPC(newNode RemoteNode, level int) ([]
 	return nodeMsgsToRemoteNodes(rsp.Neighbors), remote.connCheck(err)
 }
 
-func (remote *RemoteNode) TransferRPC(from RemoteNode, data map[string][]RemoteNode) error {
+func (remote *RemoteNode) TransferRPC(from RemoteNode, data map[string][]Replica) error {
 	// TODO: students should implement this
 	cc, err := remote.ClientConn()
 	if err != nil {
 		return err
 	}
-	transData := make(map[string]*Neighbors)
+	transData := make(map[string]*Replicas)
 	for k, v := range data {
-		transData[k] = &Neighbors{
-			Neighbors: remoteNodesToNodeMsgs(v),
+		transData[k] = &Replicas{
+			Replicas: replicasToReplicaMsgs(v),
 		}
 	}
 	_, err = cc.TransferCaller(context.Background(), &TransferData{
@@ -238,6 +429,45 @@ func (remote *RemoteNode) TransferRPC(from RemoteNode, data map[string][]RemoteN
 	return remote.connCheck(err)
 }
 
+func (remote *RemoteNode) MirrorRPC(from RemoteNode, entries []mirrorEntry) error {
+	cc, err := remote.ClientConn()
+	if err != nil {
+		return err
+	}
+	msgs := make([]*MirrorEntry, len(entries))
+	for i, entry := range entries {
+		msgs[i] = &MirrorEntry{
+			Key:     entry.key,
+			Replica: replicasToReplicaMsgs([]Replica{entry.replica})[0],
+			Removed: entry.removed,
+		}
+	}
+	_, err = cc.MirrorCaller(context.Background(), &MirrorData{
+		From:    from.toNodeMsg(),
+		Entries: msgs,
+	})
+	return remote.connCheck(err)
+}
+
+func (remote *RemoteNode) SummaryRPC(prefix string) ([][]byte, error) {
+	cc, err := remote.ClientConn()
+	if err != nil {
+		return nil, err
+	}
+	rsp, err := cc.SummaryCaller(context.Background(), &SummaryRequest{Prefix: prefix})
+	if err != nil {
+		return nil, remote.connCheck(err)
+	}
+	// Empty digests arrive as empty slices rather than nil
+	digests := rsp.Digests
+	for i := range digests {
+		if len(digests[i]) == 0 {
+			digests[i] = nil
+		}
+	}
+	return digests, nil
+}
+
 func (remote *RemoteNode) AddBackpointerRPC(bp RemoteNode) error {
 	cc, err := remote.ClientConn()
 	if err != nil {
@@ -285,19 +515,37 @@ func (remote *RemoteNode) NotifyLeaveRPC(from RemoteNode, replacement *RemoteNod
 	return remote.connCheck(err)
 }
 
-func (remote *RemoteNode) BlobStoreFetchRPC(key string) (*[]byte, error) {
+func (remote *RemoteNode) BlobStoreFetchRPC(key string) (*[]byte, Metadata, error) {
 	cc, err := remote.ClientConn()
 	if err != nil {
-		return nil, err
+		return nil, Metadata{}, err
 	}
 	rsp, err := cc.BlobStoreFetchCaller(context.Background(), &Key{Key: key})
+	if status.Code(err) == codes.NotFound {
+		return nil, Metadata{}, err
+	}
 	if err != nil {
-		return nil, remote.connCheck(err)
+		return nil, Metadata{}, remote.connCheck(err)
+	}
+	return &rsp.Data, rsp.Metadata.toMetadata(), remote.connCheck(err)
+}
+
+func (remote *RemoteNode) BlobStoreStatRPC(key string) (Metadata, error) {
+	cc, err := remote.ClientConn()
+	if err != nil {
+		return Metadata{}, err
+	}
+	rsp, err := cc.BlobStoreStatCaller(context.Background(), &Key{Key: key})
+	if status.Code(err) == codes.NotFound {
+		return Metadata{}, err
 	}
-	return &rsp.Data, remote.connCheck(err)
+	if err != nil {
+		return Metadata{}, remote.connCheck(err)
+	}
+	return rsp.toMetadata(), remote.connCheck(err)
 }
 
-func (remote *RemoteNode) TapestryLookupRPC(key string) ([]RemoteNode, error) {
+func (remote *RemoteNode) TapestryLookupRPC(key string) ([]Replica, error) {
 	cc, err := remote.ClientConn()
 	if err != nil {
 		return nil, err
@@ -306,17 +554,176 @@ func (remote *RemoteNode) TapestryLookupRPC(key string) ([]RemoteNode, error) {
 	if err != nil {
 		return nil, remote.connCheck(err)
 	}
-	return nodeMsgsToRemoteNodes(rsp.Neighbors), remote.connCheck(err)
+	return replicaMsgsToReplicas(rsp.Replicas), remote.connCheck(err)
 }
 
-func (remote *RemoteNode) TapestryStoreRPC(key string, value []byte) error {
+// TapestryStoreRPC stores a blob on the remote node, configured by the same options as Store.
+// Returns the version of the stored blob.
+func (remote *RemoteNode) TapestryStoreRPC(key string, value []byte, opts ...StoreOption) (Version, error) {
 	cc, err := remote.ClientConn()
 	if err != nil {
-		return err
+		return 0, err
+	}
+	config := newStoreConfig(opts)
+	rsp, err := cc.TapestryStoreCaller(context.Background(), &DataBlob{
+		Key:         key,
+		Data:        value,
+		Metadata:    config.metadata.toMetadataMsg(),
+		Conditional: config.conditional,
+		Expected:    uint64(config.expected),
+	})
+	if status.Code(err) == codes.ResourceExhausted {
+		// The remote blob store is full, so the connection itself is fine
+		return 0, &StoreFullError{Key: key, Size: len(value)}
+	}
+	if status.Code(err) == codes.FailedPrecondition {
+		return 0, &DrainingError{Key: key}
+	}
+	if err != nil {
+		return 0, remote.connCheck(err)
 	}
-	_, err = cc.TapestryStoreCaller(context.Background(), &DataBlob{
-		Key:  key,
-		Data: value,
+	if rsp.Conflict {
+		return 0, &VersionConflictError{Key: key, Expected: config.expected, Current: Version(rsp.Current)}
+	}
+	return Version(rsp.Version), nil
+}
+
+// TapestryStoreManyRPC stores several blobs on the remote node in a single call, applying the
+// options to each. Returns the version of each stored blob, and the error of each blob that could
+// not be stored.
+func (remote *RemoteNode) TapestryStoreManyRPC(values map[string][]byte, opts ...StoreOption) (map[string]Version, map[string]error, error) {
+	cc, err := remote.ClientConn()
+	if err != nil {
+		return nil, nil, err
+	}
+	config := newStoreConfig(opts)
+	blobs := make([]*DataBlob, 0, len(values))
+	for key, value := range values {
+		blobs = append(blobs, &DataBlob{
+			Key:      key,
+			Data:     value,
+			Metadata: config.metadata.toMetadataMsg(),
+		})
+	}
+	rsp, err := cc.TapestryStoreManyCaller(context.Background(), &DataBlobs{Blobs: blobs})
+	if err != nil {
+		return nil, nil, remote.connCheck(err)
+	}
+	if len(rsp.Replies) != len(blobs) {
+		return nil, nil, fmt.Errorf("%v replied to %v stores with %v replies", remote, len(blobs), len(rsp.Replies))
+	}
+	versions := make(map[string]Version)
+	errs := make(map[string]error)
+	for i, reply := range rsp.Replies {
+		key := blobs[i].Key
+		switch {
+		case reply.Full:
+			errs[key] = &StoreFullError{Key: key, Size: len(blobs[i].Data)}
+		case reply.Conflict:
+			errs[key] = &VersionConflictError{Key: key, Current: Version(reply.Current)}
+		case reply.Error != "":
+			errs[key] = errors.New(reply.Error)
+		default:
+			versions[key] = Version(reply.Version)
+		}
+	}
+	return versions, errs, nil
+}
+
+// TapestryLookupManyRPC looks up several keys on the remote node in a single call. Returns the
+// replicas of each key, and the error of each key that could not be looked up.
+func (remote *RemoteNode) TapestryLookupManyRPC(keys []string) (map[string][]Replica, map[string]error, error) {
+	cc, err := remote.ClientConn()
+	if err != nil {
+		return nil, nil, err
+	}
+	rsp, err := cc.TapestryLookupManyCaller(context.Background(), &Keys{Keys: keys})
+	if err != nil {
+		return nil, nil, remote.connCheck(err)
+	}
+	replicas := make(map[string][]Replica, len(rsp.Replicas))
+	for key, keyReplicas := range rsp.Replicas {
+		replicas[key] = replicaMsgsToReplicas(keyReplicas.Replicas)
+	}
+	errs := make(map[string]error, len(rsp.Errors))
+	for key, message := range rsp.Errors {
+		errs[key] = errors.New(message)
+	}
+	return replicas, errs, nil
+}
+
+func (remote *RemoteNode) GetRoutingTableRPC(from RemoteNode) ([]RoutingEntry, error) {
+	cc, err := remote.ClientConn()
+	if err != nil {
+		return nil, err
+	}
+	rsp, err := cc.GetRoutingTableCaller(context.Background(), from.toNodeMsg())
+	if err != nil {
+		return nil, remote.connCheck(err)
+	}
+	return routingEntryMsgsToRoutingEntries(rsp.Entries), remote.connCheck(err)
+}
+
+// GetStateRPC fetches a snapshot of the state of the remote node, with its location maps if locations is true
+func (remote *RemoteNode) GetStateRPC(locations bool) (NodeState, error) {
+	cc, err := remote.ClientConn()
+	if err != nil {
+		return NodeState{}, err
+	}
+	rsp, err := cc.GetStateCaller(context.Background(), &StateRequest{Locations: locations})
+	if err != nil {
+		return NodeState{}, remote.connCheck(err)
+	}
+	return NodeState{
+		Node:            rsp.Node.toRemoteNode(),
+		RoutingTable:    routingEntryMsgsToRoutingEntries(rsp.RoutingTable),
+		Backpointers:    backpointerMsgsToBackpointerEntries(rsp.Backpointers),
+		Locations:       locationEntryMsgsToLocationEntries(rsp.Locations),
+		BackupLocations: locationEntryMsgsToLocationEntries(rsp.BackupLocations),
+	}, nil
+}
+
+func (remote *RemoteNode) ListKeysRPC(prefix string, pageToken string, limit int) ([]string, bool, error) {
+	cc, err := remote.ClientConn()
+	if err != nil {
+		return nil, false, err
+	}
+	rsp, err := cc.ListKeysCaller(context.Background(), &ListKeysRequest{
+		Prefix:    prefix,
+		PageToken: pageToken,
+		Limit:     int32(limit),
 	})
-	return remote.connCheck(err)
+	if err != nil {
+		return nil, false, remote.connCheck(err)
+	}
+	return rsp.Keys, rsp.NextPageToken != "", remote.connCheck(err)
+}
+
+// PublishedKeysRPC fetches the status of every key the remote node is publishing
+func (remote *RemoteNode) PublishedKeysRPC() ([]PublishStatus, error) {
+	cc, err := remote.ClientConn()
+	if err != nil {
+		return nil, err
+	}
+	rsp, err := cc.PublishedKeysCaller(context.Background(), &PublishedKeysRequest{})
+	if err != nil {
+		return nil, remote.connCheck(err)
+	}
+	statuses := make([]PublishStatus, len(rsp.Statuses))
+	for i, msg := range rsp.Statuses {
+		statuses[i] = PublishStatus{
+			Key:            msg.Key,
+			Version:        Version(msg.Version),
+			Soft:           msg.Soft,
+			LastRegistered: unixNanoTime(msg.LastRegistered),
+			Failures:       int(msg.Failures),
+			LastError:      msg.LastError,
+			NextAttempt:    unixNanoTime(msg.NextAttempt),
+		}
+		if msg.Root != nil {
+			root := msg.Root.toRemoteNode()
+			statuses[i].Root = &root
+		}
+	}
+	return statuses, nil
 }
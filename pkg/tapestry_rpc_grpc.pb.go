@@ -24,19 +24,31 @@ const _ = grpc.SupportPackageIsVersion7
 type TapestryRPCClient interface {
 	HelloCaller(ctx context.Context, in *NodeMsg, opts ...grpc.CallOption) (*NodeMsg, error)
 	FindRootCaller(ctx context.Context, in *IdMsg, opts ...grpc.CallOption) (*RootMsg, error)
-	RegisterCaller(ctx context.Context, in *Registration, opts ...grpc.CallOption) (*Ok, error)
+	RegisterCaller(ctx context.Context, in *Registration, opts ...grpc.CallOption) (*RegistrationReply, error)
+	UnregisterCaller(ctx context.Context, in *Registration, opts ...grpc.CallOption) (*Ok, error)
 	FetchCaller(ctx context.Context, in *Key, opts ...grpc.CallOption) (*FetchedLocations, error)
+	RegisterManyCaller(ctx context.Context, in *Registrations, opts ...grpc.CallOption) (*RegistrationReplies, error)
+	FetchManyCaller(ctx context.Context, in *Keys, opts ...grpc.CallOption) (*FetchedLocationsList, error)
 	AddNodeCaller(ctx context.Context, in *NodeMsg, opts ...grpc.CallOption) (*Neighbors, error)
 	RemoveBadNodesCaller(ctx context.Context, in *Neighbors, opts ...grpc.CallOption) (*Ok, error)
 	AddNodeMulticastCaller(ctx context.Context, in *MulticastRequest, opts ...grpc.CallOption) (*Neighbors, error)
 	TransferCaller(ctx context.Context, in *TransferData, opts ...grpc.CallOption) (*Ok, error)
+	MirrorCaller(ctx context.Context, in *MirrorData, opts ...grpc.CallOption) (*Ok, error)
+	SummaryCaller(ctx context.Context, in *SummaryRequest, opts ...grpc.CallOption) (*Summary, error)
 	AddBackpointerCaller(ctx context.Context, in *NodeMsg, opts ...grpc.CallOption) (*Ok, error)
 	RemoveBackpointerCaller(ctx context.Context, in *NodeMsg, opts ...grpc.CallOption) (*Ok, error)
 	GetBackpointersCaller(ctx context.Context, in *BackpointerRequest, opts ...grpc.CallOption) (*Neighbors, error)
 	NotifyLeaveCaller(ctx context.Context, in *LeaveNotification, opts ...grpc.CallOption) (*Ok, error)
 	BlobStoreFetchCaller(ctx context.Context, in *Key, opts ...grpc.CallOption) (*DataBlob, error)
-	TapestryStoreCaller(ctx context.Context, in *DataBlob, opts ...grpc.CallOption) (*Ok, error)
-	TapestryLookupCaller(ctx context.Context, in *Key, opts ...grpc.CallOption) (*Neighbors, error)
+	BlobStoreStatCaller(ctx context.Context, in *Key, opts ...grpc.CallOption) (*MetadataMsg, error)
+	TapestryStoreCaller(ctx context.Context, in *DataBlob, opts ...grpc.CallOption) (*StoreReply, error)
+	TapestryLookupCaller(ctx context.Context, in *Key, opts ...grpc.CallOption) (*Replicas, error)
+	TapestryStoreManyCaller(ctx context.Context, in *DataBlobs, opts ...grpc.CallOption) (*StoreReplies, error)
+	TapestryLookupManyCaller(ctx context.Context, in *Keys, opts ...grpc.CallOption) (*ReplicasByKey, error)
+	GetRoutingTableCaller(ctx context.Context, in *NodeMsg, opts ...grpc.CallOption) (*RoutingTableMsg, error)
+	GetStateCaller(ctx context.Context, in *StateRequest, opts ...grpc.CallOption) (*NodeStateMsg, error)
+	ListKeysCaller(ctx context.Context, in *ListKeysRequest, opts ...grpc.CallOption) (*KeyList, error)
+	PublishedKeysCaller(ctx context.Context, in *PublishedKeysRequest, opts ...grpc.CallOption) (*PublishStatuses, error)
 }
 
 type tapestryRPCClient struct {
@@ -65,8 +77,8 @@ func (c *tapestryRPCClient) FindRootCaller(ctx context.Context, in *IdMsg, opts
 	return out, nil
 }
 
-func (c *tapestryRPCClient) RegisterCaller(ctx context.Context, in *Registration, opts ...grpc.CallOption) (*Ok, error) {
-	out := new(Ok)
+func (c *tapestryRPCClient) RegisterCaller(ctx context.Context, in *Registration, opts ...grpc.CallOption) (*RegistrationReply, error) {
+	out := new(RegistrationReply)
 	err := c.cc.Invoke(ctx, "/tapestry.TapestryRPC/RegisterCaller", in, out, opts...)
 	if err != nil {
 		return nil, err
@@ -74,6 +86,15 @@ func (c *tapestryRPCClient) RegisterCaller(ctx context.Context, in *Registration
 	return out, nil
 }
 
+func (c *tapestryRPCClient) UnregisterCaller(ctx context.Context, in *Registration, opts ...grpc.CallOption) (*Ok, error) {
+	out := new(Ok)
+	err := c.cc.Invoke(ctx, "/tapestry.TapestryRPC/UnregisterCaller", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *tapestryRPCClient) FetchCaller(ctx context.Context, in *Key, opts ...grpc.CallOption) (*FetchedLocations, error) {
 	out := new(FetchedLocations)
 	err := c.cc.Invoke(ctx, "/tapestry.TapestryRPC/FetchCaller", in, out, opts...)
@@ -83,6 +104,24 @@ func (c *tapestryRPCClient) FetchCaller(ctx context.Context, in *Key, opts ...gr
 	return out, nil
 }
 
+func (c *tapestryRPCClient) RegisterManyCaller(ctx context.Context, in *Registrations, opts ...grpc.CallOption) (*RegistrationReplies, error) {
+	out := new(RegistrationReplies)
+	err := c.cc.Invoke(ctx, "/tapestry.TapestryRPC/RegisterManyCaller", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tapestryRPCClient) FetchManyCaller(ctx context.Context, in *Keys, opts ...grpc.CallOption) (*FetchedLocationsList, error) {
+	out := new(FetchedLocationsList)
+	err := c.cc.Invoke(ctx, "/tapestry.TapestryRPC/FetchManyCaller", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *tapestryRPCClient) AddNodeCaller(ctx context.Context, in *NodeMsg, opts ...grpc.CallOption) (*Neighbors, error) {
 	out := new(Neighbors)
 	err := c.cc.Invoke(ctx, "/tapestry.TapestryRPC/AddNodeCaller", in, out, opts...)
@@ -119,6 +158,24 @@ func (c *tapestryRPCClient) TransferCaller(ctx context.Context, in *TransferData
 	return out, nil
 }
 
+func (c *tapestryRPCClient) MirrorCaller(ctx context.Context, in *MirrorData, opts ...grpc.CallOption) (*Ok, error) {
+	out := new(Ok)
+	err := c.cc.Invoke(ctx, "/tapestry.TapestryRPC/MirrorCaller", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tapestryRPCClient) SummaryCaller(ctx context.Context, in *SummaryRequest, opts ...grpc.CallOption) (*Summary, error) {
+	out := new(Summary)
+	err := c.cc.Invoke(ctx, "/tapestry.TapestryRPC/SummaryCaller", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *tapestryRPCClient) AddBackpointerCaller(ctx context.Context, in *NodeMsg, opts ...grpc.CallOption) (*Ok, error) {
 	out := new(Ok)
 	err := c.cc.Invoke(ctx, "/tapestry.TapestryRPC/AddBackpointerCaller", in, out, opts...)
@@ -164,8 +221,17 @@ func (c *tapestryRPCClient) BlobStoreFetchCaller(ctx context.Context, in *Key, o
 	return out, nil
 }
 
-func (c *tapestryRPCClient) TapestryStoreCaller(ctx context.Context, in *DataBlob, opts ...grpc.CallOption) (*Ok, error) {
-	out := new(Ok)
+func (c *tapestryRPCClient) BlobStoreStatCaller(ctx context.Context, in *Key, opts ...grpc.CallOption) (*MetadataMsg, error) {
+	out := new(MetadataMsg)
+	err := c.cc.Invoke(ctx, "/tapestry.TapestryRPC/BlobStoreStatCaller", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tapestryRPCClient) TapestryStoreCaller(ctx context.Context, in *DataBlob, opts ...grpc.CallOption) (*StoreReply, error) {
+	out := new(StoreReply)
 	err := c.cc.Invoke(ctx, "/tapestry.TapestryRPC/TapestryStoreCaller", in, out, opts...)
 	if err != nil {
 		return nil, err
@@ -173,8 +239,8 @@ func (c *tapestryRPCClient) TapestryStoreCaller(ctx context.Context, in *DataBlo
 	return out, nil
 }
 
-func (c *tapestryRPCClient) TapestryLookupCaller(ctx context.Context, in *Key, opts ...grpc.CallOption) (*Neighbors, error) {
-	out := new(Neighbors)
+func (c *tapestryRPCClient) TapestryLookupCaller(ctx context.Context, in *Key, opts ...grpc.CallOption) (*Replicas, error) {
+	out := new(Replicas)
 	err := c.cc.Invoke(ctx, "/tapestry.TapestryRPC/TapestryLookupCaller", in, out, opts...)
 	if err != nil {
 		return nil, err
@@ -182,25 +248,91 @@ func (c *tapestryRPCClient) TapestryLookupCaller(ctx context.Context, in *Key, o
 	return out, nil
 }
 
+func (c *tapestryRPCClient) TapestryStoreManyCaller(ctx context.Context, in *DataBlobs, opts ...grpc.CallOption) (*StoreReplies, error) {
+	out := new(StoreReplies)
+	err := c.cc.Invoke(ctx, "/tapestry.TapestryRPC/TapestryStoreManyCaller", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tapestryRPCClient) TapestryLookupManyCaller(ctx context.Context, in *Keys, opts ...grpc.CallOption) (*ReplicasByKey, error) {
+	out := new(ReplicasByKey)
+	err := c.cc.Invoke(ctx, "/tapestry.TapestryRPC/TapestryLookupManyCaller", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tapestryRPCClient) GetRoutingTableCaller(ctx context.Context, in *NodeMsg, opts ...grpc.CallOption) (*RoutingTableMsg, error) {
+	out := new(RoutingTableMsg)
+	err := c.cc.Invoke(ctx, "/tapestry.TapestryRPC/GetRoutingTableCaller", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tapestryRPCClient) GetStateCaller(ctx context.Context, in *StateRequest, opts ...grpc.CallOption) (*NodeStateMsg, error) {
+	out := new(NodeStateMsg)
+	err := c.cc.Invoke(ctx, "/tapestry.TapestryRPC/GetStateCaller", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tapestryRPCClient) ListKeysCaller(ctx context.Context, in *ListKeysRequest, opts ...grpc.CallOption) (*KeyList, error) {
+	out := new(KeyList)
+	err := c.cc.Invoke(ctx, "/tapestry.TapestryRPC/ListKeysCaller", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tapestryRPCClient) PublishedKeysCaller(ctx context.Context, in *PublishedKeysRequest, opts ...grpc.CallOption) (*PublishStatuses, error) {
+	out := new(PublishStatuses)
+	err := c.cc.Invoke(ctx, "/tapestry.TapestryRPC/PublishedKeysCaller", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // TapestryRPCServer is the server API for TapestryRPC service.
 // All implementations must embed UnimplementedTapestryRPCServer
 // for forward compatibility
 type TapestryRPCServer interface {
 	HelloCaller(context.Context, *NodeMsg) (*NodeMsg, error)
 	FindRootCaller(context.Context, *IdMsg) (*RootMsg, error)
-	RegisterCaller(context.Context, *Registration) (*Ok, error)
+	RegisterCaller(context.Context, *Registration) (*RegistrationReply, error)
+	UnregisterCaller(context.Context, *Registration) (*Ok, error)
 	FetchCaller(context.Context, *Key) (*FetchedLocations, error)
+	RegisterManyCaller(context.Context, *Registrations) (*RegistrationReplies, error)
+	FetchManyCaller(context.Context, *Keys) (*FetchedLocationsList, error)
 	AddNodeCaller(context.Context, *NodeMsg) (*Neighbors, error)
 	RemoveBadNodesCaller(context.Context, *Neighbors) (*Ok, error)
 	AddNodeMulticastCaller(context.Context, *MulticastRequest) (*Neighbors, error)
 	TransferCaller(context.Context, *TransferData) (*Ok, error)
+	MirrorCaller(context.Context, *MirrorData) (*Ok, error)
+	SummaryCaller(context.Context, *SummaryRequest) (*Summary, error)
 	AddBackpointerCaller(context.Context, *NodeMsg) (*Ok, error)
 	RemoveBackpointerCaller(context.Context, *NodeMsg) (*Ok, error)
 	GetBackpointersCaller(context.Context, *BackpointerRequest) (*Neighbors, error)
 	NotifyLeaveCaller(context.Context, *LeaveNotification) (*Ok, error)
 	BlobStoreFetchCaller(context.Context, *Key) (*DataBlob, error)
-	TapestryStoreCaller(context.Context, *DataBlob) (*Ok, error)
-	TapestryLookupCaller(context.Context, *Key) (*Neighbors, error)
+	BlobStoreStatCaller(context.Context, *Key) (*MetadataMsg, error)
+	TapestryStoreCaller(context.Context, *DataBlob) (*StoreReply, error)
+	TapestryLookupCaller(context.Context, *Key) (*Replicas, error)
+	TapestryStoreManyCaller(context.Context, *DataBlobs) (*StoreReplies, error)
+	TapestryLookupManyCaller(context.Context, *Keys) (*ReplicasByKey, error)
+	GetRoutingTableCaller(context.Context, *NodeMsg) (*RoutingTableMsg, error)
+	GetStateCaller(context.Context, *StateRequest) (*NodeStateMsg, error)
+	ListKeysCaller(context.Context, *ListKeysRequest) (*KeyList, error)
+	PublishedKeysCaller(context.Context, *PublishedKeysRequest) (*PublishStatuses, error)
 	mustEmbedUnimplementedTapestryRPCServer()
 }
 
@@ -214,12 +346,21 @@ func (UnimplementedTapestryRPCServer) HelloCaller(context.Context, *NodeMsg) (*N
 func (UnimplementedTapestryRPCServer) FindRootCaller(context.Context, *IdMsg) (*RootMsg, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method FindRootCaller not implemented")
 }
-func (UnimplementedTapestryRPCServer) RegisterCaller(context.Context, *Registration) (*Ok, error) {
+func (UnimplementedTapestryRPCServer) RegisterCaller(context.Context, *Registration) (*RegistrationReply, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method RegisterCaller not implemented")
 }
+func (UnimplementedTapestryRPCServer) UnregisterCaller(context.Context, *Registration) (*Ok, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UnregisterCaller not implemented")
+}
 func (UnimplementedTapestryRPCServer) FetchCaller(context.Context, *Key) (*FetchedLocations, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method FetchCaller not implemented")
 }
+func (UnimplementedTapestryRPCServer) RegisterManyCaller(context.Context, *Registrations) (*RegistrationReplies, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RegisterManyCaller not implemented")
+}
+func (UnimplementedTapestryRPCServer) FetchManyCaller(context.Context, *Keys) (*FetchedLocationsList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FetchManyCaller not implemented")
+}
 func (UnimplementedTapestryRPCServer) AddNodeCaller(context.Context, *NodeMsg) (*Neighbors, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method AddNodeCaller not implemented")
 }
@@ -232,6 +373,12 @@ func (UnimplementedTapestryRPCServer) AddNodeMulticastCaller(context.Context, *M
 func (UnimplementedTapestryRPCServer) TransferCaller(context.Context, *TransferData) (*Ok, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method TransferCaller not implemented")
 }
+func (UnimplementedTapestryRPCServer) MirrorCaller(context.Context, *MirrorData) (*Ok, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MirrorCaller not implemented")
+}
+func (UnimplementedTapestryRPCServer) SummaryCaller(context.Context, *SummaryRequest) (*Summary, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SummaryCaller not implemented")
+}
 func (UnimplementedTapestryRPCServer) AddBackpointerCaller(context.Context, *NodeMsg) (*Ok, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method AddBackpointerCaller not implemented")
 }
@@ -247,12 +394,33 @@ func (UnimplementedTapestryRPCServer) NotifyLeaveCaller(context.Context, *LeaveN
 func (UnimplementedTapestryRPCServer) BlobStoreFetchCaller(context.Context, *Key) (*DataBlob, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method BlobStoreFetchCaller not implemented")
 }
-func (UnimplementedTapestryRPCServer) TapestryStoreCaller(context.Context, *DataBlob) (*Ok, error) {
+func (UnimplementedTapestryRPCServer) BlobStoreStatCaller(context.Context, *Key) (*MetadataMsg, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BlobStoreStatCaller not implemented")
+}
+func (UnimplementedTapestryRPCServer) TapestryStoreCaller(context.Context, *DataBlob) (*StoreReply, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method TapestryStoreCaller not implemented")
 }
-func (UnimplementedTapestryRPCServer) TapestryLookupCaller(context.Context, *Key) (*Neighbors, error) {
+func (UnimplementedTapestryRPCServer) TapestryLookupCaller(context.Context, *Key) (*Replicas, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method TapestryLookupCaller not implemented")
 }
+func (UnimplementedTapestryRPCServer) TapestryStoreManyCaller(context.Context, *DataBlobs) (*StoreReplies, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TapestryStoreManyCaller not implemented")
+}
+func (UnimplementedTapestryRPCServer) TapestryLookupManyCaller(context.Context, *Keys) (*ReplicasByKey, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TapestryLookupManyCaller not implemented")
+}
+func (UnimplementedTapestryRPCServer) GetRoutingTableCaller(context.Context, *NodeMsg) (*RoutingTableMsg, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRoutingTableCaller not implemented")
+}
+func (UnimplementedTapestryRPCServer) GetStateCaller(context.Context, *StateRequest) (*NodeStateMsg, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetStateCaller not implemented")
+}
+func (UnimplementedTapestryRPCServer) ListKeysCaller(context.Context, *ListKeysRequest) (*KeyList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListKeysCaller not implemented")
+}
+func (UnimplementedTapestryRPCServer) PublishedKeysCaller(context.Context, *PublishedKeysRequest) (*PublishStatuses, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PublishedKeysCaller not implemented")
+}
 func (UnimplementedTapestryRPCServer) mustEmbedUnimplementedTapestryRPCServer() {}
 
 // UnsafeTapestryRPCServer may be embedded to opt out of forward compatibility for this service.
@@ -320,6 +488,24 @@ func _TapestryRPC_RegisterCaller_Handler(srv interface{}, ctx context.Context, d
 	return interceptor(ctx, in, info, handler)
 }
 
+func _TapestryRPC_UnregisterCaller_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Registration)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TapestryRPCServer).UnregisterCaller(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/tapestry.TapestryRPC/UnregisterCaller",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TapestryRPCServer).UnregisterCaller(ctx, req.(*Registration))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _TapestryRPC_FetchCaller_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(Key)
 	if err := dec(in); err != nil {
@@ -338,6 +524,42 @@ func _TapestryRPC_FetchCaller_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _TapestryRPC_RegisterManyCaller_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Registrations)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TapestryRPCServer).RegisterManyCaller(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/tapestry.TapestryRPC/RegisterManyCaller",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TapestryRPCServer).RegisterManyCaller(ctx, req.(*Registrations))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TapestryRPC_FetchManyCaller_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Keys)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TapestryRPCServer).FetchManyCaller(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/tapestry.TapestryRPC/FetchManyCaller",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TapestryRPCServer).FetchManyCaller(ctx, req.(*Keys))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _TapestryRPC_AddNodeCaller_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(NodeMsg)
 	if err := dec(in); err != nil {
@@ -410,6 +632,42 @@ func _TapestryRPC_TransferCaller_Handler(srv interface{}, ctx context.Context, d
 	return interceptor(ctx, in, info, handler)
 }
 
+func _TapestryRPC_MirrorCaller_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MirrorData)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TapestryRPCServer).MirrorCaller(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/tapestry.TapestryRPC/MirrorCaller",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TapestryRPCServer).MirrorCaller(ctx, req.(*MirrorData))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TapestryRPC_SummaryCaller_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SummaryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TapestryRPCServer).SummaryCaller(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/tapestry.TapestryRPC/SummaryCaller",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TapestryRPCServer).SummaryCaller(ctx, req.(*SummaryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _TapestryRPC_AddBackpointerCaller_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(NodeMsg)
 	if err := dec(in); err != nil {
@@ -500,6 +758,24 @@ func _TapestryRPC_BlobStoreFetchCaller_Handler(srv interface{}, ctx context.Cont
 	return interceptor(ctx, in, info, handler)
 }
 
+func _TapestryRPC_BlobStoreStatCaller_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Key)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TapestryRPCServer).BlobStoreStatCaller(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/tapestry.TapestryRPC/BlobStoreStatCaller",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TapestryRPCServer).BlobStoreStatCaller(ctx, req.(*Key))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _TapestryRPC_TapestryStoreCaller_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(DataBlob)
 	if err := dec(in); err != nil {
@@ -536,6 +812,114 @@ func _TapestryRPC_TapestryLookupCaller_Handler(srv interface{}, ctx context.Cont
 	return interceptor(ctx, in, info, handler)
 }
 
+func _TapestryRPC_TapestryStoreManyCaller_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DataBlobs)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TapestryRPCServer).TapestryStoreManyCaller(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/tapestry.TapestryRPC/TapestryStoreManyCaller",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TapestryRPCServer).TapestryStoreManyCaller(ctx, req.(*DataBlobs))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TapestryRPC_TapestryLookupManyCaller_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Keys)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TapestryRPCServer).TapestryLookupManyCaller(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/tapestry.TapestryRPC/TapestryLookupManyCaller",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TapestryRPCServer).TapestryLookupManyCaller(ctx, req.(*Keys))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TapestryRPC_GetRoutingTableCaller_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NodeMsg)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TapestryRPCServer).GetRoutingTableCaller(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/tapestry.TapestryRPC/GetRoutingTableCaller",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TapestryRPCServer).GetRoutingTableCaller(ctx, req.(*NodeMsg))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TapestryRPC_GetStateCaller_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TapestryRPCServer).GetStateCaller(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/tapestry.TapestryRPC/GetStateCaller",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TapestryRPCServer).GetStateCaller(ctx, req.(*StateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TapestryRPC_ListKeysCaller_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListKeysRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TapestryRPCServer).ListKeysCaller(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/tapestry.TapestryRPC/ListKeysCaller",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TapestryRPCServer).ListKeysCaller(ctx, req.(*ListKeysRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TapestryRPC_PublishedKeysCaller_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PublishedKeysRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TapestryRPCServer).PublishedKeysCaller(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/tapestry.TapestryRPC/PublishedKeysCaller",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TapestryRPCServer).PublishedKeysCaller(ctx, req.(*PublishedKeysRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // TapestryRPC_ServiceDesc is the grpc.ServiceDesc for TapestryRPC service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -555,10 +939,22 @@ var TapestryRPC_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "RegisterCaller",
 			Handler:    _TapestryRPC_RegisterCaller_Handler,
 		},
+		{
+			MethodName: "UnregisterCaller",
+			Handler:    _TapestryRPC_UnregisterCaller_Handler,
+		},
 		{
 			MethodName: "FetchCaller",
 			Handler:    _TapestryRPC_FetchCaller_Handler,
 		},
+		{
+			MethodName: "RegisterManyCaller",
+			Handler:    _TapestryRPC_RegisterManyCaller_Handler,
+		},
+		{
+			MethodName: "FetchManyCaller",
+			Handler:    _TapestryRPC_FetchManyCaller_Handler,
+		},
 		{
 			MethodName: "AddNodeCaller",
 			Handler:    _TapestryRPC_AddNodeCaller_Handler,
@@ -575,6 +971,14 @@ var TapestryRPC_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "TransferCaller",
 			Handler:    _TapestryRPC_TransferCaller_Handler,
 		},
+		{
+			MethodName: "MirrorCaller",
+			Handler:    _TapestryRPC_MirrorCaller_Handler,
+		},
+		{
+			MethodName: "SummaryCaller",
+			Handler:    _TapestryRPC_SummaryCaller_Handler,
+		},
 		{
 			MethodName: "AddBackpointerCaller",
 			Handler:    _TapestryRPC_AddBackpointerCaller_Handler,
@@ -595,6 +999,10 @@ var TapestryRPC_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "BlobStoreFetchCaller",
 			Handler:    _TapestryRPC_BlobStoreFetchCaller_Handler,
 		},
+		{
+			MethodName: "BlobStoreStatCaller",
+			Handler:    _TapestryRPC_BlobStoreStatCaller_Handler,
+		},
 		{
 			MethodName: "TapestryStoreCaller",
 			Handler:    _TapestryRPC_TapestryStoreCaller_Handler,
@@ -603,6 +1011,332 @@ var TapestryRPC_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "TapestryLookupCaller",
 			Handler:    _TapestryRPC_TapestryLookupCaller_Handler,
 		},
+		{
+			MethodName: "TapestryStoreManyCaller",
+			Handler:    _TapestryRPC_TapestryStoreManyCaller_Handler,
+		},
+		{
+			MethodName: "TapestryLookupManyCaller",
+			Handler:    _TapestryRPC_TapestryLookupManyCaller_Handler,
+		},
+		{
+			MethodName: "GetRoutingTableCaller",
+			Handler:    _TapestryRPC_GetRoutingTableCaller_Handler,
+		},
+		{
+			MethodName: "GetStateCaller",
+			Handler:    _TapestryRPC_GetStateCaller_Handler,
+		},
+		{
+			MethodName: "ListKeysCaller",
+			Handler:    _TapestryRPC_ListKeysCaller_Handler,
+		},
+		{
+			MethodName: "PublishedKeysCaller",
+			Handler:    _TapestryRPC_PublishedKeysCaller_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "pkg/tapestry_rpc.proto",
+}
+
+// TapestryAdminClient is the client API for TapestryAdmin service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type TapestryAdminClient interface {
+	GetRoutingTable(ctx context.Context, in *AdminRequest, opts ...grpc.CallOption) (*RoutingTableMsg, error)
+	GetBackpointers(ctx context.Context, in *AdminRequest, opts ...grpc.CallOption) (*BackpointersMsg, error)
+	GetLocationMap(ctx context.Context, in *AdminRequest, opts ...grpc.CallOption) (*LocationMapMsg, error)
+	ListBlobs(ctx context.Context, in *AdminRequest, opts ...grpc.CallOption) (*BlobList, error)
+	SetLogLevel(ctx context.Context, in *LogLevelRequest, opts ...grpc.CallOption) (*LogLevelReply, error)
+	Leave(ctx context.Context, in *AdminRequest, opts ...grpc.CallOption) (*AdminReply, error)
+	Drain(ctx context.Context, in *AdminRequest, opts ...grpc.CallOption) (*AdminReply, error)
+}
+
+type tapestryAdminClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewTapestryAdminClient(cc grpc.ClientConnInterface) TapestryAdminClient {
+	return &tapestryAdminClient{cc}
+}
+
+func (c *tapestryAdminClient) GetRoutingTable(ctx context.Context, in *AdminRequest, opts ...grpc.CallOption) (*RoutingTableMsg, error) {
+	out := new(RoutingTableMsg)
+	err := c.cc.Invoke(ctx, "/tapestry.TapestryAdmin/GetRoutingTable", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tapestryAdminClient) GetBackpointers(ctx context.Context, in *AdminRequest, opts ...grpc.CallOption) (*BackpointersMsg, error) {
+	out := new(BackpointersMsg)
+	err := c.cc.Invoke(ctx, "/tapestry.TapestryAdmin/GetBackpointers", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tapestryAdminClient) GetLocationMap(ctx context.Context, in *AdminRequest, opts ...grpc.CallOption) (*LocationMapMsg, error) {
+	out := new(LocationMapMsg)
+	err := c.cc.Invoke(ctx, "/tapestry.TapestryAdmin/GetLocationMap", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tapestryAdminClient) ListBlobs(ctx context.Context, in *AdminRequest, opts ...grpc.CallOption) (*BlobList, error) {
+	out := new(BlobList)
+	err := c.cc.Invoke(ctx, "/tapestry.TapestryAdmin/ListBlobs", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tapestryAdminClient) SetLogLevel(ctx context.Context, in *LogLevelRequest, opts ...grpc.CallOption) (*LogLevelReply, error) {
+	out := new(LogLevelReply)
+	err := c.cc.Invoke(ctx, "/tapestry.TapestryAdmin/SetLogLevel", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tapestryAdminClient) Leave(ctx context.Context, in *AdminRequest, opts ...grpc.CallOption) (*AdminReply, error) {
+	out := new(AdminReply)
+	err := c.cc.Invoke(ctx, "/tapestry.TapestryAdmin/Leave", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tapestryAdminClient) Drain(ctx context.Context, in *AdminRequest, opts ...grpc.CallOption) (*AdminReply, error) {
+	out := new(AdminReply)
+	err := c.cc.Invoke(ctx, "/tapestry.TapestryAdmin/Drain", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// TapestryAdminServer is the server API for TapestryAdmin service.
+// All implementations must embed UnimplementedTapestryAdminServer
+// for forward compatibility
+type TapestryAdminServer interface {
+	GetRoutingTable(context.Context, *AdminRequest) (*RoutingTableMsg, error)
+	GetBackpointers(context.Context, *AdminRequest) (*BackpointersMsg, error)
+	GetLocationMap(context.Context, *AdminRequest) (*LocationMapMsg, error)
+	ListBlobs(context.Context, *AdminRequest) (*BlobList, error)
+	SetLogLevel(context.Context, *LogLevelRequest) (*LogLevelReply, error)
+	Leave(context.Context, *AdminRequest) (*AdminReply, error)
+	Drain(context.Context, *AdminRequest) (*AdminReply, error)
+	mustEmbedUnimplementedTapestryAdminServer()
+}
+
+// UnimplementedTapestryAdminServer must be embedded to have forward compatible implementations.
+type UnimplementedTapestryAdminServer struct {
+}
+
+func (UnimplementedTapestryAdminServer) GetRoutingTable(context.Context, *AdminRequest) (*RoutingTableMsg, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRoutingTable not implemented")
+}
+func (UnimplementedTapestryAdminServer) GetBackpointers(context.Context, *AdminRequest) (*BackpointersMsg, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBackpointers not implemented")
+}
+func (UnimplementedTapestryAdminServer) GetLocationMap(context.Context, *AdminRequest) (*LocationMapMsg, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetLocationMap not implemented")
+}
+func (UnimplementedTapestryAdminServer) ListBlobs(context.Context, *AdminRequest) (*BlobList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListBlobs not implemented")
+}
+func (UnimplementedTapestryAdminServer) SetLogLevel(context.Context, *LogLevelRequest) (*LogLevelReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetLogLevel not implemented")
+}
+func (UnimplementedTapestryAdminServer) Leave(context.Context, *AdminRequest) (*AdminReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Leave not implemented")
+}
+func (UnimplementedTapestryAdminServer) Drain(context.Context, *AdminRequest) (*AdminReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Drain not implemented")
+}
+func (UnimplementedTapestryAdminServer) mustEmbedUnimplementedTapestryAdminServer() {}
+
+// UnsafeTapestryAdminServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to TapestryAdminServer will
+// result in compilation errors.
+type UnsafeTapestryAdminServer interface {
+	mustEmbedUnimplementedTapestryAdminServer()
+}
+
+func RegisterTapestryAdminServer(s grpc.ServiceRegistrar, srv TapestryAdminServer) {
+	s.RegisterService(&TapestryAdmin_ServiceDesc, srv)
+}
+
+func _TapestryAdmin_GetRoutingTable_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AdminRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TapestryAdminServer).GetRoutingTable(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/tapestry.TapestryAdmin/GetRoutingTable",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TapestryAdminServer).GetRoutingTable(ctx, req.(*AdminRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TapestryAdmin_GetBackpointers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AdminRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TapestryAdminServer).GetBackpointers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/tapestry.TapestryAdmin/GetBackpointers",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TapestryAdminServer).GetBackpointers(ctx, req.(*AdminRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TapestryAdmin_GetLocationMap_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AdminRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TapestryAdminServer).GetLocationMap(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/tapestry.TapestryAdmin/GetLocationMap",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TapestryAdminServer).GetLocationMap(ctx, req.(*AdminRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TapestryAdmin_ListBlobs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AdminRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TapestryAdminServer).ListBlobs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/tapestry.TapestryAdmin/ListBlobs",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TapestryAdminServer).ListBlobs(ctx, req.(*AdminRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TapestryAdmin_SetLogLevel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LogLevelRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TapestryAdminServer).SetLogLevel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/tapestry.TapestryAdmin/SetLogLevel",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TapestryAdminServer).SetLogLevel(ctx, req.(*LogLevelRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TapestryAdmin_Leave_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AdminRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TapestryAdminServer).Leave(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/tapestry.TapestryAdmin/Leave",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TapestryAdminServer).Leave(ctx, req.(*AdminRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TapestryAdmin_Drain_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AdminRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TapestryAdminServer).Drain(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/tapestry.TapestryAdmin/Drain",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TapestryAdminServer).Drain(ctx, req.(*AdminRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// TapestryAdmin_ServiceDesc is the grpc.ServiceDesc for TapestryAdmin service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var TapestryAdmin_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "tapestry.TapestryAdmin",
+	HandlerType: (*TapestryAdminServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetRoutingTable",
+			Handler:    _TapestryAdmin_GetRoutingTable_Handler,
+		},
+		{
+			MethodName: "GetBackpointers",
+			Handler:    _TapestryAdmin_GetBackpointers_Handler,
+		},
+		{
+			MethodName: "GetLocationMap",
+			Handler:    _TapestryAdmin_GetLocationMap_Handler,
+		},
+		{
+			MethodName: "ListBlobs",
+			Handler:    _TapestryAdmin_ListBlobs_Handler,
+		},
+		{
+			MethodName: "SetLogLevel",
+			Handler:    _TapestryAdmin_SetLogLevel_Handler,
+		},
+		{
+			MethodName: "Leave",
+			Handler:    _TapestryAdmin_Leave_Handler,
+		},
+		{
+			MethodName: "Drain",
+			Handler:    _TapestryAdmin_Drain_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "pkg/tapestry_rpc.proto",
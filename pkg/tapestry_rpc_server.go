@@ -7,9 +7,9 @@
 package pkg
 
 import (
-	"errors"
-
 	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 /**
@@ -21,33 +21,81 @@ func (local *Node) HelloCaller(ctx context.Context, n *NodeMsg) (*NodeMsg, error
 }
 
 func (local *Node) FindRootCaller(ctx context.Context, id *IdMsg) (*RootMsg, error) {
+	defer local.track()()
 	idVal, err := ParseID(id.Id)
 	if err != nil {
 		return nil, err
 	}
-	next, tr, err := local.FindRoot(idVal, id.Level)
+	next, tr, hops, err := local.findRoot(idVal, id.Level)
 	rsp := &RootMsg{
 		Next:     next.toNodeMsg(),
 		ToRemove: remoteNodesToNodeMsgs(tr.Nodes()),
+		Hops:     int32(hops),
 	}
 	return rsp, err
 }
 
-func (local *Node) RegisterCaller(ctx context.Context, r *Registration) (*Ok, error) {
+func (local *Node) RegisterCaller(ctx context.Context, r *Registration) (*RegistrationReply, error) {
 	// TODO: students should implement this
-	isRoot := local.Register(r.Key, r.FromNode.toRemoteNode())
-	rsp := &Ok{
-		Ok: isRoot,
+	return local.register(r), nil
+}
+
+func (local *Node) RegisterManyCaller(ctx context.Context, r *Registrations) (*RegistrationReplies, error) {
+	rsp := &RegistrationReplies{Replies: make([]*RegistrationReply, len(r.Registrations))}
+	for i, registration := range r.Registrations {
+		rsp.Replies[i] = local.register(registration)
 	}
 	return rsp, nil
 }
 
+// Register the replica described by a registration message, conditionally if it asks to be
+func (local *Node) register(r *Registration) *RegistrationReply {
+	replica := Replica{
+		Node:    r.FromNode.toRemoteNode(),
+		Version: Version(r.Version),
+		Expires: unixNanoTime(r.Expires),
+		Soft:    r.Soft,
+	}
+	rsp := &RegistrationReply{}
+	var newest Version
+	if r.Conditional {
+		rsp.IsRoot, rsp.Registered, newest = local.RegisterIf(r.Key, replica, Version(r.Expected))
+	} else {
+		rsp.IsRoot, newest = local.Register(r.Key, replica)
+		rsp.Registered = rsp.IsRoot
+	}
+	rsp.Newest = uint64(newest)
+	return rsp
+}
+
+func (local *Node) UnregisterCaller(ctx context.Context, r *Registration) (*Ok, error) {
+	if r.Version != 0 {
+		replica := Replica{Node: r.FromNode.toRemoteNode(), Version: Version(r.Version)}
+		return &Ok{Ok: local.UnregisterVersion(r.Key, replica)}, nil
+	}
+	return &Ok{Ok: local.Unregister(r.Key, r.FromNode.toRemoteNode())}, nil
+}
+
 func (local *Node) FetchCaller(ctx context.Context, key *Key) (*FetchedLocations, error) {
-	isRoot, values := local.Fetch(key.Key)
+	defer local.track()()
+	isRoot, replicas := local.Fetch(key.Key)
 
 	rsp := &FetchedLocations{
-		Values: remoteNodesToNodeMsgs(values),
-		IsRoot: isRoot,
+		Replicas: replicasToReplicaMsgs(replicas),
+		IsRoot:   isRoot,
+	}
+	return rsp, nil
+}
+
+func (local *Node) FetchManyCaller(ctx context.Context, keys *Keys) (*FetchedLocationsList, error) {
+	defer local.track()()
+	rsp := &FetchedLocationsList{Locations: make([]*FetchedLocations, len(keys.Keys))}
+	for i, key := range keys.Keys {
+		isRoot, replicas := local.Fetch(key)
+		rsp.Locations[i] = &FetchedLocations{
+			Replicas: replicasToReplicaMsgs(replicas),
+			IsRoot:   isRoot,
+		}
 	}
 	return rsp, nil
 }
@@ -63,6 +111,9 @@ func (local *Node) RemoveBadNodesCaller(ctx context.Context, nodes *Neighbors) (
 
 func (local *Node) AddNodeCaller(ctx context.Context, n *NodeMsg) (*Neighbors, error) {
 	neighbors, err := local.AddNode(n.toRemoteNode())
+	if _, ok := err.(*IDCollisionError); ok {
+		return nil, status.Error(codes.AlreadyExists, err.Error())
+	}
 
 	rsp := &Neighbors{
 		Neighbors: remoteNodesToNodeMsgs(neighbors),
@@ -80,9 +131,9 @@ func (local *Node) AddNodeMulticastCaller(ctx context.Context, m *MulticastReque
 }
 
 func (local *Node) TransferCaller(ctx context.Context, td *TransferData) (*Ok, error) {
-	parsedData := make(map[string][]RemoteNode)
+	parsedData := make(map[string][]Replica)
 	for key, set := range td.Data {
-		parsedData[key] = nodeMsgsToRemoteNodes(set.Neighbors)
+		parsedData[key] = replicaMsgsToReplicas(set.Replicas)
 	}
 	err := local.Transfer(td.From.toRemoteNode(), parsedData)
 
@@ -92,6 +143,26 @@ func (local *Node) TransferCaller(ctx context.Context, td *TransferData) (*Ok, e
 	return rsp, err
 }
 
+func (local *Node) MirrorCaller(ctx context.Context, md *MirrorData) (*Ok, error) {
+	entries := make([]mirrorEntry, len(md.Entries))
+	for i, entry := range md.Entries {
+		if entry.Replica == nil {
+			return nil, status.Errorf(codes.InvalidArgument, "mirrored entry for %v has no replica", entry.Key)
+		}
+		entries[i] = mirrorEntry{
+			key:     entry.Key,
+			replica: replicaMsgsToReplicas([]*ReplicaMsg{entry.Replica})[0],
+			removed: entry.Removed,
+		}
+	}
+	local.mirror(md.From.toRemoteNode(), entries)
+	return &Ok{Ok: true}, nil
+}
+
+func (local *Node) SummaryCaller(ctx context.Context, req *SummaryRequest) (*Summary, error) {
+	return &Summary{Digests: local.LocationsByKey.Summary(req.Prefix)}, nil
+}
+
 func (local *Node) AddBackpointerCaller(ctx context.Context, n *NodeMsg) (*Ok, error) {
 	// TODO: students should implement this
 	err := local.AddBackpointer(n.toRemoteNode())
@@ -128,26 +199,134 @@ func (local *Node) NotifyLeaveCaller(ctx context.Context, ln *LeaveNotification)
 }
 
 func (local *Node) BlobStoreFetchCaller(ctx context.Context, key *Key) (*DataBlob, error) {
+	defer local.track()()
 	data, isOk := local.blobstore.Get(key.Key)
+	metadata, _ := local.blobstore.Stat(key.Key)
 	var err error
 	if !isOk {
-		err = errors.New("Key not found")
+		err = status.Error(codes.NotFound, "Key not found")
 	}
 	return &DataBlob{
-		Key:  key.Key,
-		Data: data,
+		Key:      key.Key,
+		Data:     data,
+		Metadata: metadata.toMetadataMsg(),
 	}, err
 }
 
-func (local *Node) TapestryLookupCaller(ctx context.Context, key *Key) (*Neighbors, error) {
-	nodes, err := local.Lookup(key.Key)
-	return &Neighbors{
-		Neighbors: remoteNodesToNodeMsgs(nodes),
+func (local *Node) BlobStoreStatCaller(ctx context.Context, key *Key) (*MetadataMsg, error) {
+	metadata, isOk := local.blobstore.Stat(key.Key)
+	if !isOk {
+		return nil, status.Error(codes.NotFound, "Key not found")
+	}
+	return metadata.toMetadataMsg(), nil
+}
+
+func (local *Node) TapestryLookupCaller(ctx context.Context, key *Key) (*Replicas, error) {
+	replicas, err := local.LookupVersions(key.Key)
+	return &Replicas{
+		Replicas: replicasToReplicaMsgs(replicas),
 	}, err
 }
 
-func (local *Node) TapestryStoreCaller(ctx context.Context, blob *DataBlob) (*Ok, error) {
-	return &Ok{Ok: true}, local.Store(blob.Key, blob.Data)
+func (local *Node) TapestryStoreCaller(ctx context.Context, blob *DataBlob) (*StoreReply, error) {
+	config := storeConfig{
+		metadata:    blob.Metadata.toMetadata(),
+		conditional: blob.Conditional,
+		expected:    Version(blob.Expected),
+	}
+	version, err := local.store(blob.Key, blob.Data, config)
+	if conflict, ok := err.(*VersionConflictError); ok {
+		return &StoreReply{Conflict: true, Current: uint64(conflict.Current)}, nil
+	}
+	if _, ok := err.(*StoreFullError); ok {
+		return nil, status.Error(codes.ResourceExhausted, err.Error())
+	}
+	if _, ok := err.(*DrainingError); ok {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+	return &StoreReply{Version: uint64(version)}, err
+}
+
+func (local *Node) TapestryStoreManyCaller(ctx context.Context, blobs *DataBlobs) (*StoreReplies, error) {
+	values := make(map[string][]byte, len(blobs.Blobs))
+	metadata := make(map[string]Metadata, len(blobs.Blobs))
+	for _, blob := range blobs.Blobs {
+		if _, exists := values[blob.Key]; exists {
+			return nil, status.Errorf(codes.InvalidArgument, "key %v appears more than once in the batch", blob.Key)
+		}
+		values[blob.Key] = blob.Data
+		metadata[blob.Key] = blob.Metadata.toMetadata()
+	}
+	versions, errs := local.storeMany(values, metadata)
+
+	rsp := &StoreReplies{Replies: make([]*StoreReply, len(blobs.Blobs))}
+	for i, blob := range blobs.Blobs {
+		reply := &StoreReply{Version: uint64(versions[blob.Key])}
+		switch err := errs[blob.Key].(type) {
+		case nil:
+		case *StoreFullError:
+			reply.Full = true
+		case *VersionConflictError:
+			reply.Conflict = true
+			reply.Current = uint64(err.Current)
+		default:
+			reply.Error = err.Error()
+		}
+		rsp.Replies[i] = reply
+	}
+	return rsp, nil
+}
+
+func (local *Node) TapestryLookupManyCaller(ctx context.Context, keys *Keys) (*ReplicasByKey, error) {
+	replicas, errs := local.lookupMany(keys.Keys)
+	rsp := &ReplicasByKey{
+		Replicas: make(map[string]*Replicas, len(replicas)),
+		Errors:   make(map[string]string, len(errs)),
+	}
+	for key, keyReplicas := range replicas {
+		rsp.Replicas[key] = &Replicas{Replicas: replicasToReplicaMsgs(keyReplicas)}
+	}
+	for key, err := range errs {
+		rsp.Errors[key] = err.Error()
+	}
+	return rsp, nil
+}
+
+func (local *Node) GetRoutingTableCaller(ctx context.Context, n *NodeMsg) (*RoutingTableMsg, error) {
+	return &RoutingTableMsg{
+		Node:    local.Node.toNodeMsg(),
+		Entries: routingEntriesToRoutingEntryMsgs(local.Table.Entries()),
+	}, nil
+}
+
+func (local *Node) GetStateCaller(ctx context.Context, req *StateRequest) (*NodeStateMsg, error) {
+	state := local.Snapshot(req.Locations)
+	return &NodeStateMsg{
+		Node:            state.Node.toNodeMsg(),
+		RoutingTable:    routingEntriesToRoutingEntryMsgs(state.RoutingTable),
+		Backpointers:    backpointerEntriesToBackpointerMsgs(state.Backpointers),
+		Locations:       locationEntriesToLocationEntryMsgs(state.Locations),
+		BackupLocations: locationEntriesToLocationEntryMsgs(state.BackupLocations),
+	}, nil
+}
+
+func (local *Node) ListKeysCaller(ctx context.Context, req *ListKeysRequest) (*KeyList, error) {
+	// Pages hold up to KEYPAGESIZE keys, which is also the size of a page with no limit
+	limit := int(req.Limit)
+	if limit < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "negative key limit %v", limit)
+	}
+	if limit == 0 || limit > KEYPAGESIZE {
+		limit = KEYPAGESIZE
+	}
+	keys, more := local.LocationsByKey.Keys(req.Prefix, req.PageToken, limit)
+	rsp := &KeyList{
+		Keys: keys,
+	}
+	if more {
+		rsp.NextPageToken = keys[len(keys)-1]
+	}
+	return rsp, nil
 }
 
 func remoteNodesToNodeMsgs(remoteNodes []RemoteNode) []*NodeMsg {
@@ -165,3 +344,115 @@ func nodeMsgsToRemoteNodes(nodeMsgs []*NodeMsg) []RemoteNode {
 	}
 	return remoteNodes
 }
+
+func replicasToReplicaMsgs(replicas []Replica) []*ReplicaMsg {
+	replicaMsgs := make([]*ReplicaMsg, len(replicas))
+	for i, replica := range replicas {
+		replicaMsgs[i] = &ReplicaMsg{
+			Node:    replica.Node.toNodeMsg(),
+			Version: uint64(replica.Version),
+			Expires: unixNano(replica.Expires),
+			Soft:    replica.Soft,
+		}
+	}
+	return replicaMsgs
+}
+
+func replicaMsgsToReplicas(replicaMsgs []*ReplicaMsg) []Replica {
+	replicas := make([]Replica, len(replicaMsgs))
+	for i, msg := range replicaMsgs {
+		replicas[i] = Replica{
+			Node:    msg.Node.toRemoteNode(),
+			Version: Version(msg.Version),
+			Expires: unixNanoTime(msg.Expires),
+			Soft:    msg.Soft,
+		}
+	}
+	return replicas
+}
+
+func routingEntriesToRoutingEntryMsgs(entries []RoutingEntry) []*RoutingEntryMsg {
+	entryMsgs := make([]*RoutingEntryMsg, len(entries))
+	for i, entry := range entries {
+		entryMsgs[i] = &RoutingEntryMsg{
+			Level: int32(entry.Level),
+			Digit: int32(entry.Digit),
+			Node:  entry.Node.toNodeMsg(),
+		}
+	}
+	return entryMsgs
+}
+
+func routingEntryMsgsToRoutingEntries(entryMsgs []*RoutingEntryMsg) []RoutingEntry {
+	entries := make([]RoutingEntry, len(entryMsgs))
+	for i, entryMsg := range entryMsgs {
+		entries[i] = RoutingEntry{
+			Level: int(entryMsg.Level),
+			Digit: Digit(entryMsg.Digit),
+			Node:  entryMsg.Node.toRemoteNode(),
+		}
+	}
+	return entries
+}
+
+func backpointerEntriesToBackpointerMsgs(entries []BackpointerEntry) []*BackpointerMsg {
+	entryMsgs := make([]*BackpointerMsg, len(entries))
+	for i, entry := range entries {
+		entryMsgs[i] = &BackpointerMsg{
+			Level: int32(entry.Level),
+			Node:  entry.Node.toNodeMsg(),
+		}
+	}
+	return entryMsgs
+}
+
+func backpointerMsgsToBackpointerEntries(entryMsgs []*BackpointerMsg) []BackpointerEntry {
+	entries := make([]BackpointerEntry, len(entryMsgs))
+	for i, entryMsg := range entryMsgs {
+		entries[i] = BackpointerEntry{
+			Level: int(entryMsg.Level),
+			Node:  entryMsg.Node.toRemoteNode(),
+		}
+	}
+	return entries
+}
+
+func locationEntriesToLocationEntryMsgs(entries []LocationEntry) []*LocationEntryMsg {
+	entryMsgs := make([]*LocationEntryMsg, len(entries))
+	for i, entry := range entries {
+		entryMsgs[i] = &LocationEntryMsg{
+			Key:      entry.Key,
+			Replicas: replicasToReplicaMsgs(entry.Replicas),
+		}
+	}
+	return entryMsgs
+}
+
+// Returns nil rather than an empty slice if there are no entries, as location maps are optional
+func locationEntryMsgsToLocationEntries(entryMsgs []*LocationEntryMsg) (entries []LocationEntry) {
+	for _, entryMsg := range entryMsgs {
+		entries = append(entries, LocationEntry{
+			Key:      entryMsg.Key,
+			Replicas: replicaMsgsToReplicas(entryMsg.Replicas),
+		})
+	}
+	return entries
+}
+
+func (local *Node) PublishedKeysCaller(ctx context.Context, req *PublishedKeysRequest) (*PublishStatuses, error) {
+	statuses := local.PublishedKeys()
+	rsp := &PublishStatuses{Statuses: make([]*PublishStatusMsg, len(statuses))}
+	for i, status := range statuses {
+		rsp.Statuses[i] = &PublishStatusMsg{
+			Key:            status.Key,
+			Version:        uint64(status.Version),
+			Soft:           status.Soft,
+			Root:           status.Root.toNodeMsg(),
+			LastRegistered: unixNano(status.LastRegistered),
+			Failures:       int32(status.Failures),
+			LastError:      status.LastError,
+			NextAttempt:    unixNano(status.NextAttempt),
+		}
+	}
+	return rsp, nil
+}
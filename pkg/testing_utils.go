@@ -57,13 +57,18 @@ func AddOne(ida string, addr string, tap []*Node) (t1 *Node, tapNew []*Node, err
 }
 
 func MakeTapestries(connectThem bool, ids ...string) ([]*Node, error) {
+	return makeTapestries(connectThem, ids)
+}
+
+// Starts a node for each ID with the given options, each joining through the first if connectThem
+func makeTapestries(connectThem bool, ids []string, opts ...StartOption) ([]*Node, error) {
 	tapestries := make([]*Node, 0, len(ids))
 	for i := 0; i < len(ids); i++ {
 		connectTo := ""
 		if i > 0 && connectThem {
 			connectTo = tapestries[0].Node.Address
 		}
-		t, err := Start(MakeID(ids[i]), 0, connectTo)
+		t, err := Start(MakeID(ids[i]), 0, connectTo, opts...)
 		if err != nil {
 			return tapestries, err
 		}
@@ -0,0 +1,96 @@
+/*
+ *  Brown University, CS138, Spring 2022
+ *
+ *  Purpose: Defines the Version type and the hybrid logical clock that
+ *  hands out versions to stored objects.
+ */
+
+package pkg
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// LOGICALBITS is the number of low bits of a Version holding the logical counter
+const LOGICALBITS = 16
+
+// Version orders the values stored under a key; larger versions are newer.
+// Versions are hybrid logical clock timestamps: the high bits are milliseconds since the Unix epoch
+// and the low LOGICALBITS bits a counter that orders versions created within the same millisecond,
+// or after observing a version from a node whose clock runs ahead. The zero Version means "no value".
+type Version uint64
+
+// ParseVersion parses the decimal form of a version, as printed by Version.String
+func ParseVersion(s string) (Version, error) {
+	v, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid version %q", s)
+	}
+	return Version(v), nil
+}
+
+func (v Version) String() string {
+	return strconv.FormatUint(uint64(v), 10)
+}
+
+// Time returns the physical part of the version
+func (v Version) Time() time.Time {
+	millis := int64(v >> LOGICALBITS)
+	return time.Unix(millis/1000, (millis%1000)*int64(time.Millisecond))
+}
+
+// VersionConflictError is returned by StoreIf when the newest version of the key is not the expected one
+type VersionConflictError struct {
+	Key      string
+	Expected Version
+	Current  Version
+}
+
+func (e *VersionConflictError) Error() string {
+	return fmt.Sprintf("version conflict on %v: expected %v, found %v", e.Key, e.Expected, e.Current)
+}
+
+// MAXVERSION is the largest Version
+const MAXVERSION = Version(math.MaxUint64)
+
+// Clock is a hybrid logical clock. The versions it returns always increase, and are greater than
+// every version it has observed, so a node that sees another node's version never writes an older one.
+// Once it reaches MAXVERSION the clock stays there rather than wrapping around to older versions.
+type Clock struct {
+	last  Version
+	mutex sync.Mutex
+}
+
+// NewClock creates a clock
+func NewClock() *Clock {
+	return new(Clock)
+}
+
+// Now returns a version greater than any returned or observed before, or MAXVERSION once the clock
+// has reached it
+func (clock *Clock) Now() Version {
+	clock.mutex.Lock()
+	defer clock.mutex.Unlock()
+
+	physical := Version(time.Now().UnixNano()/int64(time.Millisecond)) << LOGICALBITS
+	if physical > clock.last {
+		clock.last = physical
+	} else if clock.last < MAXVERSION {
+		clock.last++
+	}
+	return clock.last
+}
+
+// Observe advances the clock past a version seen on another node
+func (clock *Clock) Observe(v Version) {
+	clock.mutex.Lock()
+	defer clock.mutex.Unlock()
+
+	if v > clock.last {
+		clock.last = v
+	}
+}
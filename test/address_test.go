@@ -0,0 +1,55 @@
+package test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	tapestry "tapestry/pkg"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// test nodes listening on unix-domain sockets can join each other
+func TestStartUnixSocket(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "tapestry")
+	defer os.RemoveAll(dir)
+
+	t1, err := tapestry.Start(tapestry.MakeID("1"), 0, "", tapestry.WithListen("unix:"+filepath.Join(dir, "1.sock")))
+	assert.Equal(t, err, nil)
+	t2, err := tapestry.Start(tapestry.MakeID("2"), 0, t1.Node.Address, tapestry.WithListen("unix://"+filepath.Join(dir, "2.sock")))
+	assert.Equal(t, err, nil)
+	defer tapestry.KillTapestries(t1, t2)
+
+	assert.Equal(t, t1.Node.Address, "unix:"+filepath.Join(dir, "1.sock"))
+	assert.Equal(t, hasRoutingTableNode(t1, t2.Node), true)
+	assert.Equal(t, hasRoutingTableNode(t2, t1.Node), true)
+}
+
+// test the advertised address is separate from the bind address
+func TestStartAdvertise(t *testing.T) {
+	t1, err := tapestry.Start(tapestry.MakeID("1"), 0, "", tapestry.WithListen("0.0.0.0:0"), tapestry.WithAdvertise("127.0.0.1"))
+	assert.Equal(t, err, nil)
+	t2, err := tapestry.Start(tapestry.MakeID("2"), 0, t1.Node.Address, tapestry.WithListen("127.0.0.1:0"))
+	assert.Equal(t, err, nil)
+	defer tapestry.KillTapestries(t1, t2)
+
+	assert.Equal(t, strings.HasPrefix(t1.Node.Address, "127.0.0.1:"), true)
+	assert.Equal(t, strings.HasPrefix(t2.Node.Address, "127.0.0.1:"), true)
+	assert.Equal(t, hasRoutingTableNode(t1, t2.Node), true)
+}
+
+// test nodes listening on IPv6 loopback addresses
+func TestStartIPv6(t *testing.T) {
+	t1, err := tapestry.Start(tapestry.MakeID("1"), 0, "", tapestry.WithListen("[::1]:0"))
+	if err != nil {
+		t.Skipf("IPv6 loopback unavailable: %v", err)
+	}
+	t2, err := tapestry.Start(tapestry.MakeID("2"), 0, t1.Node.Address, tapestry.WithListen("[::1]:0"))
+	assert.Equal(t, err, nil)
+	defer tapestry.KillTapestries(t1, t2)
+
+	assert.Equal(t, strings.HasPrefix(t1.Node.Address, "[::1]:"), true)
+	assert.Equal(t, hasRoutingTableNode(t1, t2.Node), true)
+}
@@ -0,0 +1,126 @@
+package test
+
+import (
+	tapestry "tapestry/pkg"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// test the admin service needs a token to start
+func TestAdminRequiresToken(t *testing.T) {
+	_, err := tapestry.Start(tapestry.MakeID("1"), 0, "", tapestry.WithAdmin("localhost:0", ""))
+	assert.NotEqual(t, err, nil)
+}
+
+// test the admin service and its client refuse to send the token in the clear off loopback
+func TestAdminRequiresTLS(t *testing.T) {
+	_, err := tapestry.Start(tapestry.MakeID("1"), 0, "", tapestry.WithAdmin("0.0.0.0:0", "secret"))
+	assert.NotEqual(t, err, nil)
+	_, err = tapestry.DialAdmin("192.0.2.1:7000", "secret", "")
+	assert.NotEqual(t, err, nil)
+
+	tests := []struct {
+		addr     string
+		loopback bool
+	}{
+		{"localhost:7000", true},
+		{"127.0.0.1:7000", true},
+		{"[::1]:7000", true},
+		{"unix:/tmp/admin.sock", true},
+		{"0.0.0.0:7000", false},
+		{":7000", false},
+		{"192.0.2.1:7000", false},
+		{"example.com:7000", false},
+	}
+	for _, test := range tests {
+		assert.Equal(t, tapestry.IsLoopback(test.addr), test.loopback, test.addr)
+	}
+}
+
+// test the admin service inspects the node, and rejects calls without the token
+func TestAdminService(t *testing.T) {
+	node, err := tapestry.Start(tapestry.MakeID("1"), 0, "", tapestry.WithAdmin("localhost:0", "secret"))
+	assert.Equal(t, err, nil)
+	defer tapestry.KillTapestries(node)
+	assert.Equal(t, node.Store("admin", []byte("value")), nil)
+
+	admin, err := tapestry.DialAdmin(node.AdminAddr(), "secret", "")
+	assert.Equal(t, err, nil)
+	defer admin.Close()
+
+	entries, err := admin.RoutingTable()
+	assert.Equal(t, err, nil)
+	assert.Equal(t, entries, node.Table.Entries())
+	backpointers, err := admin.Backpointers()
+	assert.Equal(t, err, nil)
+	assert.Equal(t, len(backpointers), 0)
+	locations, _, err := admin.LocationMap()
+	assert.Equal(t, err, nil)
+	assert.Equal(t, len(locations), 1)
+	assert.Equal(t, locations[0].Key, "admin")
+	blobs, err := admin.ListBlobs()
+	assert.Equal(t, err, nil)
+	assert.Equal(t, len(blobs), 1)
+	assert.Equal(t, blobs[0].Key, "admin")
+	assert.Equal(t, blobs[0].Metadata.Size, 5)
+
+	previous, err := admin.SetLogLevel("error")
+	assert.Equal(t, err, nil)
+	assert.Equal(t, tapestry.LogLevel(), "error")
+	_, err = admin.SetLogLevel(previous)
+	assert.Equal(t, err, nil)
+	_, err = admin.SetLogLevel("loud")
+	assert.Equal(t, status.Code(err), codes.InvalidArgument)
+
+	intruder, err := tapestry.DialAdmin(node.AdminAddr(), "guess", "")
+	assert.Equal(t, err, nil)
+	defer intruder.Close()
+	_, err = intruder.RoutingTable()
+	assert.Equal(t, status.Code(err), codes.Unauthenticated)
+	assert.Equal(t, status.Code(intruder.Drain(time.Second)), codes.Unauthenticated)
+	assert.Equal(t, node.Draining(), false)
+}
+
+// test the admin service drains the node, handing off its blobs before it stops
+func TestAdminDrain(t *testing.T) {
+	tap, err := tapestry.MakeTapestries(true, "1")
+	assert.Equal(t, err, nil)
+	node, err := tapestry.Start(tapestry.MakeID("5"), 0, tap[0].Addr(), tapestry.WithAdmin("localhost:0", "secret"))
+	assert.Equal(t, err, nil)
+	defer tapestry.KillTapestries(tap[0], node)
+	assert.Equal(t, node.Store("kept", []byte("value")), nil)
+
+	admin, err := tapestry.DialAdmin(node.AdminAddr(), "secret", "")
+	assert.Equal(t, err, nil)
+	defer admin.Close()
+	assert.Equal(t, admin.Drain(10*time.Second), nil)
+	assert.Equal(t, node.Draining(), true)
+
+	_, ok := node.Store("new", []byte("value")).(*tapestry.DrainingError)
+	assert.Equal(t, ok, true)
+	assert.Equal(t, hasRoutingTableNode(tap[0], node.Node), false)
+	value, err := tap[0].Get("kept")
+	assert.Equal(t, err, nil)
+	assert.Equal(t, value, []byte("value"))
+}
+
+// test the admin service makes the node leave the tapestry
+func TestAdminLeave(t *testing.T) {
+	tap, err := tapestry.MakeTapestries(true, "1")
+	assert.Equal(t, err, nil)
+	leaving, err := tapestry.Start(tapestry.MakeID("5"), 0, tap[0].Addr(), tapestry.WithAdmin("localhost:0", "secret"))
+	assert.Equal(t, err, nil)
+	defer tapestry.KillTapestries(tap[0], leaving)
+	assert.Equal(t, hasRoutingTableNode(tap[0], leaving.Node), true)
+
+	admin, err := tapestry.DialAdmin(leaving.AdminAddr(), "secret", "")
+	assert.Equal(t, err, nil)
+	defer admin.Close()
+	assert.Equal(t, admin.Leave(), nil)
+	time.Sleep(200 * time.Millisecond)
+	assert.Equal(t, hasRoutingTableNode(tap[0], leaving.Node), false)
+}
@@ -0,0 +1,125 @@
+package test
+
+import (
+	"bytes"
+	"fmt"
+	tapestry "tapestry/pkg"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Keys whose root in tap is node
+func keysRootedAt(tap []*tapestry.Node, node *tapestry.Node, n int) (keys []string) {
+	for i := 0; len(keys) < n; i++ {
+		key := fmt.Sprintf("key%v", i)
+		if rootOf(tap, key) == node {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// test summaries match for the same registrations, and are the XOR of the summaries of their children
+func TestSummary(t *testing.T) {
+	node := tapestry.RemoteNode{ID: tapestry.MakeID("1"), Address: "localhost:1"}
+	first, second := tapestry.NewLocationMap(), tapestry.NewLocationMap()
+	for i := 0; i < 50; i++ {
+		key := fmt.Sprintf("key%v", i)
+		first.Register(key, tapestry.Replica{Node: node, Version: 1}, time.Minute)
+		second.Register(key, tapestry.Replica{Node: node, Version: 1}, time.Minute)
+	}
+	assert.Equal(t, first.Summary(""), second.Summary(""))
+
+	second.Register("key7", tapestry.Replica{Node: node, Version: 2}, time.Minute)
+	ours, theirs := first.Summary(""), second.Summary("")
+	changed := tapestry.Hash("key7")[0]
+	for digit := range ours {
+		assert.Equal(t, bytes.Equal(ours[digit], theirs[digit]), digit != int(changed))
+	}
+
+	prefix := changed.String()
+	combined := make([]byte, len(ours[changed]))
+	for _, digest := range first.Summary(prefix) {
+		for i := range digest {
+			combined[i] ^= digest[i]
+		}
+	}
+	assert.Equal(t, combined, ours[changed])
+	assert.Equal(t, first.KeysWithHashPrefix(prefix), second.KeysWithHashPrefix(prefix))
+}
+
+// test registrations held by a node that is not the root are transferred to the root and merged
+func TestReconcileMovesMisplacedKeys(t *testing.T) {
+	tap, err := tapestry.MakeTapestries(true, "1", "5")
+	assert.Equal(t, err, nil)
+	defer tapestry.KillTapestries(tap...)
+	root, other := tap[0], tap[1]
+
+	key := keysRootedAt(tap, root, 1)[0]
+	// As if each side of a partition had its own root for the key
+	root.LocationsByKey.Register(key, tapestry.Replica{Node: root.Node, Version: 2}, tapestry.TIMEOUT)
+	other.LocationsByKey.Register(key, tapestry.Replica{Node: other.Node, Version: 1}, tapestry.TIMEOUT)
+
+	moved, err := root.Reconcile()
+	assert.Equal(t, err, nil)
+	assert.Equal(t, moved, 0)
+	moved, err = other.Reconcile()
+	assert.Equal(t, err, nil)
+	assert.Equal(t, moved, 1)
+
+	assert.Equal(t, root.LocationsByKey.Get(key), []tapestry.RemoteNode{root.Node, other.Node})
+	assert.Equal(t, len(other.LocationsByKey.Get(key)), 0)
+	moved, err = other.Reconcile()
+	assert.Equal(t, err, nil)
+	assert.Equal(t, moved, 0)
+}
+
+// test a node keeps the registrations of keys its routing table makes it the root of, whether or not
+// its peers hold registrations under the same prefixes
+func TestReconcileKeepsRootedKeys(t *testing.T) {
+	tap, err := tapestry.MakeTapestries(true, "1", "5")
+	assert.Equal(t, err, nil)
+	defer tapestry.KillTapestries(tap...)
+	root, other := tap[0], tap[1]
+
+	keys := keysRootedAt(tap, root, 10)
+	for _, key := range keys {
+		root.LocationsByKey.Register(key, tapestry.Replica{Node: other.Node, Version: 1}, tapestry.TIMEOUT)
+	}
+	moved, err := root.Reconcile()
+	assert.Equal(t, err, nil)
+	assert.Equal(t, moved, 0)
+
+	// The peer now holds other registrations of the same keys
+	for _, key := range keys {
+		other.LocationsByKey.Register(key, tapestry.Replica{Node: root.Node, Version: 2}, tapestry.TIMEOUT)
+	}
+	moved, err = root.Reconcile()
+	assert.Equal(t, err, nil)
+	assert.Equal(t, moved, 0)
+	for _, key := range keys {
+		assert.Equal(t, root.LocationsByKey.Get(key), []tapestry.RemoteNode{other.Node})
+	}
+}
+
+// test anti-entropy runs periodically
+func TestAntiEntropyInterval(t *testing.T) {
+	root, err := tapestry.Start(tapestry.MakeID("1"), 0, "")
+	assert.Equal(t, err, nil)
+	other, err := tapestry.Start(tapestry.MakeID("5"), 0, root.Addr(), tapestry.WithAntiEntropyInterval(200*time.Millisecond))
+	assert.Equal(t, err, nil)
+	defer tapestry.KillTapestries(root, other)
+
+	keys := keysRootedAt([]*tapestry.Node{root, other}, root, 10)
+	for _, key := range keys {
+		other.LocationsByKey.Register(key, tapestry.Replica{Node: other.Node, Version: 1}, tapestry.TIMEOUT)
+	}
+	time.Sleep(600 * time.Millisecond)
+
+	for _, key := range keys {
+		assert.Equal(t, root.LocationsByKey.Get(key), []tapestry.RemoteNode{other.Node})
+		assert.Equal(t, len(other.LocationsByKey.Get(key)), 0)
+	}
+}
@@ -0,0 +1,151 @@
+package test
+
+import (
+	"fmt"
+	"sort"
+	tapestry "tapestry/pkg"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// The node in tap that is the root of key
+func rootOf(tap []*tapestry.Node, key string) *tapestry.Node {
+	root, _ := tap[0].FindRootOnRemoteNode(tap[0].Node, tapestry.Hash(key))
+	for _, node := range tap {
+		if node.Node == root {
+			return node
+		}
+	}
+	return nil
+}
+
+// A key stored on node whose root is another node
+func keyRootedElsewhere(tap []*tapestry.Node, node *tapestry.Node) string {
+	for i := 0; ; i++ {
+		key := fmt.Sprintf("key%v", i)
+		if rootOf(tap, key) != node {
+			return key
+		}
+	}
+}
+
+// test registrations are mirrored to the backup roots and unregistrations remove the mirrors
+func TestBackupRootsMirror(t *testing.T) {
+	tap, err := tapestry.MakeTapestries(true, "1", "5", "9", "D")
+	assert.Equal(t, err, nil)
+	defer tapestry.KillTapestries(tap...)
+
+	key := keyRootedElsewhere(tap, tap[0])
+	assert.Equal(t, tap[0].Store(key, []byte("mirrored")), nil)
+	time.Sleep(200 * time.Millisecond)
+
+	root := rootOf(tap, key)
+	mirrors := 0
+	for _, node := range tap {
+		if node != root && len(node.BackupLocations.Get(key)) > 0 {
+			assert.Equal(t, node.BackupLocations.Get(key), []tapestry.RemoteNode{tap[0].Node})
+			mirrors++
+		}
+	}
+	assert.Equal(t, mirrors, tapestry.BACKUPROOTS)
+
+	root.Unregister(key, tap[0].Node)
+	time.Sleep(200 * time.Millisecond)
+	for _, node := range tap {
+		assert.Equal(t, len(node.BackupLocations.Get(key)), 0)
+	}
+}
+
+// test a key stays findable when its root crashes, through the backup root that takes over
+func TestLookupFallsBackToBackupRoot(t *testing.T) {
+	tap, err := tapestry.MakeTapestries(true, "1", "5", "9", "D")
+	assert.Equal(t, err, nil)
+	defer tapestry.KillTapestries(tap...)
+
+	key := keyRootedElsewhere(tap, tap[0])
+	assert.Equal(t, tap[0].Store(key, []byte("survives")), nil)
+	time.Sleep(200 * time.Millisecond)
+
+	root := rootOf(tap, key)
+	root.Kill()
+
+	// Long before the replica republishes
+	replicas, err := tap[0].Lookup(key)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, replicas, []tapestry.RemoteNode{tap[0].Node})
+	for _, node := range tap {
+		if node != root && node != tap[0] {
+			value, err := node.Get(key)
+			assert.Equal(t, err, nil)
+			assert.Equal(t, value, []byte("survives"))
+		}
+	}
+}
+
+// test nothing is mirrored when backup roots are turned off
+func TestNoBackupRoots(t *testing.T) {
+	node, err := tapestry.Start(tapestry.MakeID("1"), 0, "", tapestry.WithBackupRoots(0))
+	assert.Equal(t, err, nil)
+	other, err := tapestry.Start(tapestry.MakeID("5"), 0, node.Addr(), tapestry.WithBackupRoots(0))
+	assert.Equal(t, err, nil)
+	defer tapestry.KillTapestries(node, other)
+
+	assert.Equal(t, node.StoreMany(batchValues(20)), nil)
+	time.Sleep(200 * time.Millisecond)
+	for i := 0; i < 20; i++ {
+		key := fmt.Sprintf("key%v", i)
+		assert.Equal(t, len(node.BackupLocations.Get(key))+len(other.BackupLocations.Get(key)), 0)
+	}
+}
+
+// test registrations a backup root failed to receive are mirrored again to the next backup roots
+func TestMirrorRetriesFailedEntries(t *testing.T) {
+	tap, err := tapestry.MakeTapestries(true, "1", "3", "5", "9", "D")
+	assert.Equal(t, err, nil)
+	defer tapestry.KillTapestries(tap...)
+
+	key := keyRootedElsewhere(tap, tap[0])
+	root := rootOf(tap, key)
+	var candidates []*tapestry.Node
+	for _, node := range tap {
+		if node != root {
+			candidates = append(candidates, node)
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		return tapestry.Hash(key).IsNewRoute(candidates[i].Node.ID, candidates[j].Node.ID)
+	})
+	// The first backup root is gone, without the root knowing yet
+	candidates[0].Kill()
+	storer := candidates[len(candidates)-1]
+	assert.Equal(t, storer.Store(key, []byte("mirrored")), nil)
+	time.Sleep(tapestry.MIRRORRETRY + 500*time.Millisecond)
+
+	mirrors := 0
+	for _, node := range candidates[1:] {
+		if len(node.BackupLocations.Get(key)) > 0 {
+			mirrors++
+		}
+	}
+	assert.Equal(t, mirrors, tapestry.BACKUPROOTS)
+}
+
+// test a mirrored entry without a replica is rejected
+func TestMirrorWithoutReplica(t *testing.T) {
+	node, err := tapestry.Start(tapestry.MakeID("1"), 0, "")
+	assert.Equal(t, err, nil)
+	defer tapestry.KillTapestries(node)
+	cc, err := node.Node.ClientConn()
+	assert.Equal(t, err, nil)
+
+	_, err = cc.MirrorCaller(context.Background(), &tapestry.MirrorData{
+		From:    &tapestry.NodeMsg{Id: tapestry.MakeID("5").String(), Address: "127.0.0.1:1"},
+		Entries: []*tapestry.MirrorEntry{{Key: "key"}},
+	})
+	assert.Equal(t, status.Code(err), codes.InvalidArgument)
+}
@@ -0,0 +1,141 @@
+package test
+
+import (
+	"errors"
+	"fmt"
+	tapestry "tapestry/pkg"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Values for n keys, numbered from 0
+func batchValues(n int) map[string][]byte {
+	values := make(map[string][]byte, n)
+	for i := 0; i < n; i++ {
+		values[fmt.Sprintf("key%v", i)] = []byte(fmt.Sprintf("value%v", i))
+	}
+	return values
+}
+
+// test storing many keys from one node makes them available from every node
+func TestStoreMany(t *testing.T) {
+	tap, _ := tapestry.MakeTapestries(true, "1", "5", "9", "d")
+	defer tapestry.KillTapestries(tap...)
+
+	values := batchValues(2*tapestry.BATCHSIZE + 10)
+	assert.Equal(t, tap[0].StoreMany(values, tapestry.WithContentType("text/plain")), nil)
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	blobs, err := tap[2].GetMany(keys)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, blobs, values)
+
+	nodes, err := tap[3].LookupMany(keys)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, len(nodes), len(values))
+	for _, key := range keys {
+		assert.Equal(t, nodes[key], []tapestry.RemoteNode{tap[0].Node})
+	}
+	metadata, _ := tap[1].Stat("key0")
+	assert.Equal(t, metadata.ContentType, "text/plain")
+}
+
+// test GetMany reports missing keys as NotFoundErrors and still returns the others
+func TestGetManyMissing(t *testing.T) {
+	tap, _ := tapestry.MakeTapestries(true, "1", "5")
+	defer tapestry.KillTapestries(tap...)
+
+	tap[0].Store("present", []byte("here"))
+	blobs, err := tap[1].GetMany([]string{"present", "missing"})
+	assert.Equal(t, blobs, map[string][]byte{"present": []byte("here")})
+
+	var batch *tapestry.BatchError
+	assert.Equal(t, errors.As(err, &batch), true)
+	assert.Equal(t, len(batch.Errors), 1)
+	var notFound *tapestry.NotFoundError
+	assert.Equal(t, errors.As(batch.Errors["missing"], &notFound), true)
+
+	nodes, err := tap[1].LookupMany([]string{"present", "missing"})
+	assert.Equal(t, err, nil)
+	assert.Equal(t, nodes["present"], []tapestry.RemoteNode{tap[0].Node})
+	assert.Equal(t, len(nodes["missing"]), 0)
+}
+
+// test StoreMany reports the keys that do not fit in a full blob store
+func TestStoreManyFull(t *testing.T) {
+	node, err := tapestry.Start(tapestry.MakeID("1"), 0, "", tapestry.WithBlobQuota(0, 2), tapestry.WithEvictionPolicy(tapestry.RejectPolicy{}))
+	assert.Equal(t, err, nil)
+	defer tapestry.KillTapestries(node)
+
+	err = node.StoreMany(batchValues(3))
+	var batch *tapestry.BatchError
+	assert.Equal(t, errors.As(err, &batch), true)
+	assert.Equal(t, len(batch.Errors), 1)
+	var full *tapestry.StoreFullError
+	assert.Equal(t, errors.As(batch.Errors["key2"], &full), true)
+}
+
+// test the batch operations of a client
+func TestClientBatch(t *testing.T) {
+	tap, _ := tapestry.MakeTapestries(true, "1", "5", "9")
+	defer tapestry.KillTapestries(tap...)
+
+	client, _ := tapestry.Connect(tap[1].Node.Address)
+	values := batchValues(tapestry.BATCHSIZE + 1)
+	assert.Equal(t, client.StoreMany(values), nil)
+
+	keys := []string{"key0", "key1", fmt.Sprintf("key%v", tapestry.BATCHSIZE), "missing"}
+	blobs, err := client.GetMany(keys)
+	assert.Equal(t, len(blobs), 3)
+	assert.Equal(t, string(blobs["key1"]), "value1")
+	var batch *tapestry.BatchError
+	assert.Equal(t, errors.As(err, &batch), true)
+	var notFound *tapestry.NotFoundError
+	assert.Equal(t, errors.As(batch.Errors["missing"], &notFound), true)
+
+	replicas, err := client.LookupMany(keys)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, len(replicas["key0"]), 1)
+	assert.Equal(t, replicas["key0"][0].Address(), tap[1].Node.Address)
+	assert.Equal(t, len(replicas["missing"]), 0)
+}
+
+// test each blob of a batch sent to a node keeps its own metadata, and a batch repeating a key is rejected
+func TestStoreManyMetadata(t *testing.T) {
+	node, err := tapestry.Start(tapestry.MakeID("1"), 0, "")
+	assert.Equal(t, err, nil)
+	defer tapestry.KillTapestries(node)
+	cc, err := node.Node.ClientConn()
+	assert.Equal(t, err, nil)
+
+	rsp, err := cc.TapestryStoreManyCaller(context.Background(), &tapestry.DataBlobs{Blobs: []*tapestry.DataBlob{
+		{Key: "page", Data: []byte("<p>"), Metadata: &tapestry.MetadataMsg{ContentType: "text/html", Tags: map[string]string{"kind": "page"}}},
+		{Key: "data", Data: []byte("{}"), Metadata: &tapestry.MetadataMsg{ContentType: "application/json"}},
+	}})
+	assert.Equal(t, err, nil)
+	assert.Equal(t, len(rsp.Replies), 2)
+	page, err := node.Stat("page")
+	assert.Equal(t, err, nil)
+	assert.Equal(t, page.ContentType, "text/html")
+	assert.Equal(t, page.Tags, map[string]string{"kind": "page"})
+	data, err := node.Stat("data")
+	assert.Equal(t, err, nil)
+	assert.Equal(t, data.ContentType, "application/json")
+	assert.Equal(t, len(data.Tags), 0)
+
+	_, err = cc.TapestryStoreManyCaller(context.Background(), &tapestry.DataBlobs{Blobs: []*tapestry.DataBlob{
+		{Key: "twice", Data: []byte("1")},
+		{Key: "twice", Data: []byte("2")},
+	}})
+	assert.Equal(t, status.Code(err), codes.InvalidArgument)
+	_, err = node.Get("twice")
+	var notFound *tapestry.NotFoundError
+	assert.Equal(t, errors.As(err, &notFound), true)
+}
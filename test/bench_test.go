@@ -0,0 +1,105 @@
+package test
+
+import (
+	tapestry "tapestry/pkg"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// A bench target whose gets find nothing
+type missingTarget struct{}
+
+func (missingTarget) Store(key string, value []byte, opts ...tapestry.StoreOption) error {
+	return nil
+}
+
+func (missingTarget) Get(key string) ([]byte, error) {
+	return nil, &tapestry.NotFoundError{Key: key}
+}
+
+// test routing reports how many hops it took to reach the root
+func TestRoute(t *testing.T) {
+	tap, err := tapestry.MakeTapestries(true, "1", "5", "9")
+	assert.Equal(t, err, nil)
+	defer tapestry.KillTapestries(tap...)
+
+	key := keyRootedElsewhere(tap, tap[0])
+	root, hops, err := tap[0].Route(key)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, root, rootOf(tap, key).Node)
+	assert.Equal(t, hops, 1)
+
+	local := keysRootedAt(tap, tap[0], 1)[0]
+	root, hops, err = tap[0].Route(local)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, root, tap[0].Node)
+	assert.Equal(t, hops, 0)
+}
+
+// test a benchmark through the nodes of a cluster measures every operation and hop counts
+func TestBench(t *testing.T) {
+	cluster, err := tapestry.StartCluster(3)
+	assert.Equal(t, err, nil)
+	defer cluster.Down()
+	var targets []tapestry.BenchTarget
+	for _, node := range cluster.Nodes() {
+		targets = append(targets, node)
+	}
+
+	report, err := tapestry.Bench(targets, tapestry.BenchConfig{
+		Keys:        20,
+		Zipf:        1.5,
+		ValueSize:   64,
+		ReadRatio:   0.8,
+		LookupRatio: 0.5,
+		Concurrency: 3,
+		Duration:    300 * time.Millisecond,
+		Hops:        true,
+	})
+	assert.Equal(t, err, nil)
+	// Workers store the same popular keys at once, and a store that stays behind a concurrent one is refused
+	for kind := range report.Errors {
+		assert.Equal(t, kind, "store: version conflict")
+	}
+	for _, op := range []string{tapestry.BenchStore, tapestry.BenchGet, tapestry.BenchLookup} {
+		stats := report.Operations[op]
+		assert.Equal(t, stats.Count > 0, true)
+		assert.Equal(t, stats.Percentiles["p50"] <= stats.Percentiles["p99"], true)
+		assert.Equal(t, stats.Percentiles["p99"] <= stats.Max, true)
+	}
+	routed := 0
+	for hops, count := range report.Hops {
+		assert.Equal(t, hops >= 0 && hops <= 2, true)
+		routed += count
+	}
+	assert.Equal(t, routed, 20)
+}
+
+// test a benchmark through a client, and the breakdown of the errors it meets
+func TestBenchErrors(t *testing.T) {
+	tap, err := tapestry.MakeTapestries(true, "1", "5")
+	assert.Equal(t, err, nil)
+	defer tapestry.KillTapestries(tap...)
+	client, err := tapestry.Connect(tap[1].Addr())
+	assert.Equal(t, err, nil)
+
+	config := tapestry.BenchConfig{Keys: 5, ReadRatio: 0.5, Concurrency: 2, Duration: 200 * time.Millisecond, Hops: true}
+	report, err := tapestry.Bench([]tapestry.BenchTarget{client}, config)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, report.Operations[tapestry.BenchStore].Count > 0, true)
+	assert.Equal(t, report.Operations[tapestry.BenchGet].Errors, 0)
+	assert.Equal(t, len(report.Hops), 0)
+
+	config.ReadRatio = 1
+	report, err = tapestry.Bench([]tapestry.BenchTarget{missingTarget{}}, config)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, report.Operations[tapestry.BenchGet].Count > 0, true)
+	assert.Equal(t, report.Errors, map[string]int{"get: not found": report.Operations[tapestry.BenchGet].Errors})
+	assert.Equal(t, report.Operations[tapestry.BenchGet].Errors, report.Operations[tapestry.BenchGet].Count)
+
+	config.Zipf = 0.5
+	_, err = tapestry.Bench([]tapestry.BenchTarget{client}, config)
+	assert.NotEqual(t, err, nil)
+}
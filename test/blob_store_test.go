@@ -0,0 +1,168 @@
+package test
+
+import (
+	"errors"
+	tapestry "tapestry/pkg"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Stores a, b then reads a twice and b once, so a is used most often but b most recently
+func fillBlobStore(bs *tapestry.BlobStore) map[string]chan bool {
+	done := map[string]chan bool{"a": make(chan bool, 1), "b": make(chan bool, 1), "c": make(chan bool, 1)}
+	bs.Put("a", []byte("1"), tapestry.Metadata{}, done["a"])
+	bs.Put("b", []byte("2"), tapestry.Metadata{}, done["b"])
+	bs.Get("a")
+	bs.Get("a")
+	bs.Get("b")
+	return done
+}
+
+// test the LRU policy evicts the least recently used blob and unregisters it
+func TestBlobStoreLRU(t *testing.T) {
+	bs := tapestry.NewBlobStoreWithQuota(0, 2, tapestry.NewLRUPolicy())
+	done := fillBlobStore(bs)
+
+	evicted, err := bs.Put("c", []byte("3"), tapestry.Metadata{}, done["c"])
+	assert.Equal(t, err, nil)
+	assert.Equal(t, evicted, []string{"a"})
+	assert.Equal(t, bs.Keys(), []string{"b", "c"})
+	assert.Equal(t, len(done["a"]), 1)
+	assert.Equal(t, len(done["b"]), 0)
+}
+
+// test the LFU policy evicts the least frequently used blob
+func TestBlobStoreLFU(t *testing.T) {
+	bs := tapestry.NewBlobStoreWithQuota(0, 2, tapestry.NewLFUPolicy())
+	done := fillBlobStore(bs)
+
+	evicted, err := bs.Put("c", []byte("3"), tapestry.Metadata{}, done["c"])
+	assert.Equal(t, err, nil)
+	assert.Equal(t, evicted, []string{"b"})
+	assert.Equal(t, bs.Keys(), []string{"a", "c"})
+}
+
+// test the byte quota, replacing blobs in a full store and the reject policy
+func TestBlobStoreReject(t *testing.T) {
+	bs := tapestry.NewBlobStoreWithQuota(10, 0, tapestry.RejectPolicy{})
+	done := make(chan bool, 2)
+
+	_, err := bs.Put("a", []byte("123456"), tapestry.Metadata{}, done)
+	assert.Equal(t, err, nil)
+	assert.NotEqual(t, bs.Admits("b", 6), nil)
+	_, err = bs.Put("b", []byte("123456"), tapestry.Metadata{}, done)
+	var full *tapestry.StoreFullError
+	assert.Equal(t, errors.As(err, &full), true)
+	assert.Equal(t, full.Key, "b")
+
+	// Replacing a blob only needs room for the difference
+	assert.Equal(t, bs.Admits("a", 10), nil)
+	_, err = bs.Put("a", []byte("1234567890"), tapestry.Metadata{}, done)
+	assert.Equal(t, err, nil)
+	bytes, objects := bs.Usage()
+	assert.Equal(t, bytes, int64(10))
+	assert.Equal(t, objects, 1)
+
+	// Nothing can be evicted to fit a blob larger than the quota
+	lru := tapestry.NewBlobStoreWithQuota(10, 0, nil)
+	_, err = lru.Put("big", []byte("12345678901"), tapestry.Metadata{}, done)
+	assert.Equal(t, errors.As(err, &full), true)
+}
+
+// test replacing and deleting blobs does not wait for their done channels to be received from
+func TestBlobStoreRemoveDoesNotBlock(t *testing.T) {
+	bs := tapestry.NewBlobStore()
+	finished := make(chan bool)
+	go func() {
+		bs.Put("a", []byte("1"), tapestry.Metadata{}, make(chan bool))
+		bs.Put("a", []byte("2"), tapestry.Metadata{}, make(chan bool))
+		bs.Delete("a")
+		bs.Put("b", []byte("3"), tapestry.Metadata{}, nil)
+		bs.DeleteAll()
+		close(finished)
+	}()
+	select {
+	case <-finished:
+	case <-time.After(time.Second):
+		t.Errorf("BlobStore blocked on an unbuffered done channel")
+	}
+}
+
+// test evicting a blob from a node unregisters it from the root
+func TestStoreEviction(t *testing.T) {
+	node, err := tapestry.Start(tapestry.MakeID("1"), 0, "", tapestry.WithBlobQuota(0, 1))
+	assert.Equal(t, err, nil)
+	other, err := tapestry.Start(tapestry.MakeID("5"), 0, node.Addr())
+	assert.Equal(t, err, nil)
+	defer tapestry.KillTapestries(node, other)
+
+	node.Store("first", []byte("1"))
+	node.Store("second", []byte("2"))
+	time.Sleep(100 * time.Millisecond)
+
+	replicas, _ := other.Lookup("first")
+	assert.Equal(t, len(replicas), 0)
+	value, err := other.Get("second")
+	assert.Equal(t, err, nil)
+	assert.Equal(t, string(value), "2")
+}
+
+// test a full node that rejects blobs returns a StoreFullError to nodes and clients
+func TestStoreFull(t *testing.T) {
+	node, err := tapestry.Start(tapestry.MakeID("1"), 0, "", tapestry.WithBlobQuota(0, 1), tapestry.WithEvictionPolicy(tapestry.RejectPolicy{}))
+	assert.Equal(t, err, nil)
+	defer tapestry.KillTapestries(node)
+
+	assert.Equal(t, node.Store("first", []byte("1")), nil)
+	err = node.Store("second", []byte("2"))
+	var full *tapestry.StoreFullError
+	assert.Equal(t, errors.As(err, &full), true)
+	replicas, _ := node.Lookup("second")
+	assert.Equal(t, len(replicas), 0)
+
+	client, _ := tapestry.Connect(node.Addr())
+	err = client.Store("second", []byte("2"))
+	assert.Equal(t, errors.As(err, &full), true)
+	assert.Equal(t, full.Size, 1)
+}
+
+// test a blob that no longer fits once the rest of its batch is stored leaves the version stored
+// before it advertised
+func TestStoreFullKeepsOlderVersion(t *testing.T) {
+	node, err := tapestry.Start(tapestry.MakeID("1"), 0, "", tapestry.WithBlobQuota(10, 0), tapestry.WithEvictionPolicy(tapestry.RejectPolicy{}))
+	assert.Equal(t, err, nil)
+	defer tapestry.KillTapestries(node)
+
+	assert.Equal(t, node.Store("x", []byte("1234")), nil)
+	replicas, err := node.LookupVersions("x")
+	assert.Equal(t, err, nil)
+	assert.Equal(t, len(replicas), 1)
+	stored := replicas[0].Version
+
+	// Each blob fits on its own, but a is stored first and x then no longer fits
+	err = node.StoreMany(map[string][]byte{"a": []byte("12345"), "x": []byte("123456")})
+	var batch *tapestry.BatchError
+	assert.Equal(t, errors.As(err, &batch), true)
+	assert.Equal(t, len(batch.Errors), 1)
+	assert.NotEqual(t, batch.Errors["x"], nil)
+	time.Sleep(100 * time.Millisecond)
+
+	replicas, err = node.LookupVersions("x")
+	assert.Equal(t, err, nil)
+	assert.Equal(t, len(replicas), 1)
+	if len(replicas) == 1 {
+		assert.Equal(t, replicas[0].Version, stored)
+	}
+	value, err := node.Get("x")
+	assert.Equal(t, err, nil)
+	assert.Equal(t, string(value), "1234")
+	published := node.PublishedKeys()
+	assert.Equal(t, len(published), 2)
+	for _, status := range published {
+		if status.Key == "x" {
+			assert.Equal(t, status.Version, stored)
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package test
+
+import (
+	tapestry "tapestry/pkg"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// test a node that fetches a blob caches it and publishes itself as a soft replica
+func TestCacheFetchedBlob(t *testing.T) {
+	primary, err := tapestry.Start(tapestry.MakeID("1"), 0, "")
+	assert.Equal(t, err, nil)
+	reader, err := tapestry.Start(tapestry.MakeID("5"), 0, primary.Addr(), tapestry.WithCache(time.Minute))
+	assert.Equal(t, err, nil)
+	defer tapestry.KillTapestries(primary, reader)
+
+	assert.Equal(t, primary.Store("hot", []byte("value")), nil)
+	value, err := reader.Get("hot")
+	assert.Equal(t, err, nil)
+	assert.Equal(t, string(value), "value")
+	time.Sleep(100 * time.Millisecond)
+
+	replicas, err := primary.LookupVersions("hot")
+	assert.Equal(t, err, nil)
+	assert.Equal(t, len(replicas), 2)
+	assert.Equal(t, replicas[0].Node, primary.Node)
+	assert.Equal(t, replicas[0].Soft, false)
+	assert.Equal(t, replicas[1].Node, reader.Node)
+	assert.Equal(t, replicas[1].Soft, true)
+	assert.Equal(t, replicas[1].Version, replicas[0].Version)
+
+	metadata, err := reader.Stat("hot")
+	assert.Equal(t, err, nil)
+	assert.Equal(t, metadata.Cached, false)
+	assert.Equal(t, metadata.Expires.IsZero(), true)
+
+	// The cached copy is served even once the primary is gone
+	primary.Remove("hot")
+	value, err = reader.Get("hot")
+	assert.Equal(t, err, nil)
+	assert.Equal(t, string(value), "value")
+}
+
+// test a cached copy expires after its lease while the primary copy remains
+func TestCacheLease(t *testing.T) {
+	primary, err := tapestry.Start(tapestry.MakeID("1"), 0, "")
+	assert.Equal(t, err, nil)
+	reader, err := tapestry.Start(tapestry.MakeID("5"), 0, primary.Addr(), tapestry.WithCache(time.Second))
+	assert.Equal(t, err, nil)
+	defer tapestry.KillTapestries(primary, reader)
+
+	primary.Store("hot", []byte("value"))
+	reader.Get("hot")
+	time.Sleep(100 * time.Millisecond)
+	replicas, _ := primary.LookupVersions("hot")
+	assert.Equal(t, len(replicas), 2)
+
+	time.Sleep(1500 * time.Millisecond)
+
+	replicas, err = primary.LookupVersions("hot")
+	assert.Equal(t, err, nil)
+	assert.Equal(t, len(replicas), 1)
+	assert.Equal(t, replicas[0].Node, primary.Node)
+	value, err := reader.Get("hot")
+	assert.Equal(t, err, nil)
+	assert.Equal(t, string(value), "value")
+}
+
+// test a node without a cache does not become a replica of the blobs it fetches
+func TestNoCache(t *testing.T) {
+	tap, _ := tapestry.MakeTapestries(true, "1", "5")
+	defer tapestry.KillTapestries(tap...)
+
+	tap[0].Store("hot", []byte("value"))
+	tap[1].Get("hot")
+	time.Sleep(100 * time.Millisecond)
+
+	replicas, _ := tap[0].LookupVersions("hot")
+	assert.Equal(t, len(replicas), 1)
+}
+
+// test a node fetches and caches a newer version instead of serving its stale cached copy
+func TestCacheNewerVersion(t *testing.T) {
+	primary, err := tapestry.Start(tapestry.MakeID("1"), 0, "")
+	assert.Equal(t, err, nil)
+	reader, err := tapestry.Start(tapestry.MakeID("5"), 0, primary.Addr(), tapestry.WithCache(time.Minute))
+	assert.Equal(t, err, nil)
+	defer tapestry.KillTapestries(primary, reader)
+
+	primary.Store("hot", []byte("old"))
+	reader.Get("hot")
+	time.Sleep(100 * time.Millisecond)
+	primary.Store("hot", []byte("new"))
+
+	value, err := reader.Get("hot")
+	assert.Equal(t, err, nil)
+	assert.Equal(t, string(value), "new")
+	time.Sleep(100 * time.Millisecond)
+
+	replicas, _ := primary.LookupVersions("hot")
+	assert.Equal(t, len(replicas), 2)
+	assert.Equal(t, replicas[1].Node, reader.Node)
+	assert.Equal(t, replicas[1].Version, replicas[0].Version)
+}
+
+// test a cached copy reports the expiry time of the blob, and keeps its lease apart
+func TestCacheKeepsExpiry(t *testing.T) {
+	primary, err := tapestry.Start(tapestry.MakeID("1"), 0, "")
+	assert.Equal(t, err, nil)
+	reader, err := tapestry.Start(tapestry.MakeID("5"), 0, primary.Addr(), tapestry.WithCache(time.Minute))
+	assert.Equal(t, err, nil)
+	defer tapestry.KillTapestries(primary, reader)
+
+	assert.Equal(t, primary.Store("hot", []byte("value"), tapestry.WithTTL(time.Hour)), nil)
+	stored, err := primary.Stat("hot")
+	assert.Equal(t, err, nil)
+	reader.Get("hot")
+	time.Sleep(100 * time.Millisecond)
+
+	// Only the cached copy is left to read the metadata from
+	primary.Remove("hot")
+	metadata, err := reader.Stat("hot")
+	assert.Equal(t, err, nil)
+	assert.Equal(t, metadata.Cached, true)
+	assert.Equal(t, metadata.Expires.Equal(stored.Expires), true)
+	assert.Equal(t, metadata.Lease.IsZero(), false)
+	assert.Equal(t, metadata.Lease.Before(time.Now().Add(time.Minute+time.Second)), true)
+
+	replicas, err := reader.LookupVersions("hot")
+	assert.Equal(t, err, nil)
+	for _, replica := range replicas {
+		if replica.Soft {
+			assert.Equal(t, replica.Expires.Equal(metadata.Lease), true)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package test
+
+import (
+	tapestry "tapestry/pkg"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// test the nodes of a cluster join into one tapestry
+func TestStartCluster(t *testing.T) {
+	cluster, err := tapestry.StartCluster(4)
+	assert.Equal(t, err, nil)
+	defer cluster.Down()
+
+	nodes := cluster.Nodes()
+	assert.Equal(t, len(nodes), 4)
+	for _, node := range nodes[1:] {
+		assert.Equal(t, hasRoutingTableNode(nodes[0], node.Node), true)
+	}
+	assert.Equal(t, nodes[1].Store("clustered", []byte("value")), nil)
+	value, err := nodes[3].Get("clustered")
+	assert.Equal(t, err, nil)
+	assert.Equal(t, value, []byte("value"))
+}
+
+// test nodes can be added to and killed from a cluster
+func TestClusterAddAndKill(t *testing.T) {
+	cluster, err := tapestry.StartCluster(2)
+	assert.Equal(t, err, nil)
+	defer cluster.Down()
+
+	added, err := cluster.Add()
+	assert.Equal(t, err, nil)
+	assert.Equal(t, len(cluster.Nodes()), 3)
+	assert.Equal(t, hasRoutingTableNode(cluster.Nodes()[0], added.Node), true)
+
+	_, err = cluster.Kill("")
+	assert.NotEqual(t, err, nil)
+	_, err = cluster.Kill("not hex")
+	assert.NotEqual(t, err, nil)
+	killed, err := cluster.Kill(added.ID()[:8])
+	assert.Equal(t, err, nil)
+	assert.Equal(t, killed, added)
+	assert.Equal(t, len(cluster.Nodes()), 2)
+	_, err = tapestry.SayHelloRPC(added.Addr(), tapestry.RemoteNode{})
+	assert.NotEqual(t, err, nil)
+
+	cluster.Down()
+	assert.Equal(t, len(cluster.Nodes()), 0)
+}
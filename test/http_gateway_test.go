@@ -0,0 +1,134 @@
+package test
+
+import (
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	tapestry "tapestry/pkg"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// test storing, fetching and removing an object through the HTTP gateway
+func TestHTTPGatewayObjects(t *testing.T) {
+	tap, _ := tapestry.MakeTapestries(true, "1", "5", "9")
+	defer tapestry.KillTapestries(tap...)
+	gateway := httptest.NewServer(tapestry.NewHTTPHandler(tap[1]))
+	defer gateway.Close()
+
+	rsp := doHTTP(t, http.MethodPut, gateway.URL+"/objects/dir/file", "hello world")
+	assert.Equal(t, rsp.StatusCode, http.StatusNoContent)
+
+	value, _ := tap[2].Get("dir/file")
+	assert.Equal(t, string(value), "hello world")
+
+	rsp = doHTTP(t, http.MethodGet, gateway.URL+"/objects/dir/file", "")
+	body, _ := ioutil.ReadAll(rsp.Body)
+	assert.Equal(t, rsp.StatusCode, http.StatusOK)
+	assert.Equal(t, string(body), "hello world")
+
+	rsp = doHTTP(t, http.MethodDelete, gateway.URL+"/objects/dir/file", "")
+	assert.Equal(t, rsp.StatusCode, http.StatusNoContent)
+	rsp = doHTTP(t, http.MethodDelete, gateway.URL+"/objects/dir/file", "")
+	assert.Equal(t, rsp.StatusCode, http.StatusNotFound)
+
+	rsp = doHTTP(t, http.MethodPost, gateway.URL+"/objects/dir/file", "")
+	assert.Equal(t, rsp.StatusCode, http.StatusMethodNotAllowed)
+}
+
+// test looking up replicas through the HTTP gateway
+func TestHTTPGatewayLocations(t *testing.T) {
+	tap, _ := tapestry.MakeTapestries(true, "1", "5")
+	defer tapestry.KillTapestries(tap...)
+	gateway := httptest.NewServer(tapestry.NewHTTPHandler(tap[0]))
+	defer gateway.Close()
+
+	rsp := doHTTP(t, http.MethodGet, gateway.URL+"/locations/missing", "")
+	assert.Equal(t, rsp.StatusCode, http.StatusNotFound)
+	rsp = doHTTP(t, http.MethodGet, gateway.URL+"/objects/missing", "")
+	assert.Equal(t, rsp.StatusCode, http.StatusNotFound)
+
+	tap[1].Store("key", []byte("value"))
+	rsp = doHTTP(t, http.MethodGet, gateway.URL+"/locations/key", "")
+	assert.Equal(t, rsp.StatusCode, http.StatusOK)
+
+	var locations struct {
+		Key      string
+		Replicas []tapestry.RemoteNode
+	}
+	json.NewDecoder(rsp.Body).Decode(&locations)
+	assert.Equal(t, locations.Key, "key")
+	assert.Equal(t, locations.Replicas, []tapestry.RemoteNode{tap[1].Node})
+}
+
+func doHTTP(t *testing.T, method string, url string, body string) *http.Response {
+	req, _ := http.NewRequest(method, url, strings.NewReader(body))
+	rsp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("%v %v failed: %v", method, url, err)
+	}
+	return rsp
+}
+
+// test conditional stores through the HTTP gateway
+func TestHTTPGatewayConditionalPut(t *testing.T) {
+	tap, _ := tapestry.MakeTapestries(true, "1", "5")
+	defer tapestry.KillTapestries(tap...)
+	gateway := httptest.NewServer(tapestry.NewHTTPHandler(tap[0]))
+	defer gateway.Close()
+
+	put := func(header string, value string, body string) *http.Response {
+		req, _ := http.NewRequest(http.MethodPut, gateway.URL+"/objects/key", strings.NewReader(body))
+		req.Header.Set(header, value)
+		rsp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("PUT failed: %v", err)
+		}
+		return rsp
+	}
+
+	rsp := put("If-None-Match", "*", "one")
+	assert.Equal(t, rsp.StatusCode, http.StatusNoContent)
+	etag := rsp.Header.Get("ETag")
+	rsp = put("If-None-Match", "*", "two")
+	assert.Equal(t, rsp.StatusCode, http.StatusPreconditionFailed)
+
+	rsp = put("If-Match", etag, "two")
+	assert.Equal(t, rsp.StatusCode, http.StatusNoContent)
+	rsp = put("If-Match", etag, "three")
+	assert.Equal(t, rsp.StatusCode, http.StatusPreconditionFailed)
+
+	rsp = doHTTP(t, http.MethodGet, gateway.URL+"/objects/key", "")
+	body, _ := ioutil.ReadAll(rsp.Body)
+	assert.Equal(t, string(body), "two")
+	assert.NotEqual(t, rsp.Header.Get("ETag"), etag)
+}
+
+// test the HTTP gateway rejects bodies over the maximum body size, with or without a length
+func TestHTTPGatewayBodyTooLarge(t *testing.T) {
+	node, err := tapestry.Start(tapestry.MakeID("1"), 0, "", tapestry.WithMaxBodySize(16))
+	assert.Equal(t, err, nil)
+	defer tapestry.KillTapestries(node)
+	gateway := httptest.NewServer(tapestry.NewHTTPHandler(node))
+	defer gateway.Close()
+
+	rsp := doHTTP(t, http.MethodPut, gateway.URL+"/objects/small", "sixteen bytes!!!")
+	assert.Equal(t, rsp.StatusCode, http.StatusNoContent)
+	rsp = doHTTP(t, http.MethodPut, gateway.URL+"/objects/large", "seventeen bytes!!")
+	assert.Equal(t, rsp.StatusCode, http.StatusRequestEntityTooLarge)
+
+	// A chunked body gives no length up front
+	req, _ := http.NewRequest(http.MethodPut, gateway.URL+"/objects/chunked", io.MultiReader(strings.NewReader(strings.Repeat("x", 64))))
+	rsp, err = http.DefaultClient.Do(req)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, rsp.StatusCode, http.StatusRequestEntityTooLarge)
+
+	_, err = node.Get("large")
+	assert.NotEqual(t, err, nil)
+	_, err = node.Get("chunked")
+	assert.NotEqual(t, err, nil)
+}
@@ -0,0 +1,147 @@
+package test
+
+import (
+	"net"
+	tapestry "tapestry/pkg"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// test fetch percentiles need enough samples, and then pick the right sample
+func TestFetchPercentile(t *testing.T) {
+	tracker := tapestry.NewLatencyTracker()
+	tracker.ObserveFetch(time.Millisecond)
+	_, ok := tracker.FetchPercentile(0.95)
+	assert.Equal(t, ok, false)
+
+	for i := 2; i <= 100; i++ {
+		tracker.ObserveFetch(time.Duration(i) * time.Millisecond)
+	}
+	latency, ok := tracker.FetchPercentile(0.95)
+	assert.Equal(t, ok, true)
+	assert.Equal(t, latency, 96*time.Millisecond)
+	latency, _ = tracker.FetchPercentile(1)
+	assert.Equal(t, latency, 100*time.Millisecond)
+}
+
+// test replicas are ranked newest first, then local, then by round trip time, then unmeasured
+func TestRankReplicas(t *testing.T) {
+	tracker := tapestry.NewLatencyTracker()
+	local := tapestry.RemoteNode{ID: tapestry.MakeID("1"), Address: "local:1"}
+	near := tapestry.RemoteNode{ID: tapestry.MakeID("2"), Address: "near:1"}
+	far := tapestry.RemoteNode{ID: tapestry.MakeID("3"), Address: "far:1"}
+	unknown := tapestry.RemoteNode{ID: tapestry.MakeID("4"), Address: "unknown:1"}
+	newest := tapestry.RemoteNode{ID: tapestry.MakeID("5"), Address: "newest:1"}
+	tracker.Observe(near.Address, time.Millisecond)
+	tracker.Observe(far.Address, 50*time.Millisecond)
+	tracker.Observe(newest.Address, time.Second)
+
+	replicas := []tapestry.Replica{
+		{Node: unknown, Version: 1},
+		{Node: far, Version: 1},
+		{Node: newest, Version: 2},
+		{Node: near, Version: 1},
+		{Node: local, Version: 1},
+	}
+	tracker.Rank(local, replicas)
+	var order []tapestry.RemoteNode
+	for _, replica := range replicas {
+		order = append(order, replica.Node)
+	}
+	assert.Equal(t, order, []tapestry.RemoteNode{newest, local, near, far, unknown})
+
+	// The average moves towards new samples
+	tracker.Observe(far.Address, 0)
+	rtt, _ := tracker.RTT(far.Address)
+	assert.Equal(t, rtt, 40*time.Millisecond)
+}
+
+// test each node keeps its own latencies, and only measures the nodes it looks up replicas on
+func TestLatenciesPerNode(t *testing.T) {
+	tap, _ := tapestry.MakeTapestries(true, "1", "5")
+	defer tapestry.KillTapestries(tap...)
+
+	tap[0].Latencies().Observe("elsewhere:1", time.Hour)
+	_, measured := tap[1].Latencies().RTT("elsewhere:1")
+	assert.Equal(t, measured, false)
+
+	// Joining and publishing are not measured, as they are not single round trips
+	tap[1].Store("measured", []byte("value"))
+	_, measured = tap[0].Latencies().RTT(tap[1].Addr())
+	assert.Equal(t, measured, false)
+
+	// Looking up the replica on tap[1] probes it in the background
+	_, err := tap[0].Lookup("measured")
+	assert.Equal(t, err, nil)
+	for deadline := time.Now().Add(time.Second); !measured && time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		_, measured = tap[0].Latencies().RTT(tap[1].Addr())
+	}
+	assert.Equal(t, measured, true)
+	_, measured = tap[1].Latencies().RTT(tap[0].Addr())
+	assert.Equal(t, measured, false)
+}
+
+// test Lookup puts the nearest of the replicas holding the newest version first
+func TestLookupNearest(t *testing.T) {
+	tap, _ := tapestry.MakeTapestries(true, "1", "5", "9")
+	defer tapestry.KillTapestries(tap...)
+
+	near := tapestry.RemoteNode{ID: tapestry.MakeID("3"), Address: "127.0.0.1:1"}
+	far := tapestry.RemoteNode{ID: tapestry.MakeID("7"), Address: "127.0.0.2:1"}
+	tap[0].Latencies().Observe(near.Address, time.Millisecond)
+	tap[0].Latencies().Observe(far.Address, time.Hour)
+	for _, node := range tap {
+		node.LocationsByKey.Register("spread", tapestry.Replica{Node: far, Version: 1}, tapestry.TIMEOUT)
+		node.LocationsByKey.Register("spread", tapestry.Replica{Node: near, Version: 1}, tapestry.TIMEOUT)
+	}
+
+	replicas, err := tap[0].Lookup("spread")
+	assert.Equal(t, err, nil)
+	assert.Equal(t, replicas, []tapestry.RemoteNode{near, far})
+}
+
+// test a hedged Get is answered by the second replica when the nearest one does not answer
+func TestHedgedGet(t *testing.T) {
+	primary, err := tapestry.Start(tapestry.MakeID("1"), 0, "")
+	assert.Equal(t, err, nil)
+	reader, err := tapestry.Start(tapestry.MakeID("5"), 0, primary.Addr(), tapestry.WithHedging(0.5))
+	assert.Equal(t, err, nil)
+	defer tapestry.KillTapestries(primary, reader)
+
+	// Accepts connections but never answers
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Equal(t, err, nil)
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+		}
+	}()
+	silent := tapestry.RemoteNode{ID: tapestry.MakeID("3"), Address: listener.Addr().String()}
+
+	primary.Store("hedged", []byte("value"))
+	for i := 0; i < tapestry.MINLATENCYSAMPLES; i++ {
+		reader.Get("hedged")
+	}
+	replicas, _ := reader.LookupVersions("hedged")
+	assert.Equal(t, len(replicas), 1)
+	reader.Latencies().Observe(silent.Address, 0)
+	reader.Latencies().Observe(primary.Addr(), time.Hour)
+	for _, node := range []*tapestry.Node{primary, reader} {
+		node.LocationsByKey.Register("hedged", tapestry.Replica{Node: silent, Version: replicas[0].Version}, tapestry.TIMEOUT)
+	}
+	nodes, _ := reader.Lookup("hedged")
+	assert.Equal(t, nodes[0], silent)
+
+	start := time.Now()
+	value, err := reader.Get("hedged")
+	assert.Equal(t, err, nil)
+	assert.Equal(t, string(value), "value")
+	assert.Equal(t, time.Since(start) < tapestry.GRPCTimeout/2, true)
+}
@@ -0,0 +1,123 @@
+package test
+
+import (
+	"fmt"
+	"sort"
+	tapestry "tapestry/pkg"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// test crawling discovers every node in the mesh
+func TestCrawlMesh(t *testing.T) {
+	tap, _ := tapestry.MakeTapestries(true, "1", "5", "9", "D")
+	defer tapestry.KillTapestries(tap...)
+
+	nodes, err := tapestry.CrawlMesh(tap[2].Node)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, len(nodes), 4)
+	for _, node := range tap {
+		assert.Equal(t, hasnode(nodes, node.Node), true)
+	}
+}
+
+// test listing keys across the mesh with pagination and prefixes
+func TestListKeys(t *testing.T) {
+	tap, _ := tapestry.MakeTapestries(true, "1", "5", "9", "D")
+	defer tapestry.KillTapestries(tap...)
+
+	expected := make([]string, 0)
+	for i := 0; i < 2*tapestry.KEYPAGESIZE+10; i++ {
+		key := fmt.Sprintf("key-%03d", i)
+		tap[i%len(tap)].Store(key, []byte("value"))
+		expected = append(expected, key)
+	}
+	tap[0].Store("other", []byte("value"))
+	sort.Strings(expected)
+
+	keys := make([]string, 0)
+	token := ""
+	pages := 0
+	for {
+		page, next, err := tap[1].ListKeys("key-", token)
+		assert.Equal(t, err, nil)
+		keys = append(keys, page...)
+		pages++
+		if next == "" {
+			break
+		}
+		token = next
+	}
+	assert.Equal(t, keys, expected)
+	assert.Equal(t, pages, 3)
+
+	client, _ := tapestry.Connect(tap[3].Node.Address)
+	keys, next, err := client.ListKeys("oth", "")
+	assert.Equal(t, err, nil)
+	assert.Equal(t, keys, []string{"other"})
+	assert.Equal(t, next, "")
+}
+
+// test later pages of a listing crawl the mesh again when a node of the first crawl is gone
+func TestListKeysNodeGoneDuringListing(t *testing.T) {
+	tap, _ := tapestry.MakeTapestries(true, "1", "5", "9", "D")
+	defer tapestry.KillTapestries(tap[0], tap[1], tap[2])
+
+	for i := 0; i < 2*tapestry.KEYPAGESIZE; i++ {
+		tap[0].Store(fmt.Sprintf("key-%03d", i), []byte("value"))
+	}
+	client, _ := tapestry.Connect(tap[1].Node.Address)
+	first, token, err := client.ListKeys("key-", "")
+	assert.Equal(t, err, nil)
+	assert.Equal(t, len(first), tapestry.KEYPAGESIZE)
+
+	tap[3].Kill()
+	second, _, err := client.ListKeys("key-", token)
+	assert.Equal(t, err, nil)
+	assert.NotEqual(t, len(second), 0)
+	assert.Equal(t, sort.StringsAreSorted(append(first, second...)), true)
+	assert.Equal(t, second[0] > token, true)
+}
+
+// test keys registered at two roots are only listed once
+func TestListKeysDeduplicates(t *testing.T) {
+	tap, _ := tapestry.MakeTapestries(true, "1", "5")
+	defer tapestry.KillTapestries(tap...)
+
+	tap[0].LocationsByKey.Register("split", tapestry.Replica{Node: tap[0].Node, Version: 1}, tapestry.TIMEOUT)
+	tap[1].LocationsByKey.Register("split", tapestry.Replica{Node: tap[1].Node, Version: 1}, tapestry.TIMEOUT)
+
+	keys, _, err := tap[0].ListKeys("", "")
+	assert.Equal(t, err, nil)
+	assert.Equal(t, keys, []string{"split"})
+}
+
+// test a key listing page without a limit holds up to KEYPAGESIZE keys, and a negative limit is rejected
+func TestListKeysLimit(t *testing.T) {
+	node, err := tapestry.Start(tapestry.MakeID("1"), 0, "")
+	assert.Equal(t, err, nil)
+	defer tapestry.KillTapestries(node)
+	for i := 0; i < tapestry.KEYPAGESIZE+1; i++ {
+		node.LocationsByKey.Register(fmt.Sprintf("key%03d", i), tapestry.Replica{Node: node.Node, Version: 1}, tapestry.TIMEOUT)
+	}
+	cc, err := node.Node.ClientConn()
+	assert.Equal(t, err, nil)
+
+	rsp, err := cc.ListKeysCaller(context.Background(), &tapestry.ListKeysRequest{Limit: 0})
+	assert.Equal(t, err, nil)
+	assert.Equal(t, len(rsp.Keys), tapestry.KEYPAGESIZE)
+	assert.Equal(t, rsp.NextPageToken, rsp.Keys[len(rsp.Keys)-1])
+	rsp, err = cc.ListKeysCaller(context.Background(), &tapestry.ListKeysRequest{Limit: 1000})
+	assert.Equal(t, err, nil)
+	assert.Equal(t, len(rsp.Keys), tapestry.KEYPAGESIZE)
+	_, err = cc.ListKeysCaller(context.Background(), &tapestry.ListKeysRequest{Limit: -1})
+	assert.Equal(t, status.Code(err), codes.InvalidArgument)
+
+	keys, more := node.LocationsByKey.Keys("", "", 0)
+	assert.Equal(t, len(keys), 0)
+	assert.Equal(t, more, false)
+}
@@ -0,0 +1,55 @@
+package test
+
+import (
+	tapestry "tapestry/pkg"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// test metadata stored with a blob can be fetched from other nodes
+func TestStatMetadata(t *testing.T) {
+	tap, _ := tapestry.MakeTapestries(true, "1", "5", "9")
+	defer tapestry.KillTapestries(tap...)
+
+	tags := map[string]string{"owner": "alice"}
+	err := tap[0].Store("look", []byte("cuzzo"), tapestry.WithContentType("text/plain"), tapestry.WithTags(tags))
+	assert.Equal(t, err, nil)
+
+	metadata, err := tap[2].Stat("look")
+	assert.Equal(t, err, nil)
+	assert.Equal(t, metadata.Size, 5)
+	assert.Equal(t, metadata.ContentType, "text/plain")
+	assert.Equal(t, metadata.Tags, tags)
+	assert.Equal(t, metadata.Created.IsZero(), false)
+	assert.Equal(t, metadata.Created.Equal(metadata.Modified), true)
+
+	client, _ := tapestry.Connect(tap[1].Node.Address)
+	clientMetadata, err := client.Stat("look")
+	assert.Equal(t, err, nil)
+	assert.Equal(t, clientMetadata.ContentType, metadata.ContentType)
+	assert.Equal(t, clientMetadata.Tags, metadata.Tags)
+	assert.Equal(t, clientMetadata.Modified.Equal(metadata.Modified), true)
+
+	_, err = tap[1].Stat("missing")
+	assert.NotEqual(t, err, nil)
+}
+
+// test overwriting a blob keeps its creation time and updates the rest of its metadata
+func TestStatOverwrite(t *testing.T) {
+	tap, _ := tapestry.MakeTapestries(true, "1", "5", "9")
+	defer tapestry.KillTapestries(tap...)
+
+	tap[0].Store("look", []byte("cuzzo"), tapestry.WithContentType("text/plain"))
+	first, _ := tap[1].Stat("look")
+
+	time.Sleep(10 * time.Millisecond)
+	tap[0].Store("look", []byte("{\"cuzzo\": true}"), tapestry.WithContentType("application/json"))
+	second, err := tap[1].Stat("look")
+	assert.Equal(t, err, nil)
+	assert.Equal(t, second.Size, 15)
+	assert.Equal(t, second.ContentType, "application/json")
+	assert.Equal(t, second.Created.Equal(first.Created), true)
+	assert.Equal(t, second.Modified.After(first.Modified), true)
+}
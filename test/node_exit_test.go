@@ -113,3 +113,113 @@ func TestNotifyLeave2_WithReplacement(t *testing.T) {
 func hasRoutingTableNode(node *tapestry.Node, node2 tapestry.RemoteNode) bool {
 	return node.Table.Contains(node2)
 }
+
+// test draining hands off the blobs stored on the node, and rejects new ones meanwhile
+func TestDrain_HandsOffBlobs(t *testing.T) {
+	tap, err := tapestry.MakeTapestries(true, "1", "5", "9")
+	assert.Equal(t, err, nil)
+	defer tapestry.KillTapestries(tap...)
+
+	drained := tap[1]
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, drained.Store(fmt.Sprintf("drained%v", i), []byte(fmt.Sprintf("value%v", i))), nil)
+	}
+	assert.Equal(t, drained.Drain(), nil)
+	assert.Equal(t, drained.Draining(), true)
+	_, ok := drained.Store("new", []byte("value")).(*tapestry.DrainingError)
+	assert.Equal(t, ok, true)
+	assert.NotEqual(t, drained.Drain(), nil)
+
+	assert.Equal(t, hasRoutingTableNode(tap[0], drained.Node), false)
+	assert.Equal(t, hasRoutingTableNode(tap[2], drained.Node), false)
+	for i := 0; i < 10; i++ {
+		value, err := tap[2].Get(fmt.Sprintf("drained%v", i))
+		assert.Equal(t, err, nil)
+		assert.Equal(t, value, []byte(fmt.Sprintf("value%v", i)))
+	}
+}
+
+// test draining only offers blobs to as many backup roots as the node is configured with
+func TestDrain_HandsOffBlobsToConfiguredBackupRoots(t *testing.T) {
+	var tap []*tapestry.Node
+	for i, id := range []string{"1", "5", "9"} {
+		connectTo := ""
+		if i > 0 {
+			connectTo = tap[0].Addr()
+		}
+		node, err := tapestry.Start(tapestry.MakeID(id), 0, connectTo, tapestry.WithBackupRoots(0))
+		assert.Equal(t, err, nil)
+		tap = append(tap, node)
+	}
+	defer tapestry.KillTapestries(tap...)
+
+	// A key whose first backup root from the drained node is tap[2], which is then gone
+	drained := tap[1]
+	key := ""
+	for i := 0; key == ""; i++ {
+		if candidate := fmt.Sprintf("drained%v", i); tapestry.Hash(candidate).IsNewRoute(tap[2].Node.ID, tap[0].Node.ID) {
+			key = candidate
+		}
+	}
+	assert.Equal(t, drained.Store(key, []byte("value")), nil)
+	tap[2].Kill()
+
+	err, ok := drained.Drain().(*tapestry.DrainError)
+	assert.Equal(t, ok, true)
+	if ok {
+		assert.NotEqual(t, err.Errors[key], nil)
+	}
+	_, getErr := tap[0].Get(key)
+	assert.NotEqual(t, getErr, nil)
+}
+
+// test draining hands off the registrations of the keys the node is root of, without backup roots
+func TestDrain_HandsOffRoots(t *testing.T) {
+	var tap []*tapestry.Node
+	for i, id := range []string{"1", "5", "9"} {
+		connectTo := ""
+		if i > 0 {
+			connectTo = tap[0].Addr()
+		}
+		node, err := tapestry.Start(tapestry.MakeID(id), 0, connectTo, tapestry.WithBackupRoots(0))
+		assert.Equal(t, err, nil)
+		tap = append(tap, node)
+	}
+	defer tapestry.KillTapestries(tap...)
+
+	drained := tap[1]
+	keys := keysRootedAt(tap, drained, 5)
+	for _, key := range keys {
+		assert.Equal(t, tap[0].Store(key, []byte("value")), nil)
+	}
+	assert.Equal(t, drained.Drain(), nil)
+
+	remaining := []*tapestry.Node{tap[0], tap[2]}
+	for _, key := range keys {
+		assert.Equal(t, rootOf(remaining, key).LocationsByKey.Get(key), []tapestry.RemoteNode{tap[0].Node})
+		replicas, err := tap[2].Lookup(key)
+		assert.Equal(t, err, nil)
+		assert.Equal(t, replicas, []tapestry.RemoteNode{tap[0].Node})
+	}
+}
+
+// test draining reports the backpointers that did not acknowledge that the node left
+func TestDrain_UnacknowledgedBackpointer(t *testing.T) {
+	tap, err := tapestry.MakeTapestries(true, "1", "5")
+	assert.Equal(t, err, nil)
+	defer tapestry.KillTapestries(tap...)
+
+	drained := tap[1]
+	assert.Equal(t, len(drained.Backpointers.Get(0)), 1)
+	tap[0].Kill()
+
+	err = drained.Drain()
+	drainErr, ok := err.(*tapestry.DrainError)
+	assert.Equal(t, ok, true)
+	if ok {
+		assert.Equal(t, drainErr.Acked, 0)
+		assert.Equal(t, drainErr.Notified, 1)
+		assert.Equal(t, len(drainErr.Errors), 0)
+	}
+	assert.Equal(t, drained.Draining(), true)
+}
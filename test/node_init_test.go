@@ -1,8 +1,10 @@
 package test
 
 import (
+	"errors"
 	"fmt"
 	"github.com/stretchr/testify/assert"
+	"net"
 	tapestry "tapestry/pkg"
 	"testing"
 	"time"
@@ -133,3 +135,54 @@ func hasnode(slice []tapestry.RemoteNode, item tapestry.RemoteNode) bool {
 	_, ok := set[item]
 	return ok
 }
+
+// test join rejected when another node already holds the same ID
+func TestJoinIDCollision(t *testing.T) {
+	t1, _ := tapestry.Start(tapestry.MakeID("21"), 0, "")
+	t2, _ := tapestry.Start(tapestry.MakeID("55"), 0, t1.Node.Address)
+	defer tapestry.KillTapestries(t1, t2)
+
+	t3, err := tapestry.Start(tapestry.MakeID("21"), 0, t2.Node.Address)
+
+	var collision *tapestry.IDCollisionError
+	assert.Nil(t, t3)
+	assert.Equal(t, errors.As(err, &collision), true)
+	assert.Equal(t, collision.Existing, t1.Node)
+	assert.Equal(t, hasRoutingTableNode(t2, t1.Node), true)
+}
+
+func TestRegistrationTimeout(t *testing.T) {
+	t1, _ := tapestry.Start(tapestry.MakeID("21"), 0, "", tapestry.WithRegistrationTimeout(200*time.Millisecond), tapestry.WithRepublishInterval(time.Hour))
+	defer tapestry.KillTapestries(t1)
+
+	assert.Equal(t, t1.Store("short", []byte("lived")), nil)
+	assert.Equal(t, t1.LocationsByKey.Get("short"), []tapestry.RemoteNode{t1.Node})
+	time.Sleep(400 * time.Millisecond)
+	assert.Equal(t, len(t1.LocationsByKey.Get("short")), 0)
+}
+
+func TestRPCTimeout(t *testing.T) {
+	// A server that accepts connections but never answers
+	lis, err := net.Listen("tcp", "localhost:0")
+	assert.Equal(t, err, nil)
+	defer lis.Close()
+	go func() {
+		for {
+			conn, err := lis.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+		}
+	}()
+
+	previous := tapestry.SetRPCTimeout(300 * time.Millisecond)
+	defer tapestry.SetRPCTimeout(previous)
+	assert.Equal(t, previous, tapestry.GRPCTimeout)
+	assert.Equal(t, tapestry.RPCTimeout(), 300*time.Millisecond)
+
+	start := time.Now()
+	_, err = tapestry.SayHelloRPC(lis.Addr().String(), tapestry.RemoteNode{})
+	assert.NotEqual(t, err, nil)
+	assert.Equal(t, time.Since(start) < tapestry.GRPCTimeout/2, true)
+}
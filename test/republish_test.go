@@ -0,0 +1,161 @@
+package test
+
+import (
+	"fmt"
+	"sync"
+	tapestry "tapestry/pkg"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const republishInterval = 200 * time.Millisecond
+
+// test registrations lost by the roots are restored at the next republish
+func TestRepublishRestoresRegistrations(t *testing.T) {
+	node, err := tapestry.Start(tapestry.MakeID("1"), 0, "", tapestry.WithRepublishInterval(republishInterval))
+	assert.Equal(t, err, nil)
+	other, err := tapestry.Start(tapestry.MakeID("5"), 0, node.Addr())
+	assert.Equal(t, err, nil)
+	defer tapestry.KillTapestries(node, other)
+
+	assert.Equal(t, node.StoreMany(batchValues(50)), nil)
+	for _, root := range []*tapestry.Node{node, other} {
+		for i := 0; i < 50; i++ {
+			root.LocationsByKey.Unregister(fmt.Sprintf("key%v", i), node.Node)
+		}
+	}
+	time.Sleep(3 * republishInterval)
+
+	for i := 0; i < 50; i++ {
+		replicas, err := other.Lookup(fmt.Sprintf("key%v", i))
+		assert.Equal(t, err, nil)
+		assert.Equal(t, replicas, []tapestry.RemoteNode{node.Node})
+	}
+}
+
+// test a removed blob is not republished
+func TestRepublishStopsOnRemove(t *testing.T) {
+	node, err := tapestry.Start(tapestry.MakeID("1"), 0, "", tapestry.WithRepublishInterval(republishInterval))
+	assert.Equal(t, err, nil)
+	other, err := tapestry.Start(tapestry.MakeID("5"), 0, node.Addr())
+	assert.Equal(t, err, nil)
+	defer tapestry.KillTapestries(node, other)
+
+	node.Store("gone", []byte("soon"))
+	assert.Equal(t, node.Remove("gone"), true)
+	// As if the registration timed out
+	for _, root := range []*tapestry.Node{node, other} {
+		root.LocationsByKey.Unregister("gone", node.Node)
+	}
+	time.Sleep(3 * republishInterval)
+
+	replicas, err := other.Lookup("gone")
+	assert.Equal(t, err, nil)
+	assert.Equal(t, len(replicas), 0)
+}
+
+// test blobs whose root fails are registered with the new root after backing off
+func TestRepublishNewRoot(t *testing.T) {
+	node, err := tapestry.Start(tapestry.MakeID("1"), 0, "", tapestry.WithRepublishInterval(republishInterval))
+	assert.Equal(t, err, nil)
+	other, err := tapestry.Start(tapestry.MakeID("5"), 0, node.Addr())
+	assert.Equal(t, err, nil)
+	defer tapestry.KillTapestries(node)
+
+	values := batchValues(20)
+	assert.Equal(t, node.StoreMany(values), nil)
+	var moved []string
+	for key := range values {
+		if root, _ := node.FindRootOnRemoteNode(node.Node, tapestry.Hash(key)); root == other.Node {
+			moved = append(moved, key)
+		}
+	}
+	assert.Equal(t, len(moved) > 0, true)
+
+	tapestry.KillTapestries(other)
+	time.Sleep(tapestry.REPUBLISHBACKOFF + 3*republishInterval)
+
+	for _, key := range moved {
+		replicas, err := node.Lookup(key)
+		assert.Equal(t, err, nil)
+		assert.Equal(t, replicas, []tapestry.RemoteNode{node.Node})
+	}
+}
+
+// test PublishedKeys reports the root and last registration of each stored key
+func TestPublishedKeys(t *testing.T) {
+	node, err := tapestry.Start(tapestry.MakeID("1"), 0, "")
+	assert.Equal(t, err, nil)
+	other, err := tapestry.Start(tapestry.MakeID("5"), 0, node.Addr())
+	assert.Equal(t, err, nil)
+	defer tapestry.KillTapestries(node, other)
+
+	before := time.Now()
+	assert.Equal(t, node.StoreMany(batchValues(10)), nil)
+	statuses := node.PublishedKeys()
+	assert.Equal(t, len(statuses), 10)
+	for _, status := range statuses {
+		root, _ := node.FindRootOnRemoteNode(node.Node, tapestry.Hash(status.Key))
+		assert.Equal(t, *status.Root, root)
+		assert.Equal(t, status.Failures, 0)
+		assert.Equal(t, status.LastRegistered.Before(before), false)
+		assert.Equal(t, status.NextAttempt.After(status.LastRegistered), true)
+	}
+
+	client, err := tapestry.Connect(node.Addr())
+	assert.Equal(t, err, nil)
+	remote, err := client.PublishedKeys()
+	assert.Equal(t, err, nil)
+	assert.Equal(t, len(remote), len(statuses))
+	for i := range remote {
+		assert.Equal(t, remote[i].Key, statuses[i].Key)
+		assert.Equal(t, remote[i].Version, statuses[i].Version)
+		assert.Equal(t, *remote[i].Root, *statuses[i].Root)
+	}
+
+	node.Remove(statuses[0].Key)
+	assert.Equal(t, len(node.PublishedKeys()), 9)
+}
+
+// test the failure handler is called, and failures counted, once the root of a key is killed
+func TestPublishFailureHandler(t *testing.T) {
+	var mutex sync.Mutex
+	failed := make(map[string]int)
+	handler := func(status tapestry.PublishStatus, err error) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		failed[status.Key] = status.Failures
+	}
+	node, err := tapestry.Start(tapestry.MakeID("1"), 0, "",
+		tapestry.WithRepublishInterval(republishInterval), tapestry.WithPublishFailureHandler(handler))
+	assert.Equal(t, err, nil)
+	other, err := tapestry.Start(tapestry.MakeID("5"), 0, node.Addr())
+	assert.Equal(t, err, nil)
+	defer tapestry.KillTapestries(node)
+
+	values := batchValues(20)
+	assert.Equal(t, node.StoreMany(values), nil)
+	var moved []string
+	for key := range values {
+		if root, _ := node.FindRootOnRemoteNode(node.Node, tapestry.Hash(key)); root == other.Node {
+			moved = append(moved, key)
+		}
+	}
+	assert.Equal(t, len(moved) > 0, true)
+
+	tapestry.KillTapestries(other)
+	time.Sleep(2 * republishInterval)
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	for _, key := range moved {
+		assert.Equal(t, failed[key] > 0, true)
+	}
+	for _, status := range node.PublishedKeys() {
+		if failed[status.Key] == 0 {
+			assert.Equal(t, status.Failures, 0)
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package test
+
+import (
+	"encoding/xml"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	tapestry "tapestry/pkg"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type listBucketResult struct {
+	KeyCount              int
+	IsTruncated           bool
+	NextContinuationToken string
+	Contents              []struct {
+		Key  string
+		Size int
+	}
+	CommonPrefixes []struct {
+		Prefix string
+	}
+}
+
+// test PutObject, GetObject, HeadObject and DeleteObject through the S3 gateway
+func TestS3GatewayObjects(t *testing.T) {
+	tap, _ := tapestry.MakeTapestries(true, "1", "5")
+	defer tapestry.KillTapestries(tap...)
+	gateway := httptest.NewServer(tapestry.NewS3Gateway(tap[0], "bucket"))
+	defer gateway.Close()
+
+	rsp := doHTTP(t, http.MethodPut, gateway.URL+"/bucket/photos/cat.jpg", "meow")
+	assert.Equal(t, rsp.StatusCode, http.StatusOK)
+	assert.Equal(t, rsp.Header.Get("ETag"), "\"4a4be40c96ac6314e91d93f38043a634\"")
+
+	rsp = doHTTP(t, http.MethodGet, gateway.URL+"/bucket/photos/cat.jpg", "")
+	body, _ := ioutil.ReadAll(rsp.Body)
+	assert.Equal(t, rsp.StatusCode, http.StatusOK)
+	assert.Equal(t, string(body), "meow")
+
+	rsp = doHTTP(t, http.MethodHead, gateway.URL+"/bucket/photos/cat.jpg", "")
+	assert.Equal(t, rsp.StatusCode, http.StatusOK)
+	assert.Equal(t, rsp.ContentLength, int64(4))
+
+	rsp = doHTTP(t, http.MethodDelete, gateway.URL+"/bucket/photos/cat.jpg", "")
+	assert.Equal(t, rsp.StatusCode, http.StatusNoContent)
+	rsp = doHTTP(t, http.MethodGet, gateway.URL+"/bucket/photos/cat.jpg", "")
+	assert.Equal(t, rsp.StatusCode, http.StatusNotFound)
+
+	rsp = doHTTP(t, http.MethodGet, gateway.URL+"/other/photos/cat.jpg", "")
+	assert.Equal(t, rsp.StatusCode, http.StatusNotFound)
+}
+
+// test ListObjectsV2 with prefixes, delimiters and pagination through the S3 gateway
+func TestS3GatewayListObjects(t *testing.T) {
+	tap, _ := tapestry.MakeTapestries(true, "1", "5")
+	defer tapestry.KillTapestries(tap...)
+	gateway := httptest.NewServer(tapestry.NewS3Gateway(tap[1], "bucket"))
+	defer gateway.Close()
+
+	for _, key := range []string{"a/1", "a/2", "a/b/3", "b", "c"} {
+		doHTTP(t, http.MethodPut, gateway.URL+"/bucket/"+key, key)
+	}
+
+	result := listObjects(t, gateway.URL, url.Values{"delimiter": {"/"}})
+	assert.Equal(t, result.KeyCount, 3)
+	assert.Equal(t, len(result.CommonPrefixes), 1)
+	assert.Equal(t, result.CommonPrefixes[0].Prefix, "a/")
+
+	result = listObjects(t, gateway.URL, url.Values{"prefix": {"a/"}, "delimiter": {"/"}})
+	assert.Equal(t, result.KeyCount, 3)
+	assert.Equal(t, result.Contents[1].Key, "a/2")
+	assert.Equal(t, result.CommonPrefixes[0].Prefix, "a/b/")
+
+	keys := make([]string, 0)
+	token := ""
+	for {
+		query := url.Values{"max-keys": {"2"}}
+		if token != "" {
+			query.Set("continuation-token", token)
+		}
+		result = listObjects(t, gateway.URL, query)
+		for _, object := range result.Contents {
+			keys = append(keys, object.Key)
+		}
+		if !result.IsTruncated {
+			break
+		}
+		token = result.NextContinuationToken
+	}
+	assert.Equal(t, keys, []string{"a/1", "a/2", "a/b/3", "b", "c"})
+
+	result = listObjects(t, gateway.URL, url.Values{"max-keys": {"0"}})
+	assert.Equal(t, result.KeyCount, 0)
+	assert.Equal(t, result.IsTruncated, false)
+	assert.Equal(t, result.NextContinuationToken, "")
+}
+
+// test ListObjectsV2 lists objects stored through other nodes and gateways
+func TestS3GatewayListObjectsAcrossNodes(t *testing.T) {
+	tap, _ := tapestry.MakeTapestries(true, "1", "5", "9")
+	defer tapestry.KillTapestries(tap...)
+	first := httptest.NewServer(tapestry.NewS3Gateway(tap[0], "bucket"))
+	defer first.Close()
+	second := httptest.NewServer(tapestry.NewS3Gateway(tap[1], "bucket"))
+	defer second.Close()
+
+	doHTTP(t, http.MethodPut, first.URL+"/bucket/first", "1")
+	doHTTP(t, http.MethodPut, second.URL+"/bucket/second", "22")
+	assert.Equal(t, tap[2].Store("third", []byte("333")), nil)
+
+	result := listObjects(t, first.URL, url.Values{})
+	assert.Equal(t, result.KeyCount, 3)
+	for i, key := range []string{"first", "second", "third"} {
+		assert.Equal(t, result.Contents[i].Key, key)
+		assert.Equal(t, result.Contents[i].Size, i+1)
+	}
+}
+
+// test the S3 gateway rejects objects over the maximum body size
+func TestS3GatewayEntityTooLarge(t *testing.T) {
+	node, err := tapestry.Start(tapestry.MakeID("1"), 0, "", tapestry.WithMaxBodySize(4))
+	assert.Equal(t, err, nil)
+	defer tapestry.KillTapestries(node)
+	gateway := httptest.NewServer(tapestry.NewS3Gateway(node, "bucket"))
+	defer gateway.Close()
+
+	rsp := doHTTP(t, http.MethodPut, gateway.URL+"/bucket/large", "too large")
+	assert.Equal(t, rsp.StatusCode, http.StatusRequestEntityTooLarge)
+	rsp = doHTTP(t, http.MethodGet, gateway.URL+"/bucket/large", "")
+	assert.Equal(t, rsp.StatusCode, http.StatusNotFound)
+}
+
+func listObjects(t *testing.T, base string, query url.Values) (result listBucketResult) {
+	query.Set("list-type", "2")
+	rsp := doHTTP(t, http.MethodGet, base+"/bucket?"+query.Encode(), "")
+	assert.Equal(t, rsp.StatusCode, http.StatusOK)
+	xml.NewDecoder(rsp.Body).Decode(&result)
+	return
+}
@@ -0,0 +1,62 @@
+package test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	tapestry "tapestry/pkg"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// test joining when the first seed is unreachable
+func TestStartMultipleSeeds(t *testing.T) {
+	t1, _ := tapestry.Start(tapestry.MakeID("1"), 0, "")
+	t2, err := tapestry.Start(tapestry.MakeID("2"), 0, "", tapestry.WithSeeds("127.0.0.1:1", t1.Node.Address))
+	assert.Equal(t, err, nil)
+	defer tapestry.KillTapestries(t1, t2)
+
+	time.Sleep(200 * time.Millisecond)
+	assert.Equal(t, hasRoutingTableNode(t1, t2.Node), true)
+	assert.Equal(t, hasRoutingTableNode(t2, t1.Node), true)
+}
+
+// test seeds given as a comma-separated list with spaces are trimmed
+func TestStartSeedsWithSpaces(t *testing.T) {
+	t1, _ := tapestry.Start(tapestry.MakeID("1"), 0, "")
+	t2, err := tapestry.Start(tapestry.MakeID("2"), 0, "", tapestry.WithSeeds(strings.Split("127.0.0.1:1, "+t1.Node.Address+", ", ",")...))
+	assert.Equal(t, err, nil)
+	defer tapestry.KillTapestries(t1, t2)
+
+	assert.Equal(t, hasRoutingTableNode(t2, t1.Node), true)
+}
+
+// test joining through the addresses listed in a seed file
+func TestStartSeedFile(t *testing.T) {
+	t1, _ := tapestry.Start(tapestry.MakeID("1"), 0, "")
+
+	dir, _ := ioutil.TempDir("", "seeds")
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "seeds")
+	ioutil.WriteFile(path, []byte("# bootstrap nodes\n127.0.0.1:1\n\n"+t1.Node.Address+"\n"), 0644)
+
+	t2, err := tapestry.Start(tapestry.MakeID("2"), 0, "", tapestry.WithSeedFile(path))
+	assert.Equal(t, err, nil)
+	defer tapestry.KillTapestries(t1, t2)
+
+	assert.Equal(t, hasRoutingTableNode(t2, t1.Node), true)
+}
+
+// test the join fails after retrying when no seed is reachable
+func TestStartSeedsUnreachable(t *testing.T) {
+	start := time.Now()
+	t1, err := tapestry.Start(tapestry.MakeID("1"), 0, "127.0.0.1:1",
+		tapestry.WithSeeds("127.0.0.1:2"), tapestry.WithJoinRetry(3, 50*time.Millisecond))
+
+	assert.Nil(t, t1)
+	assert.NotEqual(t, err, nil)
+	assert.Equal(t, time.Since(start) >= 150*time.Millisecond, true)
+}
@@ -0,0 +1,82 @@
+package test
+
+import (
+	"encoding/json"
+	"strings"
+	tapestry "tapestry/pkg"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// test snapshots hold the routing state of a node, survive JSON, and match over RPC
+func TestSnapshot(t *testing.T) {
+	tap, err := tapestry.MakeTapestries(true, "1", "5")
+	assert.Equal(t, err, nil)
+	defer tapestry.KillTapestries(tap...)
+
+	key := keysRootedAt(tap, tap[1], 1)[0]
+	assert.Equal(t, tap[0].Store(key, []byte("snapshot")), nil)
+	time.Sleep(100 * time.Millisecond)
+
+	state := tap[1].Snapshot(true)
+	assert.Equal(t, state.Node, tap[1].Node)
+	assert.Equal(t, state.RoutingTable, tap[1].Table.Entries())
+	assert.Equal(t, state.Backpointers, []tapestry.BackpointerEntry{{Level: 0, Node: tap[0].Node}})
+	assert.Equal(t, len(state.Locations), 1)
+	assert.Equal(t, state.Locations[0].Key, key)
+	assert.Equal(t, state.Locations[0].Replicas[0].Node, tap[0].Node)
+	assert.Equal(t, len(tap[1].Snapshot(false).Locations), 0)
+
+	encoded, err := json.Marshal(state)
+	assert.Equal(t, err, nil)
+	var decoded tapestry.NodeState
+	assert.Equal(t, json.Unmarshal(encoded, &decoded), nil)
+	assert.Equal(t, decoded.RoutingTable, state.RoutingTable)
+	assert.Equal(t, decoded.Backpointers, state.Backpointers)
+	assert.Equal(t, decoded.Locations[0].Replicas[0].Version, state.Locations[0].Replicas[0].Version)
+
+	client, err := tapestry.Connect(tap[1].Addr())
+	assert.Equal(t, err, nil)
+	remote, err := client.State(true)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, remote.RoutingTable, state.RoutingTable)
+	assert.Equal(t, remote.Backpointers, state.Backpointers)
+	assert.Equal(t, remote.Locations, state.Locations)
+}
+
+// test crawling the mesh finds every node and routing link, and reports nodes that do not answer
+func TestCrawlTopology(t *testing.T) {
+	tap, err := tapestry.MakeTapestries(true, "1", "5", "9", "D")
+	assert.Equal(t, err, nil)
+	defer tapestry.KillTapestries(tap...)
+
+	topology, err := tapestry.CrawlTopology(tap[2].Addr(), false)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, len(topology.Nodes), 4)
+	assert.Equal(t, len(topology.Unreachable), 0)
+	links := 0
+	for i, node := range tap {
+		assert.Equal(t, topology.Nodes[i].Node, node.Node)
+		for _, entry := range node.Table.Entries() {
+			if entry.Node != node.Node {
+				links++
+			}
+		}
+	}
+	assert.Equal(t, len(topology.Links), links)
+
+	dot := topology.DOT()
+	assert.Equal(t, strings.HasPrefix(dot, "digraph tapestry {"), true)
+	for _, node := range tap {
+		assert.Equal(t, strings.Contains(dot, node.Node.Address), true)
+	}
+
+	tap[3].Kill()
+	topology, err = tapestry.CrawlTopology(tap[0].Addr(), false)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, len(topology.Nodes), 3)
+	assert.Equal(t, topology.Unreachable, []tapestry.RemoteNode{tap[3].Node})
+	assert.Equal(t, strings.Contains(topology.DOT(), "style=dashed"), true)
+}
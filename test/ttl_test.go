@@ -0,0 +1,83 @@
+package test
+
+import (
+	"errors"
+	tapestry "tapestry/pkg"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// test a blob stored with a TTL is deleted, unpublished and dropped by the root once it expires
+func TestStoreTTL(t *testing.T) {
+	tap, _ := tapestry.MakeTapestries(true, "1", "5", "9")
+	defer tapestry.KillTapestries(tap...)
+
+	err := tap[0].Store("session", []byte("token"), tapestry.WithTTL(2*time.Second))
+	assert.Equal(t, err, nil)
+
+	value, err := tap[1].Get("session")
+	assert.Equal(t, err, nil)
+	assert.Equal(t, string(value), "token")
+	metadata, _ := tap[1].Stat("session")
+	assert.Equal(t, metadata.Expires.IsZero(), false)
+	replicas, _ := tap[2].LookupVersions("session")
+	assert.Equal(t, len(replicas), 1)
+	assert.Equal(t, replicas[0].Expires.Equal(metadata.Expires), true)
+
+	time.Sleep(2500 * time.Millisecond)
+
+	replicas, err = tap[2].LookupVersions("session")
+	assert.Equal(t, err, nil)
+	assert.Equal(t, len(replicas), 0)
+	_, err = tap[1].Get("session")
+	var notFound *tapestry.NotFoundError
+	assert.Equal(t, errors.As(err, &notFound), true)
+	assert.Equal(t, tap[0].Remove("session"), false)
+}
+
+// test overwriting a blob that has a TTL with one that does not keeps the new blob
+func TestStoreTTLOverwrite(t *testing.T) {
+	tap, _ := tapestry.MakeTapestries(true, "1", "5")
+	defer tapestry.KillTapestries(tap...)
+
+	tap[0].Store("session", []byte("short"), tapestry.WithTTL(500*time.Millisecond))
+	tap[0].Store("session", []byte("forever"))
+	time.Sleep(time.Second)
+
+	value, err := tap[1].Get("session")
+	assert.Equal(t, err, nil)
+	assert.Equal(t, string(value), "forever")
+}
+
+// test a TTL given by a client applies on the node that stores the blob
+func TestClientStoreTTL(t *testing.T) {
+	tap, _ := tapestry.MakeTapestries(true, "1", "5")
+	defer tapestry.KillTapestries(tap...)
+
+	client, _ := tapestry.Connect(tap[0].Node.Address)
+	err := client.Store("session", []byte("token"), tapestry.WithTTL(time.Second))
+	assert.Equal(t, err, nil)
+	_, err = client.Get("session")
+	assert.Equal(t, err, nil)
+
+	time.Sleep(1500 * time.Millisecond)
+	_, err = client.Get("session")
+	assert.NotEqual(t, err, nil)
+	assert.Equal(t, len(tap[0].LocationsByKey.Get("session"))+len(tap[1].LocationsByKey.Get("session")), 0)
+}
+
+// test the location map does not register replicas that have already expired
+func TestLocationMapExpiry(t *testing.T) {
+	locations := tapestry.NewLocationMap()
+	node := tapestry.RemoteNode{ID: tapestry.MakeID("1"), Address: "localhost:1"}
+
+	locations.Register("past", tapestry.Replica{Node: node, Expires: time.Now().Add(-time.Second)}, tapestry.TIMEOUT)
+	assert.Equal(t, len(locations.Get("past")), 0)
+
+	locations.Register("soon", tapestry.Replica{Node: node, Expires: time.Now().Add(100 * time.Millisecond)}, tapestry.TIMEOUT)
+	assert.Equal(t, len(locations.Get("soon")), 1)
+	time.Sleep(200 * time.Millisecond)
+	assert.Equal(t, len(locations.Get("soon")), 0)
+}
@@ -0,0 +1,164 @@
+package test
+
+import (
+	"errors"
+	"sync"
+	tapestry "tapestry/pkg"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// test the hybrid logical clock always moves forward, including past observed versions
+func TestClock(t *testing.T) {
+	clock := tapestry.NewClock()
+	first := clock.Now()
+	second := clock.Now()
+	assert.Equal(t, second > first, true)
+	assert.Equal(t, first.Time().Sub(time.Now()) < time.Second, true)
+
+	future := tapestry.Version(time.Now().Add(time.Hour).UnixNano()/int64(time.Millisecond)) << tapestry.LOGICALBITS
+	clock.Observe(future)
+	assert.Equal(t, clock.Now() > future, true)
+
+	parsed, err := tapestry.ParseVersion(future.String())
+	assert.Equal(t, err, nil)
+	assert.Equal(t, parsed, future)
+
+	clock.Observe(tapestry.MAXVERSION)
+	assert.Equal(t, clock.Now(), tapestry.MAXVERSION)
+	assert.Equal(t, clock.Now(), tapestry.MAXVERSION)
+}
+
+// test lookups return versions newest first and Get fetches the newest value
+func TestStoreVersions(t *testing.T) {
+	tap, _ := tapestry.MakeTapestries(true, "1", "5", "9")
+	defer tapestry.KillTapestries(tap...)
+
+	tap[0].Store("look", []byte("old"))
+	tap[1].Store("look", []byte("new"))
+
+	replicas, err := tap[2].LookupVersions("look")
+	assert.Equal(t, err, nil)
+	assert.Equal(t, len(replicas), 2)
+	assert.Equal(t, replicas[0].Node, tap[1].Node)
+	assert.Equal(t, replicas[0].Version > replicas[1].Version, true)
+
+	value, _ := tap[2].Get("look")
+	assert.Equal(t, string(value), "new")
+	metadata, _ := tap[2].Stat("look")
+	assert.Equal(t, metadata.Version, replicas[0].Version)
+
+	client, _ := tapestry.Connect(tap[0].Node.Address)
+	clientReplicas, err := client.LookupVersions("look")
+	assert.Equal(t, err, nil)
+	assert.Equal(t, clientReplicas, replicas)
+	value, _ = client.Get("look")
+	assert.Equal(t, string(value), "new")
+}
+
+// test a store gets a version newer than one registered by a node whose clock is ahead
+func TestStoreObservesNewerVersion(t *testing.T) {
+	tap, _ := tapestry.MakeTapestries(true, "1", "5", "9")
+	defer tapestry.KillTapestries(tap...)
+
+	future := tapestry.Version(time.Now().Add(time.Hour).UnixNano()/int64(time.Millisecond)) << tapestry.LOGICALBITS
+	for _, node := range tap {
+		node.LocationsByKey.Register("look", tapestry.Replica{Node: tap[2].Node, Version: future}, tapestry.TIMEOUT)
+	}
+
+	tap[0].Store("look", []byte("cuzzo"))
+	replicas, _ := tap[1].LookupVersions("look")
+	assert.Equal(t, replicas[0].Node, tap[0].Node)
+	assert.Equal(t, replicas[0].Version > future, true)
+}
+
+// test conditional stores only succeed against the newest version
+func TestStoreIf(t *testing.T) {
+	tap, _ := tapestry.MakeTapestries(true, "1", "5", "9")
+	defer tapestry.KillTapestries(tap...)
+
+	first, err := tap[0].StoreIf("look", []byte("one"), 0)
+	assert.Equal(t, err, nil)
+
+	_, err = tap[1].StoreIf("look", []byte("two"), 0)
+	var conflict *tapestry.VersionConflictError
+	assert.Equal(t, errors.As(err, &conflict), true)
+	assert.Equal(t, conflict.Current, first)
+
+	second, err := tap[1].StoreIf("look", []byte("two"), first)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, second > first, true)
+
+	client, _ := tapestry.Connect(tap[2].Node.Address)
+	_, err = client.StoreIf("look", []byte("three"), first)
+	assert.Equal(t, errors.As(err, &conflict), true)
+	assert.Equal(t, conflict.Current, second)
+
+	third, err := client.StoreIf("look", []byte("three"), second)
+	assert.Equal(t, err, nil)
+	value, _ := tap[0].Get("look")
+	assert.Equal(t, string(value), "three")
+	metadata, _ := tap[0].Stat("look")
+	assert.Equal(t, metadata.Version, third)
+}
+
+// test only one of several concurrent conditional stores of the same version succeeds
+func TestStoreIfConcurrent(t *testing.T) {
+	tap, _ := tapestry.MakeTapestries(true, "1", "3", "5", "7", "9")
+	defer tapestry.KillTapestries(tap...)
+
+	initial, _ := tap[0].StoreIf("counter", []byte("0"), 0)
+
+	var wg sync.WaitGroup
+	var mutex sync.Mutex
+	succeeded := 0
+	for _, node := range tap {
+		wg.Add(1)
+		go func(node *tapestry.Node) {
+			defer wg.Done()
+			if _, err := node.StoreIf("counter", []byte(node.ID()), initial); err == nil {
+				mutex.Lock()
+				succeeded++
+				mutex.Unlock()
+			}
+		}(node)
+	}
+	wg.Wait()
+	assert.Equal(t, succeeded, 1)
+}
+
+// test a conditional store does not move the clock to the expected version it is given
+func TestStoreIfDoesNotObserveExpected(t *testing.T) {
+	tap, _ := tapestry.MakeTapestries(true, "1", "5")
+	defer tapestry.KillTapestries(tap...)
+
+	_, err := tap[0].StoreIf("look", []byte("one"), tapestry.MAXVERSION)
+	var conflict *tapestry.VersionConflictError
+	assert.Equal(t, errors.As(err, &conflict), true)
+
+	version, err := tap[0].StoreIf("other", []byte("two"), 0)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, version.Time().Sub(time.Now()) < time.Second, true)
+}
+
+// test a store fails, rather than being lost, when the root always has a newer version
+func TestStoreBehindNewestVersion(t *testing.T) {
+	tap, _ := tapestry.MakeTapestries(true, "1", "5", "9")
+	defer tapestry.KillTapestries(tap...)
+
+	for _, node := range tap {
+		node.LocationsByKey.Register("look", tapestry.Replica{Node: tap[2].Node, Version: tapestry.MAXVERSION}, tapestry.TIMEOUT)
+	}
+
+	err := tap[0].Store("look", []byte("lost"))
+	var conflict *tapestry.VersionConflictError
+	assert.Equal(t, errors.As(err, &conflict), true)
+	if conflict != nil {
+		assert.Equal(t, conflict.Current, tapestry.MAXVERSION)
+	}
+	replicas, err := tap[1].LookupVersions("look")
+	assert.Equal(t, err, nil)
+	assert.Equal(t, replicas, []tapestry.Replica{{Node: tap[2].Node, Version: tapestry.MAXVERSION}})
+}